@@ -0,0 +1,197 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Clone 复制表定义（用于 ALTER TABLE 在副本上修改，检查通过后再替换）
+func (t *TableSchema) Clone() *TableSchema {
+	clone := *t
+	clone.Columns = append([]Column(nil), t.Columns...)
+	clone.ForeignKeys = make([]*ForeignKey, len(t.ForeignKeys))
+	for i, fk := range t.ForeignKeys {
+		fkCopy := *fk
+		clone.ForeignKeys[i] = &fkCopy
+	}
+	clone.Checks = make([]*CheckConstraint, len(t.Checks))
+	for i, check := range t.Checks {
+		checkCopy := *check
+		clone.Checks[i] = &checkCopy
+	}
+	if t.View != nil {
+		viewCopy := *t.View
+		clone.View = &viewCopy
+	}
+	if t.Partitioning != nil {
+		clone.Partitioning = t.Partitioning.Clone()
+	}
+	return &clone
+}
+
+// ReplaceTable 用修改后的表定义替换同名的表，indexes 为表上全部的索引信息
+// 已删除的列所属的序列一起删除；调用者负责保证其他表的外键仍然有效
+func (c *Catalog) ReplaceTable(schema *TableSchema, indexes []*IndexInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.tables[schema.Name]; !exists {
+		return fmt.Errorf("table not found: %s", schema.Name)
+	}
+
+	c.tables[schema.Name] = schema
+
+	for name, info := range c.indexes {
+		if info.TableName == schema.Name {
+			delete(c.indexes, name)
+		}
+	}
+	for _, info := range indexes {
+		c.indexes[info.Name] = info
+	}
+
+	for name, seq := range c.sequences {
+		if column, ok := strings.CutPrefix(seq.OwnedBy, schema.Name+"."); ok && schema.GetColumnIndex(column) == -1 {
+			delete(c.sequences, name)
+		}
+	}
+
+	// 表的行已经重写，统计信息需要重新收集
+	delete(c.stats, schema.Name)
+
+	// 持久化
+	return c.save()
+}
+
+// RenameTable 重命名表，同时更新索引、外键和序列中对表的引用
+func (c *Catalog) RenameTable(oldName, newName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	schema, exists := c.tables[oldName]
+	if !exists {
+		return fmt.Errorf("table not found: %s", oldName)
+	}
+	if _, exists := c.tables[newName]; exists {
+		return fmt.Errorf("table already exists: %s", newName)
+	}
+	if _, exists := c.sequences[newName]; exists {
+		return fmt.Errorf("relation already exists: %s", newName)
+	}
+	if _, exists := c.views[newName]; exists {
+		return fmt.Errorf("relation already exists: %s", newName)
+	}
+	// 视图保存的是查询原文，被视图引用的表不能重命名
+	if view := c.dependentView(oldName); view != "" {
+		return fmt.Errorf("cannot rename table %s: view %s depends on it", oldName, view)
+	}
+
+	delete(c.tables, oldName)
+	schema.Name = newName
+	c.tables[newName] = schema
+
+	for _, info := range c.indexes {
+		if info.TableName == oldName {
+			info.TableName = newName
+		}
+	}
+	for _, table := range c.tables {
+		for _, fk := range table.ForeignKeys {
+			if fk.Table == oldName {
+				fk.Table = newName
+			}
+			if fk.RefTable == oldName {
+				fk.RefTable = newName
+			}
+		}
+	}
+	for _, seq := range c.sequences {
+		if column, ok := strings.CutPrefix(seq.OwnedBy, oldName+"."); ok {
+			seq.OwnedBy = newName + "." + column
+		}
+	}
+	for _, trigger := range c.triggers {
+		if trigger.Table == oldName {
+			trigger.Table = newName
+		}
+	}
+	if stats, exists := c.stats[oldName]; exists {
+		delete(c.stats, oldName)
+		stats.Table = newName
+		c.stats[newName] = stats
+	}
+
+	// 持久化
+	return c.save()
+}
+
+// RenameColumn 重命名列，同时更新主键、索引、外键和序列中对列的引用
+// checks 为表达式中的列名已经替换后的 CHECK 约束，generated 为各列替换后的生成列表达式
+func (c *Catalog) RenameColumn(tableName, oldName, newName string, checks []*CheckConstraint, generated []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	schema, exists := c.tables[tableName]
+	if !exists {
+		return fmt.Errorf("table not found: %s", tableName)
+	}
+	colIndex := schema.GetColumnIndex(oldName)
+	if colIndex == -1 {
+		return fmt.Errorf("column not found: %s", oldName)
+	}
+	if schema.GetColumnIndex(newName) != -1 {
+		return fmt.Errorf("column %s of table %s already exists", newName, tableName)
+	}
+
+	schema.Columns[colIndex].Name = newName
+	if schema.PrimaryKey == oldName {
+		schema.PrimaryKey = newName
+	}
+	schema.Checks = checks
+	for i := range schema.Columns {
+		schema.Columns[i].Generated = generated[i]
+	}
+	if schema.Partitioning != nil && schema.Partitioning.Column == oldName {
+		schema.Partitioning.Column = newName
+	}
+
+	for _, info := range c.indexes {
+		if info.TableName == tableName && info.ColumnName == oldName {
+			info.ColumnName = newName
+		}
+	}
+	for _, fk := range schema.ForeignKeys {
+		if fk.Column == oldName {
+			fk.Column = newName
+		}
+	}
+	for _, fk := range c.foreignKeysReferencing(tableName) {
+		if fk.RefColumn == oldName {
+			fk.RefColumn = newName
+		}
+	}
+	for _, seq := range c.sequences {
+		if seq.OwnedBy == tableName+"."+oldName {
+			seq.OwnedBy = tableName + "." + newName
+		}
+	}
+	if stats, exists := c.stats[tableName]; exists {
+		if column := stats.GetColumn(oldName); column != nil {
+			column.Column = newName
+		}
+	}
+
+	// 持久化
+	return c.save()
+}
+
+// RemoveSequence 删除序列（包括列所属的序列），用于修改表失败时清理已创建的序列
+func (c *Catalog) RemoveSequence(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.sequences, name)
+
+	// 持久化
+	return c.save()
+}
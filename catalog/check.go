@@ -0,0 +1,80 @@
+package catalog
+
+import "fmt"
+
+// CheckConstraint CHECK 约束
+type CheckConstraint struct {
+	Name string // 约束名
+	Expr string // 表达式原文，每次写入时解析并在新行上计算
+}
+
+// String 返回 CHECK 约束的定义（用于显示）
+func (c *CheckConstraint) String() string {
+	return fmt.Sprintf("CHECK (%s)", c.Expr)
+}
+
+// HasConstraint 判断表上是否已有同名的 CHECK 约束或外键
+func (t *TableSchema) HasConstraint(name string) bool {
+	for _, check := range t.Checks {
+		if check.Name == name {
+			return true
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		if fk.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddCheckConstraint 为已有的表添加 CHECK 约束（调用者负责检查已有的行）
+func (c *Catalog) AddCheckConstraint(tableName string, check *CheckConstraint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	schema, exists := c.tables[tableName]
+	if !exists {
+		return fmt.Errorf("table not found: %s", tableName)
+	}
+	if schema.HasConstraint(check.Name) {
+		return fmt.Errorf("constraint %s for table %s already exists", check.Name, tableName)
+	}
+
+	schema.Checks = append(schema.Checks, check)
+
+	// 持久化
+	return c.save()
+}
+
+// DropConstraint 删除表上的 CHECK 约束或外键
+// 约束不存在且 ifExists 为 true 时返回 false
+func (c *Catalog) DropConstraint(tableName, name string, ifExists bool) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	schema, exists := c.tables[tableName]
+	if !exists {
+		return false, fmt.Errorf("table not found: %s", tableName)
+	}
+
+	for i, check := range schema.Checks {
+		if check.Name == name {
+			schema.Checks = append(schema.Checks[:i], schema.Checks[i+1:]...)
+			return true, c.save()
+		}
+	}
+	// 外键名在模式内唯一，元数据中带有表所在模式的前缀
+	schemaName, _ := SplitName(tableName)
+	for i, fk := range schema.ForeignKeys {
+		if fk.Name == name || fk.Name == QualifiedName(schemaName, name) {
+			schema.ForeignKeys = append(schema.ForeignKeys[:i], schema.ForeignKeys[i+1:]...)
+			return true, c.save()
+		}
+	}
+
+	if ifExists {
+		return false, nil
+	}
+	return false, fmt.Errorf("constraint %s of table %s does not exist", name, tableName)
+}
@@ -0,0 +1,195 @@
+package catalog
+
+import (
+	"fmt"
+	"godb/types"
+	"sort"
+	"strings"
+)
+
+// ShowCreate 根据元数据重新生成创建表、视图或物化视图的语句
+// 主键、UNIQUE 约束（排序规则与列一致的唯一索引）、外键和 CHECK 约束写在 CREATE TABLE 中，其他索引和触发器生成 CREATE INDEX 和 CREATE TRIGGER 语句
+func (c *Catalog) ShowCreate(name string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if view, exists := c.views[name]; exists {
+		return viewDDL("VIEW", view), nil
+	}
+
+	schema, exists := c.tables[name]
+	if !exists {
+		return "", fmt.Errorf("table not found: %s", name)
+	}
+
+	indexes := make([]*IndexInfo, 0)
+	for _, info := range c.indexes {
+		if info.TableName == name {
+			indexes = append(indexes, info)
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+
+	var ddl strings.Builder
+	if schema.View != nil {
+		ddl.WriteString(viewDDL("MATERIALIZED VIEW", schema.View))
+	} else {
+		ddl.WriteString(c.tableDDL(schema, indexes))
+	}
+
+	for _, info := range indexes {
+		if schema.View == nil && isConstraintIndex(schema, info) {
+			continue
+		}
+		ddl.WriteString("\n")
+		ddl.WriteString(indexDDL(schema, info))
+	}
+	for _, trigger := range c.tableTriggers(name) {
+		ddl.WriteString("\n")
+		ddl.WriteString(trigger.String())
+	}
+	return ddl.String(), nil
+}
+
+// tableDDL 生成 CREATE TABLE 语句（调用者需要持有锁）
+func (c *Catalog) tableDDL(schema *TableSchema, indexes []*IndexInfo) string {
+	lines := make([]string, 0, len(schema.Columns))
+	for _, column := range schema.Columns {
+		lines = append(lines, "  "+c.columnDDL(schema, column))
+	}
+
+	if schema.PrimaryKey != "" {
+		lines = append(lines, "  "+constraintPrefix(schema.PrimaryKeyIndex, schema.Name+"_pkey")+
+			fmt.Sprintf("PRIMARY KEY (%s)", schema.PrimaryKey))
+	}
+	for _, info := range indexes {
+		if info.Name != schema.PrimaryKeyIndex && isConstraintIndex(schema, info) {
+			lines = append(lines, "  "+constraintPrefix(info.Name, fmt.Sprintf("%s_%s_key", schema.Name, info.ColumnName))+
+				fmt.Sprintf("UNIQUE (%s)", info.ColumnName))
+		}
+	}
+	for _, fk := range schema.ForeignKeys {
+		lines = append(lines, fmt.Sprintf("  CONSTRAINT %s %s", BaseName(fk.Name), fk.String()))
+	}
+	for _, check := range schema.Checks {
+		lines = append(lines, fmt.Sprintf("  CONSTRAINT %s %s", check.Name, check.String()))
+	}
+
+	options := partitionDDL(schema)
+	if schema.Temporary {
+		if schema.OnCommit != "" {
+			options += " ON COMMIT " + schema.OnCommit
+		}
+		return fmt.Sprintf("CREATE TEMPORARY TABLE %s (\n%s\n)%s;", BaseName(schema.Name), strings.Join(lines, ",\n"), options)
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n)%s;", schema.Name, strings.Join(lines, ",\n"), options)
+}
+
+// partitionDDL 生成 PARTITION BY 子句，不分区的表返回空字符串
+func partitionDDL(schema *TableSchema) string {
+	partitioning := schema.Partitioning
+	if partitioning == nil {
+		return ""
+	}
+	clause := fmt.Sprintf(" PARTITION BY %s (%s)", partitioning.Method, partitioning.Column)
+	if partitioning.Method == "HASH" {
+		return clause + fmt.Sprintf(" PARTITIONS %d", len(partitioning.Partitions))
+	}
+
+	column := schema.Columns[schema.GetColumnIndex(partitioning.Column)]
+	partitions := make([]string, len(partitioning.Partitions))
+	for i, partition := range partitioning.Partitions {
+		partitions[i] = fmt.Sprintf("  PARTITION %s %s", partition.Name, partitioning.Bound(partition, column))
+	}
+	return clause + " (\n" + strings.Join(partitions, ",\n") + "\n)"
+}
+
+// columnDDL 生成列定义，所属序列的 nextval 默认值还原为 AUTO_INCREMENT（调用者需要持有锁）
+func (c *Catalog) columnDDL(schema *TableSchema, column Column) string {
+	def := column.Name + " " + column.DeclaredType()
+	if column.Type == types.TypeText && column.Collation != "" && column.Collation != types.CollationBinary {
+		def += " COLLATE " + string(column.Collation)
+	}
+	if column.Generated != "" {
+		def += " " + column.GeneratedClause()
+	}
+	if column.NotNull {
+		def += " NOT NULL"
+	}
+	if column.Default != "" {
+		if c.isAutoIncrement(schema, column) {
+			def += " AUTO_INCREMENT"
+		} else {
+			def += " DEFAULT " + column.Default
+		}
+	}
+	return def
+}
+
+// IsAutoIncrement 判断列是否是 AUTO_INCREMENT / SERIAL 列（默认值是它所属的序列的 nextval）
+func (c *Catalog) IsAutoIncrement(schema *TableSchema, column Column) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isAutoIncrement(schema, column)
+}
+
+// isAutoIncrement 判断列的默认值是否是它所属的序列的 nextval（调用者需要持有锁）
+func (c *Catalog) isAutoIncrement(schema *TableSchema, column Column) bool {
+	return c.autoIncrementSequence(schema, column) != nil
+}
+
+// autoIncrementSequence 返回默认值为其 nextval 的、列所属的序列，不是自增列时返回 nil（调用者需要持有锁）
+func (c *Catalog) autoIncrementSequence(schema *TableSchema, column Column) *Sequence {
+	for _, seq := range c.sequences {
+		if seq.OwnedBy == schema.Name+"."+column.Name && column.Default == fmt.Sprintf("nextval('%s')", seq.Name) {
+			return seq
+		}
+	}
+	return nil
+}
+
+// isConstraintIndex 判断唯一索引是否可以表示为主键或 UNIQUE 约束（排序规则与列一致）
+func isConstraintIndex(schema *TableSchema, info *IndexInfo) bool {
+	if !info.Unique {
+		return false
+	}
+	if info.Name == schema.PrimaryKeyIndex {
+		return true
+	}
+	colIndex := schema.GetColumnIndex(info.ColumnName)
+	if colIndex == -1 {
+		return false
+	}
+	return info.ColumnType != types.TypeText || info.Collation.String() == schema.Columns[colIndex].Collation.String()
+}
+
+// constraintPrefix 约束名与默认名不同时生成 CONSTRAINT name 前缀（约束名不带模式前缀，建表时加上表所在的模式）
+func constraintPrefix(name, defaultName string) string {
+	if name == "" || name == defaultName {
+		return ""
+	}
+	return "CONSTRAINT " + BaseName(name) + " "
+}
+
+// indexDDL 生成 CREATE INDEX 语句，排序规则与列不同时带 COLLATE
+func indexDDL(schema *TableSchema, info *IndexInfo) string {
+	unique := ""
+	if info.Unique {
+		unique = "UNIQUE "
+	}
+	collate := ""
+	if colIndex := schema.GetColumnIndex(info.ColumnName); colIndex != -1 && info.ColumnType == types.TypeText &&
+		info.Collation.String() != schema.Columns[colIndex].Collation.String() {
+		collate = " COLLATE " + info.Collation.String()
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s%s);", unique, BaseName(info.Name), info.TableName, info.ColumnName, collate)
+}
+
+// viewDDL 生成 CREATE [MATERIALIZED] VIEW 语句
+func viewDDL(kind string, view *View) string {
+	columns := ""
+	if len(view.Columns) > 0 {
+		columns = " (" + strings.Join(view.Columns, ", ") + ")"
+	}
+	return fmt.Sprintf("CREATE %s %s%s AS %s;", kind, view.Name, columns, view.Query)
+}
@@ -0,0 +1,155 @@
+package catalog
+
+import (
+	"fmt"
+	"godb/types"
+	"math"
+	"strings"
+)
+
+// EnumType 枚举类型定义
+type EnumType struct {
+	Name   string   // 类型名
+	Labels []string // 枚举标签，下标即存储的序号，同时也是比较顺序
+}
+
+// Ordinal 返回标签对应的序号
+func (t *EnumType) Ordinal(label string) (uint16, bool) {
+	for i, l := range t.Labels {
+		if l == label {
+			return uint16(i), true
+		}
+	}
+	return 0, false
+}
+
+// Label 返回序号对应的标签
+func (t *EnumType) Label(ordinal uint16) (string, bool) {
+	if int(ordinal) >= len(t.Labels) {
+		return "", false
+	}
+	return t.Labels[ordinal], true
+}
+
+// NewEnumColumn 创建枚举类型的列定义
+func NewEnumColumn(name string, enumType *EnumType) Column {
+	return Column{
+		Name:     name,
+		Type:     types.TypeEnum,
+		TypeName: enumType.Name,
+	}
+}
+
+// CreateEnumType 创建枚举类型
+func (c *Catalog) CreateEnumType(name string, labels []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name = strings.ToLower(name)
+	if _, exists := c.enumTypes[name]; exists {
+		return fmt.Errorf("type already exists: %s", name)
+	}
+	if _, err := ParseDataType(strings.ToUpper(name)); err == nil {
+		return fmt.Errorf("type name conflicts with built-in type: %s", name)
+	}
+
+	if len(labels) == 0 {
+		return fmt.Errorf("enum type %s must have at least one label", name)
+	}
+	if len(labels) > math.MaxUint16+1 {
+		return fmt.Errorf("too many labels for enum type %s", name)
+	}
+
+	seen := make(map[string]bool)
+	for _, label := range labels {
+		if seen[label] {
+			return fmt.Errorf("duplicate label for enum type %s: '%s'", name, label)
+		}
+		seen[label] = true
+	}
+
+	c.enumTypes[name] = &EnumType{
+		Name:   name,
+		Labels: labels,
+	}
+
+	// 持久化
+	return c.save()
+}
+
+// GetEnumType 获取枚举类型（类型名不区分大小写）
+func (c *Catalog) GetEnumType(name string) (*EnumType, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	enumType, exists := c.enumTypes[strings.ToLower(name)]
+	if !exists {
+		return nil, fmt.Errorf("type not found: %s", name)
+	}
+
+	return enumType, nil
+}
+
+// AddEnumLabel 向枚举类型追加标签
+// 已存储的行记录的是序号，所以新标签只能追加在末尾
+func (c *Catalog) AddEnumLabel(name, label string, ifNotExists bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	enumType, exists := c.enumTypes[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("type not found: %s", name)
+	}
+
+	if _, found := enumType.Ordinal(label); found {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("enum label already exists: '%s'", label)
+	}
+	if len(enumType.Labels) > math.MaxUint16 {
+		return fmt.Errorf("too many labels for enum type %s", enumType.Name)
+	}
+
+	enumType.Labels = append(enumType.Labels, label)
+
+	// 持久化
+	return c.save()
+}
+
+// DropEnumType 删除枚举类型
+func (c *Catalog) DropEnumType(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name = strings.ToLower(name)
+	if _, exists := c.enumTypes[name]; !exists {
+		return fmt.Errorf("type not found: %s", name)
+	}
+
+	// 检查是否仍有列在使用该类型
+	for _, table := range c.tables {
+		for _, col := range table.Columns {
+			if col.Type == types.TypeEnum && col.TypeName == name {
+				return fmt.Errorf("cannot drop type %s: column %s.%s depends on it", name, table.Name, col.Name)
+			}
+		}
+	}
+
+	delete(c.enumTypes, name)
+
+	// 持久化
+	return c.save()
+}
+
+// ListEnumTypes 列出所有枚举类型
+func (c *Catalog) ListEnumTypes() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.enumTypes))
+	for name := range c.enumTypes {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,114 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReferentialAction 外键的引用动作（被引用的行删除或更新时对引用行的处理）
+type ReferentialAction string
+
+const (
+	ActionNoAction   ReferentialAction = "NO ACTION"   // 语句结束时检查（可以延迟到提交时）
+	ActionRestrict   ReferentialAction = "RESTRICT"    // 立即检查，不能延迟
+	ActionCascade    ReferentialAction = "CASCADE"     // 级联删除或更新引用行
+	ActionSetNull    ReferentialAction = "SET NULL"    // 引用列设为 NULL
+	ActionSetDefault ReferentialAction = "SET DEFAULT" // 引用列设为默认值
+)
+
+// ParseReferentialAction 解析引用动作，空字符串表示 NO ACTION
+func ParseReferentialAction(s string) (ReferentialAction, error) {
+	switch action := ReferentialAction(s); action {
+	case "":
+		return ActionNoAction, nil
+	case ActionNoAction, ActionRestrict, ActionCascade, ActionSetNull, ActionSetDefault:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unsupported referential action: %s", s)
+	}
+}
+
+// ForeignKey 外键约束（只支持单列外键）
+type ForeignKey struct {
+	Name              string            // 约束名
+	Table             string            // 引用表（子表）
+	Column            string            // 引用列
+	RefTable          string            // 被引用表（父表）
+	RefColumn         string            // 被引用列，必须有唯一索引（主键或 UNIQUE）
+	OnDelete          ReferentialAction // 父表行删除时的动作
+	OnUpdate          ReferentialAction // 父表被引用列更新时的动作
+	Deferrable        bool              // 是否可以延迟到提交时检查
+	InitiallyDeferred bool              // 事务中默认是否延迟检查
+}
+
+// String 返回外键的定义（用于错误信息和显示）
+func (fk *ForeignKey) String() string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", fk.Column, fk.RefTable, fk.RefColumn)
+	if fk.OnDelete != "" && fk.OnDelete != ActionNoAction {
+		def += " ON DELETE " + string(fk.OnDelete)
+	}
+	if fk.OnUpdate != "" && fk.OnUpdate != ActionNoAction {
+		def += " ON UPDATE " + string(fk.OnUpdate)
+	}
+	if fk.Deferrable {
+		def += " DEFERRABLE"
+		if fk.InitiallyDeferred {
+			def += " INITIALLY DEFERRED"
+		}
+	}
+	return def
+}
+
+// GetForeignKeysReferencing 返回引用指定表的所有外键（包括表自身的自引用外键），按约束名排序
+func (c *Catalog) GetForeignKeysReferencing(tableName string) []*ForeignKey {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.foreignKeysReferencing(tableName)
+}
+
+// foreignKeysReferencing 返回引用指定表的所有外键（内部方法，需要调用者持有锁）
+func (c *Catalog) foreignKeysReferencing(tableName string) []*ForeignKey {
+	result := make([]*ForeignKey, 0)
+	for _, table := range c.tables {
+		for _, fk := range table.ForeignKeys {
+			if fk.RefTable == tableName {
+				result = append(result, fk)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// GetForeignKey 按约束名查找外键
+func (c *Catalog) GetForeignKey(name string) (*ForeignKey, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, table := range c.tables {
+		for _, fk := range table.ForeignKeys {
+			if fk.Name == name {
+				return fk, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("constraint not found: %s", name)
+}
+
+// ListForeignKeys 列出所有外键，按约束名排序
+func (c *Catalog) ListForeignKeys() []*ForeignKey {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]*ForeignKey, 0)
+	for _, table := range c.tables {
+		result = append(result, table.ForeignKeys...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
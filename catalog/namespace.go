@@ -0,0 +1,134 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultSchema 默认模式，其中的表、视图、序列和索引在元数据中使用不带前缀的名字
+const DefaultSchema = "public"
+
+// TempSchema 临时表所在的模式，解析不带模式的名字时总是最先查找（临时表遮蔽同名的表）
+const TempSchema = "pg_temp"
+
+// QualifiedName 返回模式中的对象在元数据中的名字：默认模式中为 name，其他模式中为 schema.name
+func QualifiedName(schema, name string) string {
+	if schema == "" || schema == DefaultSchema {
+		return name
+	}
+	return schema + "." + name
+}
+
+// SplitName 把元数据中的名字拆分为模式名和对象名
+func SplitName(name string) (string, string) {
+	if schema, base, ok := strings.Cut(name, "."); ok {
+		return schema, base
+	}
+	return DefaultSchema, name
+}
+
+// BaseName 返回不带模式前缀的对象名
+func BaseName(name string) string {
+	_, base := SplitName(name)
+	return base
+}
+
+// CreateSchema 创建模式
+func (c *Catalog) CreateSchema(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name == DefaultSchema || name == TempSchema || c.schemas[name] {
+		return fmt.Errorf("schema already exists: %s", name)
+	}
+	c.schemas[name] = true
+
+	// 持久化
+	return c.save()
+}
+
+// SchemaExists 判断模式是否存在
+func (c *Catalog) SchemaExists(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return name == DefaultSchema || name == TempSchema || c.schemas[name]
+}
+
+// DropSchema 删除空的模式，模式中还有对象时不能删除（DROP SCHEMA ... CASCADE 由执行器先删除其中的对象）
+func (c *Catalog) DropSchema(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name == DefaultSchema || name == TempSchema {
+		return fmt.Errorf("cannot drop schema %s", name)
+	}
+	if !c.schemas[name] {
+		return fmt.Errorf("schema not found: %s", name)
+	}
+	tables, views, sequences := c.schemaObjects(name)
+	if len(tables)+len(views)+len(sequences) > 0 {
+		return fmt.Errorf("cannot drop schema %s because other objects depend on it, use DROP SCHEMA ... CASCADE", name)
+	}
+
+	delete(c.schemas, name)
+
+	// 持久化
+	return c.save()
+}
+
+// ListSchemas 列出所有模式（包括 public）
+func (c *Catalog) ListSchemas() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := append([]string{DefaultSchema}, c.listSchemas()...)
+	sort.Strings(names)
+	return names
+}
+
+// SchemaObjects 返回模式中的表（包括物化视图）、视图和独立的序列（表的列所属的序列随表一起删除）
+func (c *Catalog) SchemaObjects(name string) ([]string, []string, []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.schemaObjects(name)
+}
+
+// schemaObjects 返回模式中的对象（内部方法，需要调用者持有锁）
+func (c *Catalog) schemaObjects(name string) ([]string, []string, []string) {
+	prefix := name + "."
+	tables := make([]string, 0)
+	for tableName := range c.tables {
+		if strings.HasPrefix(tableName, prefix) {
+			tables = append(tables, tableName)
+		}
+	}
+	views := make([]string, 0)
+	for viewName := range c.views {
+		if strings.HasPrefix(viewName, prefix) {
+			views = append(views, viewName)
+		}
+	}
+	sequences := make([]string, 0)
+	for seqName, seq := range c.sequences {
+		if strings.HasPrefix(seqName, prefix) && seq.OwnedBy == "" {
+			sequences = append(sequences, seqName)
+		}
+	}
+	sort.Strings(tables)
+	sort.Strings(views)
+	sort.Strings(sequences)
+	return tables, views, sequences
+}
+
+// listSchemas 返回 CREATE SCHEMA 创建的模式（内部方法，需要调用者持有锁）
+func (c *Catalog) listSchemas() []string {
+	names := make([]string, 0, len(c.schemas))
+	for name := range c.schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
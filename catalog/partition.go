@@ -0,0 +1,258 @@
+package catalog
+
+import (
+	"fmt"
+	"godb/storage"
+	"godb/types"
+	"hash/fnv"
+	"strings"
+)
+
+// Partitioning 分区表的分区方式，每个分区的行保存在自己的页链中
+type Partitioning struct {
+	Method     string       // 分区方式：RANGE、LIST 或 HASH
+	Column     string       // 分区键
+	Partitions []*Partition // 分区（RANGE 分区按上界升序）
+}
+
+// Partition 分区
+type Partition struct {
+	Name        string   // 分区名
+	FirstPageID uint32   // 分区第一个数据页 ID
+	LessThan    string   `json:",omitempty"` // RANGE 分区的上界（不含），值的文本形式
+	MaxValue    bool     `json:",omitempty"` // RANGE 分区是否没有上界
+	Values      []string `json:",omitempty"` // LIST 分区的值，值的文本形式
+	HasNull     bool     `json:",omitempty"` // LIST 分区是否包含 NULL
+}
+
+// Clone 复制分区方式
+func (p *Partitioning) Clone() *Partitioning {
+	clone := *p
+	clone.Partitions = make([]*Partition, len(p.Partitions))
+	for i, partition := range p.Partitions {
+		partitionCopy := *partition
+		partitionCopy.Values = append([]string(nil), partition.Values...)
+		clone.Partitions[i] = &partitionCopy
+	}
+	return &clone
+}
+
+// GetPartition 按名字查找分区，返回分区的序号，不存在时返回 -1
+func (p *Partitioning) GetPartition(name string) int {
+	for i, partition := range p.Partitions {
+		if partition.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Bound 返回分区边界的 VALUES 子句（godb_partitions 和 SHOW CREATE TABLE 使用）
+func (p *Partitioning) Bound(partition *Partition, column Column) string {
+	switch p.Method {
+	case "RANGE":
+		if partition.MaxValue {
+			return "VALUES LESS THAN (MAXVALUE)"
+		}
+		return fmt.Sprintf("VALUES LESS THAN (%s)", boundLiteral(partition.LessThan, column))
+	case "LIST":
+		values := make([]string, 0, len(partition.Values)+1)
+		for _, value := range partition.Values {
+			values = append(values, boundLiteral(value, column))
+		}
+		if partition.HasNull {
+			values = append(values, "NULL")
+		}
+		return fmt.Sprintf("VALUES IN (%s)", strings.Join(values, ", "))
+	}
+	return ""
+}
+
+// boundLiteral 把边界值写为字面量：数值和布尔值原样输出，其他类型写为字符串
+func boundLiteral(value string, column Column) string {
+	switch column.Type {
+	case types.TypeInt, types.TypeFloat, types.TypeBoolean:
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// Validate 检查分区定义：分区名不重复，边界值能转换为分区键的类型，
+// RANGE 分区的上界严格递增且只有最后一个分区可以没有上界，LIST 分区的值不重复
+func (p *Partitioning) Validate(column Column) error {
+	if len(p.Partitions) == 0 {
+		return fmt.Errorf("partitioned table must have at least one partition")
+	}
+	names := make(map[string]bool)
+	for _, partition := range p.Partitions {
+		if names[partition.Name] {
+			return fmt.Errorf("duplicate partition name: %s", partition.Name)
+		}
+		names[partition.Name] = true
+	}
+
+	switch p.Method {
+	case "RANGE":
+		var previous types.Value
+		for i, partition := range p.Partitions {
+			if partition.MaxValue {
+				if i != len(p.Partitions)-1 {
+					return fmt.Errorf("MAXVALUE can only be used in the last partition")
+				}
+				continue
+			}
+			bound, err := partitionValue(partition.LessThan, column)
+			if err != nil {
+				return fmt.Errorf("invalid bound of partition %s: %w", partition.Name, err)
+			}
+			if i > 0 && types.Compare(bound, previous, column.Collation) <= 0 {
+				return fmt.Errorf("bounds of RANGE partitions must be strictly increasing: partition %s", partition.Name)
+			}
+			previous = bound
+		}
+	case "LIST":
+		seen := make([]types.Value, 0)
+		hasNull := false
+		for _, partition := range p.Partitions {
+			if partition.HasNull {
+				if hasNull {
+					return fmt.Errorf("NULL is listed in more than one partition")
+				}
+				hasNull = true
+			}
+			for _, text := range partition.Values {
+				value, err := partitionValue(text, column)
+				if err != nil {
+					return fmt.Errorf("invalid value of partition %s: %w", partition.Name, err)
+				}
+				for _, other := range seen {
+					if types.Compare(value, other, column.Collation) == 0 {
+						return fmt.Errorf("value %s is listed in more than one partition", text)
+					}
+				}
+				seen = append(seen, value)
+			}
+		}
+	}
+	return nil
+}
+
+// PartitionFor 返回值所属的分区序号
+// RANGE 分区中 NULL 属于第一个分区，HASH 分区中 NULL 属于第一个分区，LIST 分区中 NULL 属于列出 NULL 的分区
+func (p *Partitioning) PartitionFor(value types.Value, column Column) (int, error) {
+	switch p.Method {
+	case "RANGE":
+		if value.IsNull() {
+			return 0, nil
+		}
+		for i, partition := range p.Partitions {
+			if partition.MaxValue {
+				return i, nil
+			}
+			bound, err := partitionValue(partition.LessThan, column)
+			if err != nil {
+				return 0, err
+			}
+			if types.Compare(value, bound, column.Collation) < 0 {
+				return i, nil
+			}
+		}
+	case "LIST":
+		for i, partition := range p.Partitions {
+			if value.IsNull() {
+				if partition.HasNull {
+					return i, nil
+				}
+				continue
+			}
+			for _, text := range partition.Values {
+				listed, err := partitionValue(text, column)
+				if err != nil {
+					return 0, err
+				}
+				if types.Compare(value, listed, column.Collation) == 0 {
+					return i, nil
+				}
+			}
+		}
+	case "HASH":
+		if value.IsNull() {
+			return 0, nil
+		}
+		hash, err := hashValue(value, column.Collation)
+		if err != nil {
+			return 0, err
+		}
+		return int(hash % uint64(len(p.Partitions))), nil
+	}
+
+	text := "NULL"
+	if !value.IsNull() {
+		text = value.String()
+	}
+	return 0, fmt.Errorf("no partition for %s = %s", p.Column, text)
+}
+
+// partitionValue 把边界值的文本转换为分区键的类型
+func partitionValue(text string, column Column) (types.Value, error) {
+	return types.Cast(types.NewTextValue(text), column.Type)
+}
+
+// hashValue 计算值的哈希（TEXT 按排序规则的键计算，排序规则下相等的值哈希相同）
+func hashValue(value types.Value, collation types.Collation) (uint64, error) {
+	hash := fnv.New64a()
+	data, err := value.Key(collation)
+	if err != nil {
+		return 0, err
+	}
+	hash.Write(data)
+	return hash.Sum64(), nil
+}
+
+// AllocateTableStorage 为表分配新的数据页并把第一页 ID 写入表定义：不分区的表一个页链，分区表每个分区一个页链
+func AllocateTableStorage(pager *storage.Pager, schema *TableSchema) (*storage.TableStorage, error) {
+	if schema.Partitioning == nil {
+		tableStorage, err := storage.NewTableStorage(pager, len(schema.Columns))
+		if err != nil {
+			return nil, err
+		}
+		schema.FirstPageID = tableStorage.GetFirstPageID()
+		return tableStorage, nil
+	}
+
+	for _, partition := range schema.Partitioning.Partitions {
+		if err := AllocatePartition(pager, schema, partition); err != nil {
+			return nil, err
+		}
+	}
+	schema.FirstPageID = schema.Partitioning.Partitions[0].FirstPageID
+	return CreateTableStorage(pager, schema)
+}
+
+// AllocatePartition 为分区分配第一个数据页
+func AllocatePartition(pager *storage.Pager, schema *TableSchema, partition *Partition) error {
+	tableStorage, err := storage.NewTableStorage(pager, len(schema.Columns))
+	if err != nil {
+		return err
+	}
+	partition.FirstPageID = tableStorage.GetFirstPageID()
+	return nil
+}
+
+// partitionedStorage 加载分区表的存储，插入的行按分区键选择分区
+func partitionedStorage(pager *storage.Pager, schema *TableSchema) *storage.TableStorage {
+	partitioning := schema.Partitioning
+	firstPageIDs := make([]uint32, len(partitioning.Partitions))
+	for i, partition := range partitioning.Partitions {
+		firstPageIDs[i] = partition.FirstPageID
+	}
+	colIndex := schema.GetColumnIndex(partitioning.Column)
+	column := schema.Columns[colIndex]
+	return storage.LoadPartitionedTableStorage(pager, firstPageIDs, len(schema.Columns), func(row *storage.Row) (int, error) {
+		partition, err := partitioning.PartitionFor(row.Values[colIndex], column)
+		if err != nil {
+			return 0, fmt.Errorf("table %s has %w", schema.Name, err)
+		}
+		return partition, nil
+	})
+}
@@ -0,0 +1,108 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableDefinition 表及其索引、所属序列、触发器和统计信息的元数据（删除表之前保存，回滚事务时用来恢复）
+type TableDefinition struct {
+	Schema    *TableSchema
+	Indexes   []*IndexInfo
+	Sequences []*Sequence
+	Triggers  []*Trigger
+	Stats     *TableStats
+}
+
+// GetTableDefinition 获取表及其索引、所属序列、触发器和统计信息的元数据
+func (c *Catalog) GetTableDefinition(name string) (*TableDefinition, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	schema, exists := c.tables[name]
+	if !exists {
+		return nil, fmt.Errorf("table not found: %s", name)
+	}
+
+	def := &TableDefinition{Schema: schema, Stats: c.stats[name]}
+	for _, info := range c.indexes {
+		if info.TableName == name {
+			def.Indexes = append(def.Indexes, info)
+		}
+	}
+	for _, seq := range c.sequences {
+		if strings.HasPrefix(seq.OwnedBy, name+".") {
+			def.Sequences = append(def.Sequences, seq)
+		}
+	}
+	for _, trigger := range c.triggers {
+		if trigger.Table == name {
+			def.Triggers = append(def.Triggers, trigger)
+		}
+	}
+	return def, nil
+}
+
+// RestoreTable 恢复删除的表及其索引、所属序列、触发器和统计信息
+func (c *Catalog) RestoreTable(def *TableDefinition) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := def.Schema.Name
+	if _, exists := c.tables[name]; exists {
+		return fmt.Errorf("table already exists: %s", name)
+	}
+	if _, exists := c.views[name]; exists {
+		return fmt.Errorf("relation already exists: %s", name)
+	}
+	for _, info := range def.Indexes {
+		if _, exists := c.indexes[info.Name]; exists {
+			return fmt.Errorf("index already exists: %s", info.Name)
+		}
+	}
+	for _, seq := range def.Sequences {
+		if _, exists := c.sequences[seq.Name]; exists {
+			return fmt.Errorf("sequence already exists: %s", seq.Name)
+		}
+	}
+	for _, trigger := range def.Triggers {
+		if _, exists := c.triggers[trigger.Name]; exists {
+			return fmt.Errorf("trigger already exists: %s", trigger.Name)
+		}
+	}
+
+	c.tables[name] = def.Schema
+	for _, info := range def.Indexes {
+		c.indexes[info.Name] = info
+	}
+	for _, seq := range def.Sequences {
+		c.sequences[seq.Name] = seq
+	}
+	for _, trigger := range def.Triggers {
+		c.triggers[trigger.Name] = trigger
+	}
+	if def.Stats != nil {
+		c.stats[name] = def.Stats
+	}
+
+	// 持久化
+	return c.save()
+}
+
+// RestoreIndex 恢复删除的索引
+func (c *Catalog) RestoreIndex(info *IndexInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.indexes[info.Name]; exists {
+		return fmt.Errorf("index already exists: %s", info.Name)
+	}
+	if _, exists := c.tables[info.TableName]; !exists {
+		return fmt.Errorf("table not found: %s", info.TableName)
+	}
+
+	c.indexes[info.Name] = info
+
+	// 持久化
+	return c.save()
+}
@@ -5,29 +5,185 @@ import (
 	"fmt"
 	"godb/storage"
 	"godb/types"
+	"math"
 	"os"
+	"strings"
 	"sync"
+	"unicode/utf8"
 )
 
 // Column 列定义
 type Column struct {
-	Name string          // 列名
-	Type types.DataType  // 数据类型
+	Name      string          // 列名
+	Type      types.DataType  // 存储类型
+	TypeName  string          // 声明的类型名（如 VARCHAR、SMALLINT；枚举列为枚举类型名）
+	Length    int             // 类型长度修饰（VARCHAR(n)/CHAR(n)），0 表示不限制
+	Unsigned  bool            // 是否为无符号整数
+	Collation types.Collation // TEXT 列的排序规则，空表示 binary
+	NotNull   bool            // 是否声明了 NOT NULL（主键列总是 NOT NULL）
+	Default   string          // DEFAULT 表达式的原文，空表示没有默认值（即 NULL）
+	Generated string          `json:",omitempty"` // 生成列的表达式原文，空表示不是生成列
+	Stored    bool            `json:",omitempty"` // 生成列的值是否写入行中（STORED），否则读取时计算（VIRTUAL）
+}
+
+// NewColumn 根据声明的类型创建列定义
+func NewColumn(name, typeName string, length int, unsigned bool) (Column, error) {
+	typeName = strings.ToUpper(typeName)
+	dataType, err := ParseDataType(typeName)
+	if err != nil {
+		return Column{}, err
+	}
+
+	col := Column{
+		Name:     name,
+		Type:     dataType,
+		TypeName: typeName,
+	}
+
+	switch dataType {
+	case types.TypeText:
+		if length < 0 {
+			return Column{}, fmt.Errorf("invalid length for %s: %d", typeName, length)
+		}
+		col.Length = length
+	case types.TypeInt:
+		col.Unsigned = unsigned
+	}
+
+	return col, nil
+}
+
+// SetCollation 设置 TEXT 列的排序规则
+func (c *Column) SetCollation(name string) error {
+	if c.Type != types.TypeText {
+		return fmt.Errorf("collation can only be specified for text columns: %s", c.Name)
+	}
+	collation, err := types.ParseCollation(name)
+	if err != nil {
+		return err
+	}
+	c.Collation = collation
+	return nil
+}
+
+// DeclaredType 返回列声明的类型（如 VARCHAR(10)、INT UNSIGNED）
+func (c Column) DeclaredType() string {
+	// 旧版本元数据没有记录声明类型
+	name := c.TypeName
+	if name == "" {
+		name = c.Type.String()
+	}
+	if c.Length > 0 {
+		name = fmt.Sprintf("%s(%d)", name, c.Length)
+	}
+	if c.Unsigned {
+		name += " UNSIGNED"
+	}
+	return name
+}
+
+// IsVirtual 判断列是否是读取时计算的生成列（行中不保存它的值）
+func (c Column) IsVirtual() bool {
+	return c.Generated != "" && !c.Stored
+}
+
+// GeneratedClause 返回生成列的定义（如 GENERATED ALWAYS AS (price * qty) STORED）
+func (c Column) GeneratedClause() string {
+	if c.Generated == "" {
+		return ""
+	}
+	kind := "VIRTUAL"
+	if c.Stored {
+		kind = "STORED"
+	}
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", c.Generated, kind)
+}
+
+// Validate 检查值是否满足列的约束（NOT NULL、文本长度、整数范围）
+func (c Column) Validate(v types.Value) error {
+	switch v.Type {
+	case types.TypeNull:
+		if c.NotNull {
+			return fmt.Errorf("null value in column %s violates not-null constraint", c.Name)
+		}
+	case types.TypeText:
+		if c.Length <= 0 {
+			return nil
+		}
+		text, _ := v.AsText()
+		// 按字符而不是字节计算长度
+		if utf8.RuneCountInString(text) > c.Length {
+			return fmt.Errorf("value too long for column %s %s", c.Name, c.DeclaredType())
+		}
+	case types.TypeInt:
+		intVal, _ := v.AsInt()
+		min, max := c.IntRange()
+		if intVal < min || intVal > max {
+			return fmt.Errorf("value %d out of range for column %s %s", intVal, c.Name, c.DeclaredType())
+		}
+	}
+	return nil
+}
+
+// IntRange 返回整数列的取值范围
+func (c Column) IntRange() (int64, int64) {
+	return intRange(c.TypeName, c.Unsigned)
+}
+
+// intRange 返回整数类型的取值范围
+func intRange(typeName string, unsigned bool) (int64, int64) {
+	var bits uint
+	switch typeName {
+	case "TINYINT":
+		bits = 8
+	case "SMALLINT":
+		bits = 16
+	case "MEDIUMINT":
+		bits = 24
+	case "INT", "INTEGER":
+		bits = 32
+	default:
+		// BIGINT 以及旧版本元数据中未记录类型名的列
+		if unsigned {
+			return 0, math.MaxInt64
+		}
+		return math.MinInt64, math.MaxInt64
+	}
+
+	if unsigned {
+		return 0, int64(1)<<bits - 1
+	}
+	return -(int64(1) << (bits - 1)), int64(1)<<(bits-1) - 1
 }
 
 // IndexInfo 索引信息
 type IndexInfo struct {
-	Name       string         // 索引名
-	TableName  string         // 表名
-	ColumnName string         // 列名
-	ColumnType types.DataType // 列类型
+	Name       string          // 索引名
+	TableName  string          // 表名
+	ColumnName string          // 列名
+	ColumnType types.DataType  // 列类型
+	Collation  types.Collation // 索引键的排序规则（TEXT 列）
+	Unique     bool            // 是否为唯一索引
+	RootPageID uint32          `json:",omitempty"` // B+ 树根节点的页 ID（0 表示索引还没有写入数据文件，启动时从表数据构建）
 }
 
 // TableSchema 表定义
 type TableSchema struct {
-	Name        string    // 表名
-	Columns     []Column  // 列定义
-	FirstPageID uint32    // 第一个数据页 ID
+	Name            string   // 表名
+	Columns         []Column // 列定义
+	FirstPageID     uint32   // 第一个数据页 ID
+	PrimaryKey      string   // 主键列，空表示没有主键
+	PrimaryKeyIndex string   // 主键使用的唯一索引名
+
+	ForeignKeys []*ForeignKey      // 外键约束
+	Checks      []*CheckConstraint // CHECK 约束
+
+	View *View // 物化视图的定义，nil 表示普通表
+
+	Temporary bool   `json:",omitempty"` // 是否是临时表（行保存在会话私有的临时文件中，不写入元数据文件）
+	OnCommit  string `json:",omitempty"` // 临时表在事务提交时的动作：DELETE ROWS 或 DROP，空表示保留行
+
+	Partitioning *Partitioning `json:",omitempty"` // 分区方式，nil 表示不分区
 }
 
 // GetColumnIndex 获取列索引
@@ -51,24 +207,45 @@ func (t *TableSchema) GetColumnType(columnName string) (types.DataType, error) {
 
 // Catalog 元数据管理器
 type Catalog struct {
-	tables   map[string]*TableSchema // 表名 -> 表定义
-	indexes  map[string]*IndexInfo   // 索引名 -> 索引信息
-	mu       sync.RWMutex
-	metaFile string // 元数据文件路径
+	tables    map[string]*TableSchema // 表名 -> 表定义
+	indexes   map[string]*IndexInfo   // 索引名 -> 索引信息
+	enumTypes map[string]*EnumType    // 类型名 -> 枚举类型
+	sequences map[string]*Sequence    // 序列名 -> 序列
+	views     map[string]*View        // 视图名 -> 视图
+	schemas   map[string]bool         // CREATE SCHEMA 创建的模式（不包括默认的 public）
+	triggers  map[string]*Trigger     // 触发器名 -> 触发器
+	stats     map[string]*TableStats  // 表名 -> ANALYZE 收集的统计信息
+	mu        sync.RWMutex
+	metaFile  string // 元数据文件路径
+
+	deferSave bool // 是否推迟写入元数据文件（事务进行中）
+	unsaved   bool // 推迟期间是否有修改没有写入
 }
 
 // CatalogData 用于序列化的数据结构
 type CatalogData struct {
-	Tables  map[string]*TableSchema `json:"tables"`
-	Indexes map[string]*IndexInfo   `json:"indexes"`
+	Tables    map[string]*TableSchema `json:"tables"`
+	Indexes   map[string]*IndexInfo   `json:"indexes"`
+	EnumTypes map[string]*EnumType    `json:"enum_types,omitempty"`
+	Sequences map[string]*Sequence    `json:"sequences,omitempty"`
+	Views     map[string]*View        `json:"views,omitempty"`
+	Schemas   []string                `json:"schemas,omitempty"`
+	Triggers  map[string]*Trigger     `json:"triggers,omitempty"`
+	Stats     map[string]*TableStats  `json:"stats,omitempty"`
 }
 
 // NewCatalog 创建元数据管理器
 func NewCatalog(metaFile string) (*Catalog, error) {
 	catalog := &Catalog{
-		tables:   make(map[string]*TableSchema),
-		indexes:  make(map[string]*IndexInfo),
-		metaFile: metaFile,
+		tables:    make(map[string]*TableSchema),
+		indexes:   make(map[string]*IndexInfo),
+		enumTypes: make(map[string]*EnumType),
+		sequences: make(map[string]*Sequence),
+		views:     make(map[string]*View),
+		schemas:   make(map[string]bool),
+		triggers:  make(map[string]*Trigger),
+		stats:     make(map[string]*TableStats),
+		metaFile:  metaFile,
 	}
 
 	// 加载元数据
@@ -83,23 +260,26 @@ func NewCatalog(metaFile string) (*Catalog, error) {
 }
 
 // CreateTable 创建表
-func (c *Catalog) CreateTable(name string, columns []Column, firstPageID uint32) error {
+func (c *Catalog) CreateTable(schema *TableSchema) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// 检查表是否已存在
-	if _, exists := c.tables[name]; exists {
-		return fmt.Errorf("table already exists: %s", name)
+	if _, exists := c.tables[schema.Name]; exists {
+		return fmt.Errorf("table already exists: %s", schema.Name)
+	}
+	if _, exists := c.sequences[schema.Name]; exists {
+		return fmt.Errorf("relation already exists: %s", schema.Name)
+	}
+	if _, exists := c.views[schema.Name]; exists {
+		return fmt.Errorf("relation already exists: %s", schema.Name)
 	}
 
-	// 创建表定义
-	schema := &TableSchema{
-		Name:        name,
-		Columns:     columns,
-		FirstPageID: firstPageID,
+	if schema.PrimaryKey != "" && schema.GetColumnIndex(schema.PrimaryKey) == -1 {
+		return fmt.Errorf("column not found: %s", schema.PrimaryKey)
 	}
 
-	c.tables[name] = schema
+	c.tables[schema.Name] = schema
 
 	// 持久化
 	return c.save()
@@ -123,12 +303,52 @@ func (c *Catalog) DropTable(name string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if _, exists := c.tables[name]; !exists {
+	schema, exists := c.tables[name]
+	if !exists {
 		return fmt.Errorf("table not found: %s", name)
 	}
 
+	// 被其他表的外键引用时不能删除
+	for _, fk := range c.foreignKeysReferencing(name) {
+		if fk.Table != name {
+			return fmt.Errorf("cannot drop table %s: foreign key %s on table %s references it", name, fk.Name, fk.Table)
+		}
+	}
+	// 被视图引用时也不能删除
+	if view := c.dependentView(name); view != "" {
+		kind := "table"
+		if schema.View != nil {
+			kind = "materialized view"
+		}
+		return fmt.Errorf("cannot drop %s %s: view %s depends on it", kind, name, view)
+	}
+
 	delete(c.tables, name)
 
+	// 同时删除表上的索引
+	for indexName, info := range c.indexes {
+		if info.TableName == name {
+			delete(c.indexes, indexName)
+		}
+	}
+
+	// 以及表的列所属的序列（SERIAL / AUTO_INCREMENT）
+	for seqName, seq := range c.sequences {
+		if strings.HasPrefix(seq.OwnedBy, name+".") {
+			delete(c.sequences, seqName)
+		}
+	}
+
+	// 和表上的触发器
+	for triggerName, trigger := range c.triggers {
+		if trigger.Table == name {
+			delete(c.triggers, triggerName)
+		}
+	}
+
+	// 和表的统计信息
+	delete(c.stats, name)
+
 	// 持久化
 	return c.save()
 }
@@ -147,11 +367,14 @@ func (c *Catalog) ListTables() []string {
 
 // save 保存元数据到文件（内部方法，需要调用者持有锁）
 func (c *Catalog) save() error {
-	catalogData := CatalogData{
-		Tables:  c.tables,
-		Indexes: c.indexes,
+	if c.deferSave {
+		c.unsaved = true
+		return nil
 	}
 
+	// 临时表不写入元数据文件
+	catalogData := c.persistentData()
+
 	data, err := json.MarshalIndent(catalogData, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal catalog: %w", err)
@@ -164,6 +387,26 @@ func (c *Catalog) save() error {
 	return nil
 }
 
+// DeferSave 推迟写入元数据文件，直到 EndDeferSave（事务开始时调用）
+// 事务中的 DDL 只修改内存中的元数据，进程在提交之前退出时元数据文件保持事务开始前的状态
+func (c *Catalog) DeferSave() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deferSave = true
+}
+
+// EndDeferSave 结束推迟，把推迟期间的修改写入元数据文件（事务提交或回滚之后调用）
+func (c *Catalog) EndDeferSave() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deferSave = false
+	if !c.unsaved {
+		return nil
+	}
+	c.unsaved = false
+	return c.save()
+}
+
 // Load 从文件加载元数据
 func (c *Catalog) Load() error {
 	c.mu.Lock()
@@ -185,6 +428,36 @@ func (c *Catalog) Load() error {
 	} else {
 		c.indexes = make(map[string]*IndexInfo)
 	}
+	if catalogData.EnumTypes != nil {
+		c.enumTypes = catalogData.EnumTypes
+	} else {
+		c.enumTypes = make(map[string]*EnumType)
+	}
+	if catalogData.Sequences != nil {
+		c.sequences = catalogData.Sequences
+	} else {
+		c.sequences = make(map[string]*Sequence)
+	}
+	if catalogData.Views != nil {
+		c.views = catalogData.Views
+	} else {
+		c.views = make(map[string]*View)
+	}
+	c.schemas = make(map[string]bool)
+	for _, name := range catalogData.Schemas {
+		c.schemas[name] = true
+	}
+	if catalogData.Triggers != nil {
+		c.triggers = catalogData.Triggers
+	} else {
+		c.triggers = make(map[string]*Trigger)
+	}
+	if catalogData.Stats != nil {
+		c.stats = catalogData.Stats
+	} else {
+		c.stats = make(map[string]*TableStats)
+	}
+	c.restoreSequences()
 
 	return nil
 }
@@ -192,16 +465,18 @@ func (c *Catalog) Load() error {
 // ParseDataType 从字符串解析数据类型
 func ParseDataType(typeStr string) (types.DataType, error) {
 	switch typeStr {
-	case "INT", "INTEGER", "BIGINT":
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT":
 		return types.TypeInt, nil
 	case "TEXT", "VARCHAR", "CHAR", "STRING":
 		return types.TypeText, nil
-	case "BOOLEAN", "BOOL", "TINYINT":
+	case "BOOLEAN", "BOOL":
 		return types.TypeBoolean, nil
 	case "FLOAT", "DOUBLE", "REAL":
 		return types.TypeFloat, nil
 	case "DATE", "DATETIME", "TIMESTAMP":
 		return types.TypeDate, nil
+	case "UUID":
+		return types.TypeUUID, nil
 	default:
 		return 0, fmt.Errorf("unsupported data type: %s", typeStr)
 	}
@@ -209,11 +484,14 @@ func ParseDataType(typeStr string) (types.DataType, error) {
 
 // CreateTableStorage 为表创建存储
 func CreateTableStorage(pager *storage.Pager, schema *TableSchema) (*storage.TableStorage, error) {
+	if schema.Partitioning != nil {
+		return partitionedStorage(pager, schema), nil
+	}
 	return storage.LoadTableStorage(pager, schema.FirstPageID, len(schema.Columns)), nil
 }
 
-// CreateIndex 创建索引
-func (c *Catalog) CreateIndex(name, tableName, columnName string) error {
+// CreateIndex 创建索引，collation 为空时使用列的排序规则
+func (c *Catalog) CreateIndex(name, tableName, columnName string, collation types.Collation, unique bool) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -234,12 +512,21 @@ func (c *Catalog) CreateIndex(name, tableName, columnName string) error {
 		return fmt.Errorf("column not found: %s", columnName)
 	}
 
+	column := table.Columns[colIndex]
+	if collation == "" {
+		collation = column.Collation
+	} else if column.Type != types.TypeText {
+		return fmt.Errorf("collation can only be specified for text columns: %s", columnName)
+	}
+
 	// 创建索引信息
 	indexInfo := &IndexInfo{
 		Name:       name,
 		TableName:  tableName,
 		ColumnName: columnName,
-		ColumnType: table.Columns[colIndex].Type,
+		ColumnType: column.Type,
+		Collation:  collation,
+		Unique:     unique,
 	}
 
 	c.indexes[name] = indexInfo
@@ -248,21 +535,61 @@ func (c *Catalog) CreateIndex(name, tableName, columnName string) error {
 	return c.save()
 }
 
+// SetIndexRootPage 记录索引的 B+ 树根节点页 ID
+func (c *Catalog) SetIndexRootPage(name string, rootPageID uint32) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, exists := c.indexes[name]
+	if !exists {
+		return fmt.Errorf("index not found: %s", name)
+	}
+	info.RootPageID = rootPageID
+
+	// 持久化
+	return c.save()
+}
+
 // DropIndex 删除索引
 func (c *Catalog) DropIndex(name string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if _, exists := c.indexes[name]; !exists {
+	info, exists := c.indexes[name]
+	if !exists {
 		return fmt.Errorf("index not found: %s", name)
 	}
 
+	// 主键索引随表一起删除
+	if table, ok := c.tables[info.TableName]; ok && table.PrimaryKeyIndex == name {
+		return fmt.Errorf("cannot drop index %s: it enforces the primary key of table %s", name, info.TableName)
+	}
+
+	// 外键依赖被引用列上的唯一索引
+	if info.Unique && !c.hasOtherUniqueIndex(info) {
+		for _, fk := range c.foreignKeysReferencing(info.TableName) {
+			if fk.RefColumn == info.ColumnName {
+				return fmt.Errorf("cannot drop index %s: foreign key %s on table %s depends on it", name, fk.Name, fk.Table)
+			}
+		}
+	}
+
 	delete(c.indexes, name)
 
 	// 持久化
 	return c.save()
 }
 
+// hasOtherUniqueIndex 判断索引所在的列上是否还有其他唯一索引（内部方法，需要调用者持有锁）
+func (c *Catalog) hasOtherUniqueIndex(info *IndexInfo) bool {
+	for _, other := range c.indexes {
+		if other != info && other.Unique && other.TableName == info.TableName && other.ColumnName == info.ColumnName {
+			return true
+		}
+	}
+	return false
+}
+
 // GetIndex 获取索引信息
 func (c *Catalog) GetIndex(name string) (*IndexInfo, error) {
 	c.mu.RLock()
@@ -0,0 +1,325 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// SequenceCacheSize nextval 每次持久化时预留的值的个数
+// 崩溃后从预留的最后一个值之后继续分配，已分配的值不会重复（但可能跳过未使用的预留值）
+const SequenceCacheSize = 32
+
+// Sequence 序列定义和当前状态
+type Sequence struct {
+	Name      string // 序列名
+	Increment int64  // 步长，不能为 0
+	MinValue  int64  // 最小值
+	MaxValue  int64  // 最大值
+	Start     int64  // 起始值
+	Cycle     bool   // 超出范围时是否从头开始
+	LastValue int64  // 最近分配的值（IsCalled 为 false 时是下一次分配的值）
+	IsCalled  bool   // LastValue 是否已经分配过
+	Reserved  int64  // 已持久化的预留值上限，重启后从这里之后继续分配
+	OwnedBy   string // 所属的列（table.column），删除表时一起删除，空表示独立的序列
+
+	remaining int // 内存中剩余的预留值个数，为 0 时需要重新预留并持久化
+}
+
+// NewSequence 创建序列定义，未声明的最小值、最大值和起始值按步长的方向取默认值
+func NewSequence(name string, increment int64, minValue, maxValue, start *int64, cycle bool) (*Sequence, error) {
+	if increment == 0 {
+		return nil, fmt.Errorf("INCREMENT must not be zero")
+	}
+
+	seq := &Sequence{Name: name, Increment: increment, Cycle: cycle}
+	if increment > 0 {
+		seq.MinValue, seq.MaxValue = 1, math.MaxInt64
+	} else {
+		seq.MinValue, seq.MaxValue = math.MinInt64, -1
+	}
+	if minValue != nil {
+		seq.MinValue = *minValue
+	}
+	if maxValue != nil {
+		seq.MaxValue = *maxValue
+	}
+	if seq.MinValue >= seq.MaxValue {
+		return nil, fmt.Errorf("MINVALUE (%d) must be less than MAXVALUE (%d)", seq.MinValue, seq.MaxValue)
+	}
+
+	seq.Start = seq.MinValue
+	if increment < 0 {
+		seq.Start = seq.MaxValue
+	}
+	if start != nil {
+		seq.Start = *start
+	}
+	if seq.Start < seq.MinValue || seq.Start > seq.MaxValue {
+		return nil, fmt.Errorf("START value (%d) must be between MINVALUE (%d) and MAXVALUE (%d)", seq.Start, seq.MinValue, seq.MaxValue)
+	}
+
+	seq.LastValue = seq.Start
+	seq.Reserved = seq.Start
+	return seq, nil
+}
+
+// String 返回序列的定义（用于显示）
+func (s *Sequence) String() string {
+	def := fmt.Sprintf("INCREMENT BY %d MINVALUE %d MAXVALUE %d START WITH %d", s.Increment, s.MinValue, s.MaxValue, s.Start)
+	if s.Cycle {
+		def += " CYCLE"
+	}
+	return def
+}
+
+// step 返回 value 之后的下一个值，超出范围时返回 false
+func (s *Sequence) step(value int64) (int64, bool) {
+	if s.Increment > 0 {
+		if value > s.MaxValue-s.Increment {
+			return 0, false
+		}
+	} else if value < s.MinValue-s.Increment {
+		return 0, false
+	}
+	return value + s.Increment, true
+}
+
+// CreateSequence 创建序列
+func (c *Catalog) CreateSequence(seq *Sequence) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.sequences[seq.Name]; exists {
+		return fmt.Errorf("sequence already exists: %s", seq.Name)
+	}
+	if _, exists := c.tables[seq.Name]; exists {
+		return fmt.Errorf("relation already exists: %s", seq.Name)
+	}
+	if _, exists := c.views[seq.Name]; exists {
+		return fmt.Errorf("relation already exists: %s", seq.Name)
+	}
+
+	c.sequences[seq.Name] = seq
+
+	// 持久化
+	return c.save()
+}
+
+// GetSequence 获取序列定义
+func (c *Catalog) GetSequence(name string) (*Sequence, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	seq, exists := c.sequences[name]
+	if !exists {
+		return nil, fmt.Errorf("sequence not found: %s", name)
+	}
+	return seq, nil
+}
+
+// DropSequence 删除序列，列所属的序列只能随表一起删除
+func (c *Catalog) DropSequence(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seq, exists := c.sequences[name]
+	if !exists {
+		return fmt.Errorf("sequence not found: %s", name)
+	}
+	if seq.OwnedBy != "" {
+		return fmt.Errorf("cannot drop sequence %s because column %s requires it", name, seq.OwnedBy)
+	}
+
+	delete(c.sequences, name)
+
+	// 持久化
+	return c.save()
+}
+
+// ListSequences 列出所有序列名，按名字排序
+func (c *Catalog) ListSequences() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.sequences))
+	for name := range c.sequences {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NextValue 分配序列的下一个值（不受事务回滚影响）
+// 预留的值用完时先把新的预留上限写入元数据文件，再返回分配的值
+func (c *Catalog) NextValue(name string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seq, exists := c.sequences[name]
+	if !exists {
+		return 0, fmt.Errorf("sequence not found: %s", name)
+	}
+
+	value := seq.LastValue
+	if seq.IsCalled {
+		next, ok := seq.step(seq.LastValue)
+		if !ok {
+			if !seq.Cycle {
+				if seq.Increment > 0 {
+					return 0, fmt.Errorf("nextval: reached maximum value of sequence %s (%d)", name, seq.MaxValue)
+				}
+				return 0, fmt.Errorf("nextval: reached minimum value of sequence %s (%d)", name, seq.MinValue)
+			}
+			next = seq.MinValue
+			if seq.Increment < 0 {
+				next = seq.MaxValue
+			}
+			// 从头开始时重新预留
+			seq.remaining = 0
+		}
+		value = next
+	}
+
+	if seq.remaining == 0 {
+		reserved, count := value, 1
+		for count < SequenceCacheSize {
+			next, ok := seq.step(reserved)
+			if !ok {
+				break
+			}
+			reserved = next
+			count++
+		}
+
+		oldLast, oldCalled, oldReserved := seq.LastValue, seq.IsCalled, seq.Reserved
+		seq.LastValue, seq.IsCalled, seq.Reserved = value, true, reserved
+		if err := c.saveSequences(); err != nil {
+			seq.LastValue, seq.IsCalled, seq.Reserved = oldLast, oldCalled, oldReserved
+			return 0, err
+		}
+		seq.remaining = count
+	}
+
+	seq.LastValue = value
+	seq.IsCalled = true
+	seq.remaining--
+	return value, nil
+}
+
+// AdvanceAutoIncrement 自增列写入的值不小于所属序列的下一个值时，让序列从该值之后继续分配（与 MySQL 的 AUTO_INCREMENT 一样）
+func (c *Catalog) AdvanceAutoIncrement(schema *TableSchema, column Column, value int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seq := c.autoIncrementSequence(schema, column)
+	if seq == nil || seq.Increment < 0 || value > seq.MaxValue {
+		return nil
+	}
+	next := seq.LastValue
+	if seq.IsCalled {
+		var ok bool
+		if next, ok = seq.step(seq.LastValue); !ok {
+			return nil
+		}
+	}
+	if value < next {
+		return nil
+	}
+
+	seq.LastValue, seq.IsCalled = value, true
+	// 仍在预留范围内时不需要持久化
+	if seq.remaining > 0 && value <= seq.Reserved {
+		seq.remaining = int((seq.Reserved - value) / seq.Increment)
+		return nil
+	}
+	seq.Reserved, seq.remaining = value, 0
+	return c.saveSequences()
+}
+
+// SetValue 设置序列的当前值，isCalled 为 false 时下一次 nextval 返回 value 本身
+func (c *Catalog) SetValue(name string, value int64, isCalled bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seq, exists := c.sequences[name]
+	if !exists {
+		return fmt.Errorf("sequence not found: %s", name)
+	}
+	if value < seq.MinValue || value > seq.MaxValue {
+		return fmt.Errorf("setval: value %d is out of bounds for sequence %s (%d..%d)", value, name, seq.MinValue, seq.MaxValue)
+	}
+
+	seq.LastValue = value
+	seq.IsCalled = isCalled
+	seq.Reserved = value
+	seq.remaining = 0
+
+	// 持久化
+	return c.saveSequences()
+}
+
+// saveSequences 持久化序列状态（内部方法，需要调用者持有锁）
+// 序列不受事务回滚影响，推迟写入元数据文件期间也立即写入：只更新文件中已有的序列，其他修改仍然推迟到事务结束
+func (c *Catalog) saveSequences() error {
+	if !c.deferSave {
+		return c.save()
+	}
+
+	data, err := os.ReadFile(c.metaFile)
+	if os.IsNotExist(err) {
+		// 元数据文件中还没有任何序列
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read catalog file: %w", err)
+	}
+	var saved CatalogData
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to unmarshal catalog: %w", err)
+	}
+	for name := range saved.Sequences {
+		if seq, exists := c.sequences[name]; exists {
+			saved.Sequences[name] = seq
+		}
+	}
+
+	data, err = json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	if err := os.WriteFile(c.metaFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write catalog file: %w", err)
+	}
+	return nil
+}
+
+// restoreSequences 加载元数据后恢复序列状态（内部方法，需要调用者持有锁）
+// 上次运行可能已经分配了预留范围内的任何值，所以从预留上限之后继续分配
+func (c *Catalog) restoreSequences() {
+	for _, seq := range c.sequences {
+		if seq.IsCalled {
+			seq.LastValue = seq.Reserved
+		}
+		seq.remaining = 0
+	}
+}
+
+// ReleaseSequenceReservations 正常关闭时收回未使用的预留值，下次启动从最近分配的值之后继续
+func (c *Catalog) ReleaseSequenceReservations() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.sequences) == 0 {
+		return nil
+	}
+	for _, seq := range c.sequences {
+		seq.Reserved = seq.LastValue
+		seq.remaining = 0
+	}
+
+	// 持久化
+	return c.save()
+}
@@ -0,0 +1,69 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TableStats ANALYZE 收集的表统计信息
+type TableStats struct {
+	Table      string         // 表名
+	RowCount   int64          // 行数
+	SampleSize int64          // 计算列统计信息时采样的行数
+	AnalyzedAt time.Time      // 收集统计信息的时间
+	Columns    []*ColumnStats // 各列的统计信息（按列的顺序）
+}
+
+// ColumnStats 列的统计信息
+type ColumnStats struct {
+	Column    string   // 列名
+	NullFrac  float64  // NULL 值所占的比例
+	Distinct  int64    // 不同的非 NULL 值的个数（由样本估算）
+	Histogram []string `json:",omitempty"` // 等深直方图的桶边界（升序，n 个桶有 n+1 个边界），值的文本形式
+}
+
+// GetColumn 获取列的统计信息，没有时返回 nil
+func (s *TableStats) GetColumn(name string) *ColumnStats {
+	for _, column := range s.Columns {
+		if column.Column == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// SetTableStats 保存表的统计信息（替换之前的统计信息）
+func (c *Catalog) SetTableStats(stats *TableStats) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.tables[stats.Table]; !exists {
+		return fmt.Errorf("table not found: %s", stats.Table)
+	}
+	c.stats[stats.Table] = stats
+
+	// 持久化
+	return c.save()
+}
+
+// GetTableStats 获取表的统计信息，没有执行过 ANALYZE 时返回 nil
+func (c *Catalog) GetTableStats(name string) *TableStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats[name]
+}
+
+// ListTableStats 列出所有表的统计信息（按表名排序）
+func (c *Catalog) ListTableStats() []*TableStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]*TableStats, 0, len(c.stats))
+	for _, stats := range c.stats {
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Table < result[j].Table })
+	return result
+}
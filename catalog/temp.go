@@ -0,0 +1,66 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+)
+
+// IsTemporaryName 判断元数据中的名字是否属于临时模式（临时表及其索引、所属序列和触发器都在临时模式中）
+func IsTemporaryName(name string) bool {
+	return strings.HasPrefix(name, TempSchema+".")
+}
+
+// ListTemporaryTables 列出所有临时表（按名字排序）
+func (c *Catalog) ListTemporaryTables() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0)
+	for name, schema := range c.tables {
+		if schema.Temporary {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// persistentData 返回要保存的元数据：去掉临时模式中的表、索引、序列、触发器和统计信息（内部方法，需要调用者持有锁）
+func (c *Catalog) persistentData() CatalogData {
+	data := CatalogData{
+		Tables:    make(map[string]*TableSchema, len(c.tables)),
+		Indexes:   make(map[string]*IndexInfo, len(c.indexes)),
+		EnumTypes: c.enumTypes,
+		Sequences: make(map[string]*Sequence, len(c.sequences)),
+		Views:     c.views,
+		Schemas:   c.listSchemas(),
+		Triggers:  make(map[string]*Trigger, len(c.triggers)),
+		Stats:     make(map[string]*TableStats, len(c.stats)),
+	}
+	for name, schema := range c.tables {
+		if !schema.Temporary {
+			data.Tables[name] = schema
+		}
+	}
+	for name, info := range c.indexes {
+		if !IsTemporaryName(info.TableName) {
+			data.Indexes[name] = info
+		}
+	}
+	for name, seq := range c.sequences {
+		if !IsTemporaryName(name) {
+			data.Sequences[name] = seq
+		}
+	}
+	for name, trigger := range c.triggers {
+		if !IsTemporaryName(trigger.Table) {
+			data.Triggers[name] = trigger
+		}
+	}
+	for name, stats := range c.stats {
+		if !IsTemporaryName(name) {
+			data.Stats[name] = stats
+		}
+	}
+	return data
+}
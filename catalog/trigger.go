@@ -0,0 +1,115 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Trigger 行级触发器：表的每一行插入、更新或删除之前（BEFORE）或之后（AFTER）执行触发器体中的语句
+type Trigger struct {
+	Name   string   // 触发器名（与表在同一模式中）
+	Table  string   // 表名
+	Timing string   // BEFORE 或 AFTER
+	Event  string   // INSERT、UPDATE 或 DELETE
+	Body   []string // 语句原文，其中的 NEW.column 和 OLD.column 在执行时替换为行的值
+
+	SearchPath []string `json:",omitempty"` // 创建触发器时的 search_path，执行时按它解析语句中的名字，空表示 public
+}
+
+// String 返回 CREATE TRIGGER 语句
+func (t *Trigger) String() string {
+	body := strings.Join(t.Body, "; ")
+	if len(t.Body) > 1 {
+		body = "BEGIN " + body + "; END"
+	}
+	return fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW %s;", BaseName(t.Name), t.Timing, t.Event, t.Table, body)
+}
+
+// CreateTrigger 创建触发器
+func (c *Catalog) CreateTrigger(trigger *Trigger) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.triggers[trigger.Name]; exists {
+		return fmt.Errorf("trigger already exists: %s", trigger.Name)
+	}
+	schema, exists := c.tables[trigger.Table]
+	if !exists {
+		return fmt.Errorf("table not found: %s", trigger.Table)
+	}
+	if schema.View != nil {
+		return fmt.Errorf("cannot create trigger on materialized view %s", trigger.Table)
+	}
+
+	c.triggers[trigger.Name] = trigger
+
+	// 持久化
+	return c.save()
+}
+
+// GetTrigger 获取触发器
+func (c *Catalog) GetTrigger(name string) (*Trigger, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	trigger, exists := c.triggers[name]
+	if !exists {
+		return nil, fmt.Errorf("trigger not found: %s", name)
+	}
+	return trigger, nil
+}
+
+// DropTrigger 删除触发器
+func (c *Catalog) DropTrigger(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.triggers[name]; !exists {
+		return fmt.Errorf("trigger not found: %s", name)
+	}
+	delete(c.triggers, name)
+
+	// 持久化
+	return c.save()
+}
+
+// GetTriggers 获取表上在 timing 和 event 时触发的触发器（按名字排序，依次执行）
+func (c *Catalog) GetTriggers(tableName, timing, event string) []*Trigger {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]*Trigger, 0)
+	for _, trigger := range c.triggers {
+		if trigger.Table == tableName && trigger.Timing == timing && trigger.Event == event {
+			result = append(result, trigger)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// HasTriggers 判断表上是否有触发器
+func (c *Catalog) HasTriggers(tableName string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, trigger := range c.triggers {
+		if trigger.Table == tableName {
+			return true
+		}
+	}
+	return false
+}
+
+// tableTriggers 获取表上的所有触发器，按名字排序（内部方法，需要调用者持有锁）
+func (c *Catalog) tableTriggers(tableName string) []*Trigger {
+	result := make([]*Trigger, 0)
+	for _, trigger := range c.triggers {
+		if trigger.Table == tableName {
+			result = append(result, trigger)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
@@ -0,0 +1,136 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+)
+
+// View 视图定义，查询时展开为保存的 SELECT 语句
+type View struct {
+	Name    string   // 视图名
+	Query   string   // SELECT 语句原文
+	Columns []string // CREATE VIEW 声明的列名，空表示使用查询结果的列名
+	Depends []string // 查询中引用的表和视图
+
+	SearchPath []string `json:",omitempty"` // 创建视图时的 search_path，展开视图时按它解析查询中的名字，空表示 public
+}
+
+// CreateView 创建视图，replace 为 true 时替换同名的视图（CREATE OR REPLACE VIEW）
+func (c *Catalog) CreateView(view *View, replace bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.views[view.Name]; exists {
+		if !replace {
+			return fmt.Errorf("view already exists: %s", view.Name)
+		}
+	} else if _, exists := c.tables[view.Name]; exists {
+		return fmt.Errorf("relation already exists: %s", view.Name)
+	} else if _, exists := c.sequences[view.Name]; exists {
+		return fmt.Errorf("relation already exists: %s", view.Name)
+	}
+
+	// 替换视图时不能引用依赖它的视图（否则展开时无限递归）
+	for _, dep := range view.Depends {
+		if dep == view.Name || c.viewDependsOn(dep, view.Name) {
+			return fmt.Errorf("infinite recursion detected in view %s", view.Name)
+		}
+	}
+
+	c.views[view.Name] = view
+
+	// 持久化
+	return c.save()
+}
+
+// GetView 获取视图定义
+func (c *Catalog) GetView(name string) (*View, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	view, exists := c.views[name]
+	if !exists {
+		return nil, fmt.Errorf("view not found: %s", name)
+	}
+	return view, nil
+}
+
+// DropView 删除视图，被其他视图引用时不能删除
+func (c *Catalog) DropView(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.views[name]; !exists {
+		return fmt.Errorf("view not found: %s", name)
+	}
+	if dependent := c.dependentView(name); dependent != "" {
+		return fmt.Errorf("cannot drop view %s: view %s depends on it", name, dependent)
+	}
+
+	delete(c.views, name)
+
+	// 持久化
+	return c.save()
+}
+
+// ListViews 列出所有视图
+func (c *Catalog) ListViews() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.views))
+	for name := range c.views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DependentView 返回引用了表或视图的一个视图或物化视图名，没有时返回空
+func (c *Catalog) DependentView(name string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dependentView(name)
+}
+
+// dependentView 返回引用了表或视图的一个视图或物化视图名（按名字排序取第一个），没有时返回空（调用者需要持有锁）
+func (c *Catalog) dependentView(name string) string {
+	views := make([]*View, 0, len(c.views))
+	for _, view := range c.views {
+		views = append(views, view)
+	}
+	for _, table := range c.tables {
+		if table.View != nil {
+			views = append(views, table.View)
+		}
+	}
+
+	dependents := make([]string, 0)
+	for _, view := range views {
+		for _, dep := range view.Depends {
+			if dep == name {
+				dependents = append(dependents, view.Name)
+				break
+			}
+		}
+	}
+	if len(dependents) == 0 {
+		return ""
+	}
+	sort.Strings(dependents)
+	return dependents[0]
+}
+
+// viewDependsOn 判断视图是否直接或间接引用了 target（调用者需要持有锁）
+func (c *Catalog) viewDependsOn(name, target string) bool {
+	view, exists := c.views[name]
+	if !exists {
+		return false
+	}
+	for _, dep := range view.Depends {
+		if dep == target || c.viewDependsOn(dep, target) {
+			return true
+		}
+	}
+	return false
+}
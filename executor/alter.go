@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/transaction"
+	"strings"
+)
+
+// executeAlterTable 执行 ALTER TABLE，多个子句按顺序执行，每个子句单独生效
+// 语法: ALTER TABLE name action [, ...]，action 为:
+//
+//	ADD [CONSTRAINT name] CHECK (expr) | DROP CONSTRAINT [IF EXISTS] name
+//	ADD [COLUMN] [IF NOT EXISTS] column_def | DROP [COLUMN] [IF EXISTS] name
+//	RENAME [COLUMN] name TO new_name | RENAME TO new_name
+//	ALTER [COLUMN] name [SET DATA] TYPE type [USING expr]
+//	ADD PARTITION (PARTITION name VALUES ...) | DROP PARTITION [IF EXISTS] name | DETACH PARTITION name
+func (e *Executor) executeAlterTable(sql string) (string, error) {
+	stmt, err := parser.ParseAlterTable(sql)
+	if err != nil {
+		return "", err
+	}
+
+	tableName, err := e.resolveRelation(stmt.Table)
+	if err != nil {
+		return "", err
+	}
+	if schema, err := e.catalog.GetTable(tableName); err != nil {
+		return "", err
+	} else if schema.View != nil {
+		return "", fmt.Errorf("cannot alter materialized view %s", stmt.Table)
+	}
+
+	// 获取写锁
+	txID := e.getCurrentTxID()
+	lockManager := e.txManager.GetLockManager()
+	if err := lockManager.AcquireWriteLock(tableName, transaction.TransactionID(txID)); err != nil {
+		return "", fmt.Errorf("failed to acquire write lock: %w", err)
+	}
+
+	// 如果是自动提交模式，在操作完成后释放锁（出错时也要释放）
+	if e.currentTx == nil {
+		defer lockManager.ReleaseLocks(transaction.TransactionID(txID))
+	}
+
+	for _, action := range stmt.Actions {
+		// 重写表和重命名会替换表定义，每个子句重新获取
+		schema, err := e.catalog.GetTable(tableName)
+		if err != nil {
+			return "", err
+		}
+
+		switch action.Kind {
+		case parser.AlterAddConstraint:
+			err = e.alterAddConstraint(schema, action.Constraint)
+		case parser.AlterDropConstraint:
+			_, err = e.catalog.DropConstraint(tableName, action.Name, action.IfExists)
+		default:
+			// 修改列和重命名会使事务日志中记录的行 ID 和表名失效
+			if e.currentTx != nil {
+				return "", fmt.Errorf("ALTER TABLE %s cannot run inside a transaction", tableName)
+			}
+			switch action.Kind {
+			case parser.AlterAddColumn:
+				err = e.alterAddColumn(schema, action)
+			case parser.AlterDropColumn:
+				err = e.alterDropColumn(schema, action)
+			case parser.AlterRenameColumn:
+				err = e.alterRenameColumn(schema, action)
+			case parser.AlterRenameTable:
+				// 重命名后的表仍在原来的模式中
+				newName := schemaQualified(tableName, action.NewName)
+				err = e.alterRenameTable(schema, newName)
+				tableName = newName
+			case parser.AlterColumnType:
+				err = e.alterColumnType(schema, action)
+			case parser.AlterAddPartition:
+				err = e.alterAddPartition(schema, action)
+			case parser.AlterDropPartition:
+				err = e.alterDropPartition(schema, action)
+			case parser.AlterDetachPartition:
+				err = e.alterDetachPartition(schema, action)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("Table '%s' altered successfully", tableName), nil
+}
+
+// alterAddConstraint 为已有的表添加约束，已有的行必须满足约束
+func (e *Executor) alterAddConstraint(schema *catalog.TableSchema, constraint *parser.TableConstraint) error {
+	if constraint.Kind != parser.ConstraintCheck {
+		return fmt.Errorf("ALTER TABLE ADD only supports CHECK constraints")
+	}
+
+	check, err := e.resolveCheck(schema, constraint)
+	if err != nil {
+		return err
+	}
+	if err := e.verifyCheck(schema, check); err != nil {
+		return err
+	}
+	return e.catalog.AddCheckConstraint(schema.Name, check)
+}
+
+// verifyCheck 检查表中已有的行是否满足 CHECK 约束
+func (e *Executor) verifyCheck(schema *catalog.TableSchema, check *catalog.CheckConstraint) error {
+	expr, err := parser.ParseExpr(check.Expr)
+	if err != nil {
+		return err
+	}
+
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return err
+	}
+	rows, err := e.readAllRows(schema, tableStorage)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		result, known, err := e.evalPredicate(row, expr, schema)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate check constraint %s: %w", check.Name, err)
+		}
+		if known && !result {
+			return fmt.Errorf("constraint violation: check constraint %s of table %s is violated by some row", check.Name, schema.Name)
+		}
+	}
+	return nil
+}
+
+// isAlterTable 检查是否是 ALTER TABLE 语句
+func isAlterTable(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "ALTER TABLE")
+}
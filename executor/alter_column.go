@@ -0,0 +1,522 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/index"
+	"godb/parser"
+	"godb/storage"
+	"godb/types"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// alterAddColumn 添加列，已有的行取列的默认值（没有默认值时为 NULL）
+// 列级的 PRIMARY KEY、UNIQUE、REFERENCES 和 CHECK 约束对已有的行同样检查
+func (e *Executor) alterAddColumn(schema *catalog.TableSchema, action *parser.AlterTableAction) error {
+	colDef := action.Column
+	if schema.GetColumnIndex(colDef.Name) != -1 {
+		if action.IfNotExists {
+			return nil
+		}
+		return fmt.Errorf("column %s of table %s already exists", colDef.Name, schema.Name)
+	}
+
+	column, isAutoIncrement, err := e.buildColumn(schema.Name, colDef)
+	if err != nil {
+		return err
+	}
+	if err := e.validateColumnDefault(column); err != nil {
+		return err
+	}
+
+	newSchema := schema.Clone()
+	newSchema.Columns = append(newSchema.Columns, column)
+	if err := e.validateGeneratedColumns(newSchema); err != nil {
+		return err
+	}
+	indexes := e.catalog.GetIndexesByTable(schema.Name)
+	var foreignKey *catalog.ForeignKey
+
+	constraints := colDef.Constraints()
+	for _, constraint := range constraints {
+		switch constraint.Kind {
+		case parser.ConstraintPrimaryKey:
+			if newSchema.PrimaryKey != "" {
+				return fmt.Errorf("multiple primary keys for table %s are not allowed", schema.Name)
+			}
+			if column.IsVirtual() {
+				return fmt.Errorf("primary key on virtual generated column %s is not supported", column.Name)
+			}
+			newSchema.PrimaryKey = column.Name
+			newSchema.PrimaryKeyIndex = schema.Name + "_pkey"
+			indexes = append(indexes, newIndexInfo(newSchema.PrimaryKeyIndex, newSchema, column))
+		case parser.ConstraintUnique:
+			indexes = append(indexes, newIndexInfo(fmt.Sprintf("%s_%s_key", schema.Name, column.Name), newSchema, column))
+		case parser.ConstraintForeignKey:
+			fk, err := e.resolveForeignKey(newSchema, constraint, constraints)
+			if err != nil {
+				return err
+			}
+			newSchema.ForeignKeys = append(newSchema.ForeignKeys, fk)
+			foreignKey = fk
+		case parser.ConstraintCheck:
+			check, err := e.resolveCheck(newSchema, constraint)
+			if err != nil {
+				return err
+			}
+			newSchema.Checks = append(newSchema.Checks, check)
+		}
+	}
+	for _, info := range indexes {
+		if other, err := e.catalog.GetIndex(info.Name); err == nil && other.TableName != schema.Name {
+			return fmt.Errorf("index already exists: %s", info.Name)
+		}
+	}
+
+	// 自增列需要先创建序列，已有的行按顺序取值
+	if isAutoIncrement {
+		if err := e.createColumnSequence(schema.Name, column); err != nil {
+			return err
+		}
+	}
+
+	err = e.rewriteTable(schema, newSchema, indexes, func(row *storage.Row) ([]types.Value, error) {
+		value, err := e.columnDefault(column)
+		if err != nil {
+			return nil, err
+		}
+		// 默认值必须存在于被引用的表中（引用本表的外键在重写后的表上无法提前检查，默认值为 NULL 时满足）
+		if foreignKey != nil && foreignKey.RefTable != schema.Name && !value.IsNull() {
+			exists, err := e.parentKeyExists(nil, foreignKey, value)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				return nil, fmt.Errorf("constraint violation: table %s violates foreign key %s: %s = %s is not present in table %s",
+					schema.Name, foreignKey.Name, column.Name, e.formatValue(column, value), foreignKey.RefTable)
+			}
+		}
+		return append(append([]types.Value(nil), row.Values...), value), nil
+	})
+	if err != nil && isAutoIncrement {
+		e.catalog.RemoveSequence(columnSequenceName(schema.Name, column.Name))
+	}
+	return err
+}
+
+// alterDropColumn 删除列，同时删除列上的索引、主键、外键、引用该列的 CHECK 约束和所属的序列
+// 被其他表的外键引用的列不能删除
+func (e *Executor) alterDropColumn(schema *catalog.TableSchema, action *parser.AlterTableAction) error {
+	colIndex := schema.GetColumnIndex(action.Name)
+	if colIndex == -1 {
+		if action.IfExists {
+			return nil
+		}
+		return fmt.Errorf("column not found: %s", action.Name)
+	}
+	if len(schema.Columns) == 1 {
+		return fmt.Errorf("cannot drop column %s: it is the only column of table %s", action.Name, schema.Name)
+	}
+	if schema.Partitioning != nil && schema.Partitioning.Column == action.Name {
+		return fmt.Errorf("cannot drop column %s: it is the partition key of table %s", action.Name, schema.Name)
+	}
+	// 视图按列名保存定义，删除列后视图无法再查询
+	if view := e.catalog.DependentView(schema.Name); view != "" {
+		return fmt.Errorf("cannot drop column %s of table %s: view %s depends on it", action.Name, schema.Name, view)
+	}
+
+	for _, fk := range e.catalog.GetForeignKeysReferencing(schema.Name) {
+		if fk.RefColumn == action.Name && fk.Table != schema.Name {
+			return fmt.Errorf("cannot drop column %s of table %s: foreign key %s on table %s references it", action.Name, schema.Name, fk.Name, fk.Table)
+		}
+	}
+
+	for _, column := range schema.Columns {
+		if column.Generated == "" || column.Name == action.Name {
+			continue
+		}
+		references, err := generatedReferencesColumn(column, action.Name)
+		if err != nil {
+			return err
+		}
+		if references {
+			return fmt.Errorf("cannot drop column %s of table %s: generated column %s depends on it", action.Name, schema.Name, column.Name)
+		}
+	}
+
+	newSchema := schema.Clone()
+	newSchema.Columns = append(newSchema.Columns[:colIndex:colIndex], newSchema.Columns[colIndex+1:]...)
+	if newSchema.PrimaryKey == action.Name {
+		newSchema.PrimaryKey = ""
+		newSchema.PrimaryKeyIndex = ""
+	}
+
+	foreignKeys := make([]*catalog.ForeignKey, 0, len(newSchema.ForeignKeys))
+	for _, fk := range newSchema.ForeignKeys {
+		if fk.Column != action.Name && !(fk.RefTable == schema.Name && fk.RefColumn == action.Name) {
+			foreignKeys = append(foreignKeys, fk)
+		}
+	}
+	newSchema.ForeignKeys = foreignKeys
+
+	checks := make([]*catalog.CheckConstraint, 0, len(newSchema.Checks))
+	for _, check := range newSchema.Checks {
+		references, err := checkReferencesColumn(check, action.Name)
+		if err != nil {
+			return err
+		}
+		if !references {
+			checks = append(checks, check)
+		}
+	}
+	newSchema.Checks = checks
+
+	indexes := make([]*catalog.IndexInfo, 0)
+	for _, info := range e.catalog.GetIndexesByTable(schema.Name) {
+		if info.ColumnName != action.Name {
+			indexes = append(indexes, info)
+		}
+	}
+
+	return e.rewriteTable(schema, newSchema, indexes, func(row *storage.Row) ([]types.Value, error) {
+		values := make([]types.Value, 0, len(row.Values)-1)
+		values = append(values, row.Values[:colIndex]...)
+		return append(values, row.Values[colIndex+1:]...), nil
+	})
+}
+
+// alterRenameColumn 重命名列（只修改元数据，不重写行）
+func (e *Executor) alterRenameColumn(schema *catalog.TableSchema, action *parser.AlterTableAction) error {
+	if schema.GetColumnIndex(action.Name) == -1 {
+		return fmt.Errorf("column not found: %s", action.Name)
+	}
+	if schema.GetColumnIndex(action.NewName) != -1 {
+		return fmt.Errorf("column %s of table %s already exists", action.NewName, schema.Name)
+	}
+	if view := e.catalog.DependentView(schema.Name); view != "" {
+		return fmt.Errorf("cannot rename column %s of table %s: view %s depends on it", action.Name, schema.Name, view)
+	}
+
+	// CHECK 约束和生成列表达式中的列名一起替换
+	checks := make([]*catalog.CheckConstraint, len(schema.Checks))
+	for i, check := range schema.Checks {
+		expr, err := renameColumnInExpr(check.Expr, action.Name, action.NewName)
+		if err != nil {
+			return err
+		}
+		checks[i] = &catalog.CheckConstraint{Name: check.Name, Expr: expr}
+	}
+	generated := make([]string, len(schema.Columns))
+	for i, column := range schema.Columns {
+		if column.Generated == "" {
+			continue
+		}
+		expr, err := renameColumnInExpr(column.Generated, action.Name, action.NewName)
+		if err != nil {
+			return err
+		}
+		generated[i] = expr
+	}
+
+	if err := e.catalog.RenameColumn(schema.Name, action.Name, action.NewName, checks, generated); err != nil {
+		return err
+	}
+	e.indexManager.RenameColumn(schema.Name, action.Name, action.NewName)
+	return nil
+}
+
+// alterRenameTable 重命名表（只修改元数据，不重写行）
+func (e *Executor) alterRenameTable(schema *catalog.TableSchema, newName string) error {
+	if err := checkRelationName(newName); err != nil {
+		return err
+	}
+	// catalog 重命名时会修改 schema.Name，先记下原来的表名
+	oldName := schema.Name
+	if err := e.catalog.RenameTable(oldName, newName); err != nil {
+		return err
+	}
+	e.indexManager.RenameTable(oldName, newName)
+	return nil
+}
+
+// alterColumnType 修改列的类型，已有的值按显式类型转换（CAST）的规则转换，或者按 USING 表达式计算
+// 外键涉及的列不能修改类型
+func (e *Executor) alterColumnType(schema *catalog.TableSchema, action *parser.AlterTableAction) error {
+	colDef := action.Column
+	colIndex := schema.GetColumnIndex(colDef.Name)
+	if colIndex == -1 {
+		return fmt.Errorf("column not found: %s", colDef.Name)
+	}
+	oldColumn := schema.Columns[colIndex]
+
+	if _, ok := serialTypes[colDef.Type]; ok {
+		return fmt.Errorf("type %s is only allowed in column definitions", colDef.Type)
+	}
+	if schema.Partitioning != nil && schema.Partitioning.Column == oldColumn.Name {
+		return fmt.Errorf("cannot alter type of column %s: it is the partition key of table %s", oldColumn.Name, schema.Name)
+	}
+	if view := e.catalog.DependentView(schema.Name); view != "" {
+		return fmt.Errorf("cannot alter type of column %s of table %s: view %s depends on it", oldColumn.Name, schema.Name, view)
+	}
+	for _, fk := range schema.ForeignKeys {
+		if fk.Column == oldColumn.Name {
+			return fmt.Errorf("cannot alter type of column %s: it is used by foreign key %s", oldColumn.Name, fk.Name)
+		}
+	}
+	for _, fk := range e.catalog.GetForeignKeysReferencing(schema.Name) {
+		if fk.RefColumn == oldColumn.Name {
+			return fmt.Errorf("cannot alter type of column %s: it is referenced by foreign key %s on table %s", oldColumn.Name, fk.Name, fk.Table)
+		}
+	}
+
+	column, err := e.resolveColumnType(colDef)
+	if err != nil {
+		return err
+	}
+	if column.Type == types.TypeText {
+		collation := colDef.Collation
+		if collation == "" {
+			collation = string(oldColumn.Collation)
+		}
+		if collation != "" {
+			if err := column.SetCollation(collation); err != nil {
+				return err
+			}
+		}
+	} else if colDef.Collation != "" {
+		return fmt.Errorf("collation can only be specified for text columns: %s", column.Name)
+	}
+	column.NotNull = oldColumn.NotNull
+	column.Default = oldColumn.Default
+	column.Generated = oldColumn.Generated
+	column.Stored = oldColumn.Stored
+	if column.Generated != "" && action.Using != "" {
+		return fmt.Errorf("cannot specify USING when altering type of generated column %s", column.Name)
+	}
+	if e.validateColumnDefault(column) != nil && column.Type != types.TypeEnum {
+		// 默认值不能隐式转换时改为显式转换
+		column.Default = fmt.Sprintf("cast((%s) as %s)", oldColumn.Default, column.DeclaredType())
+	}
+	if err := e.validateColumnDefault(column); err != nil {
+		return fmt.Errorf("default for column %s cannot be converted to type %s: %w", column.Name, column.DeclaredType(), err)
+	}
+
+	var usingExpr sqlparser.Expr
+	if action.Using != "" {
+		if usingExpr, err = parser.ParseExpr(action.Using); err != nil {
+			return err
+		}
+	}
+
+	newSchema := schema.Clone()
+	newSchema.Columns[colIndex] = column
+
+	// 索引的键类型随列一起修改，非文本列没有排序规则
+	indexes := e.catalog.GetIndexesByTable(schema.Name)
+	for i, info := range indexes {
+		if info.ColumnName != column.Name {
+			continue
+		}
+		infoCopy := *info
+		infoCopy.ColumnType = column.Type
+		if column.Type != types.TypeText {
+			infoCopy.Collation = ""
+		} else if oldColumn.Type != types.TypeText || info.Collation == oldColumn.Collation {
+			infoCopy.Collation = column.Collation
+		}
+		indexes[i] = &infoCopy
+	}
+
+	return e.rewriteTable(schema, newSchema, indexes, func(row *storage.Row) ([]types.Value, error) {
+		value := row.Values[colIndex]
+		if usingExpr != nil {
+			if value, err = e.evalRowExpr(row, usingExpr, schema); err != nil {
+				return nil, err
+			}
+		} else if value.Type == types.TypeEnum {
+			// 枚举值按标签转换
+			value = types.NewTextValue(e.formatValue(oldColumn, value))
+		}
+
+		converted, err := e.convertToColumn(value, column)
+		if err != nil {
+			return nil, fmt.Errorf("column %s cannot be converted to type %s: %w", column.Name, column.DeclaredType(), err)
+		}
+		values := append([]types.Value(nil), row.Values...)
+		values[colIndex] = converted
+		return values, nil
+	})
+}
+
+// convertToColumn 按显式类型转换的规则把值转换为列的类型（枚举列从文本标签转换）
+func (e *Executor) convertToColumn(value types.Value, column catalog.Column) (types.Value, error) {
+	if value.IsNull() {
+		return value, nil
+	}
+	if column.Type == types.TypeEnum {
+		text, err := types.Cast(value, types.TypeText)
+		if err != nil {
+			return types.Value{}, err
+		}
+		return e.enumFromLabel(column.TypeName, text)
+	}
+	return types.Cast(value, column.Type)
+}
+
+// rewriteTable 把表的所有行按新的表定义重写到新分配的页中，然后替换表定义和索引
+// convert 计算每一行的新值；所有行都满足列约束、CHECK 约束和唯一索引后才替换，出错时原表不变
+func (e *Executor) rewriteTable(schema, newSchema *catalog.TableSchema, indexes []*catalog.IndexInfo, convert func(row *storage.Row) ([]types.Value, error)) error {
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return err
+	}
+	rows, err := e.readAllRows(schema, tableStorage)
+	if err != nil {
+		return err
+	}
+
+	// 计算新值并检查列约束
+	newRows := make([]*storage.Row, len(rows))
+	for i, row := range rows {
+		values, err := convert(row)
+		if err != nil {
+			return err
+		}
+		newRow := &storage.Row{TxID: row.TxID, Values: values}
+		if err := e.computeGeneratedColumns(newSchema, newRow); err != nil {
+			return err
+		}
+		for j, column := range newSchema.Columns {
+			if err := column.Validate(values[j]); err != nil {
+				return err
+			}
+		}
+		newRows[i] = newRow
+	}
+	if err := e.checkConstraints(newSchema, newRows); err != nil {
+		return err
+	}
+
+	return e.replaceTableRows(schema, newSchema, indexes, newRows)
+}
+
+// replaceTableRows 把行写入新分配的页并在新的 B+ 树中重建索引，全部成功后才替换表定义（FirstPageID 和分区的页）和索引
+// 出错时原表不变，释放新分配的页；替换成功后释放旧的页和旧索引的页供之后复用
+// （ALTER TABLE 和 REFRESH MATERIALIZED VIEW 不能在事务中执行，ON COMMIT DELETE ROWS 在提交之后执行，旧的页不会被回滚用到）
+func (e *Executor) replaceTableRows(schema, newSchema *catalog.TableSchema, indexes []*catalog.IndexInfo, newRows []*storage.Row) error {
+	pager := e.pagerFor(newSchema)
+	oldStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return err
+	}
+	oldPageIDs, err := oldStorage.GetPageIDs()
+	if err != nil {
+		return err
+	}
+
+	// 写入新的页（分区表的每个分区分配新的页链）
+	newStorage, err := catalog.AllocateTableStorage(pager, newSchema)
+	if err != nil {
+		return fmt.Errorf("failed to create table storage: %w", err)
+	}
+	built := make([]*index.Index, 0, len(indexes))
+	discard := func() {
+		freeIndexes(built)
+		if pageIDs, err := newStorage.GetPageIDs(); err == nil {
+			pager.FreePages(pageIDs)
+		}
+	}
+
+	for _, row := range newRows {
+		stored := storedRow(newSchema, row)
+		if err := newStorage.InsertRow(stored); err != nil {
+			discard()
+			return fmt.Errorf("failed to rewrite row: %w", err)
+		}
+		row.ID = stored.ID
+	}
+
+	// 构建索引（唯一索引检查重复键），新的根节点页 ID 记录在索引信息的副本中
+	infos := make([]*catalog.IndexInfo, 0, len(indexes))
+	for _, info := range indexes {
+		colIndex := newSchema.GetColumnIndex(info.ColumnName)
+		idx, err := index.NewIndex(pager, info.Name, newSchema.Name, info.ColumnName, info.ColumnType, info.Collation, info.Unique)
+		if err != nil {
+			discard()
+			return err
+		}
+		built = append(built, idx)
+		for _, row := range newRows {
+			if err := idx.Insert(row.Values[colIndex], row.ID); err != nil {
+				discard()
+				return fmt.Errorf("failed to build index %s: %w", info.Name, err)
+			}
+		}
+		infoCopy := *info
+		infoCopy.RootPageID = idx.RootPageID()
+		infos = append(infos, &infoCopy)
+	}
+
+	if err := e.pager.FlushAll(); err != nil {
+		discard()
+		return fmt.Errorf("failed to flush pages: %w", err)
+	}
+
+	// 替换表定义
+	oldIndexes := e.indexManager.GetIndexesByTable(schema.Name)
+	if err := e.catalog.ReplaceTable(newSchema, infos); err != nil {
+		discard()
+		return err
+	}
+	e.indexManager.ReplaceTableIndexes(schema.Name, built)
+
+	// 旧的行和索引不再被引用
+	freeIndexes(oldIndexes)
+	e.pagerFor(schema).FreePages(oldPageIDs)
+	return nil
+}
+
+// newIndexInfo 为 ALTER TABLE 添加的列创建唯一索引信息
+func newIndexInfo(name string, schema *catalog.TableSchema, column catalog.Column) *catalog.IndexInfo {
+	return &catalog.IndexInfo{
+		Name:       name,
+		TableName:  schema.Name,
+		ColumnName: column.Name,
+		ColumnType: column.Type,
+		Collation:  column.Collation,
+		Unique:     true,
+	}
+}
+
+// renameColumnInExpr 把表达式中引用的列名 oldName 替换为 newName，返回新的表达式原文
+func renameColumnInExpr(text, oldName, newName string) (string, error) {
+	expr, err := parser.ParseExpr(text)
+	if err != nil {
+		return "", err
+	}
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if colName, ok := node.(*sqlparser.ColName); ok && colName.Name.String() == oldName {
+			colName.Name = sqlparser.NewColIdent(newName)
+		}
+		return true, nil
+	}, expr)
+	return parser.String(expr), nil
+}
+
+// checkReferencesColumn 判断 CHECK 约束的表达式是否引用了列
+func checkReferencesColumn(check *catalog.CheckConstraint, columnName string) (bool, error) {
+	expr, err := parser.ParseExpr(check.Expr)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if colName, ok := node.(*sqlparser.ColName); ok && colName.Name.String() == columnName {
+			found = true
+			return false, nil
+		}
+		return true, nil
+	}, expr)
+	return found, nil
+}
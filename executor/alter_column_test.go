@@ -0,0 +1,30 @@
+package executor
+
+import "testing"
+
+// 有视图依赖的表不能删除、重命名列或修改列类型，视图仍然可以查询
+func TestAlterColumnWithDependentView(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE users (id INT PRIMARY KEY, name TEXT, age INT)",
+		"INSERT INTO users VALUES (1, 'alice', 30)",
+		"CREATE VIEW user_names AS SELECT id, name FROM users",
+		"CREATE MATERIALIZED VIEW user_ages AS SELECT id, age FROM users",
+	)
+
+	mustFail(t, e, "ALTER TABLE users DROP COLUMN name", "cannot drop column name of table users: view user_ages depends on it")
+	mustFail(t, e, "ALTER TABLE users RENAME COLUMN name TO full_name", "cannot rename column name of table users: view user_ages depends on it")
+	mustFail(t, e, "ALTER TABLE users ALTER COLUMN age TYPE BIGINT", "cannot alter type of column age of table users: view user_ages depends on it")
+
+	mustExec(t, e, "DROP MATERIALIZED VIEW user_ages")
+	mustFail(t, e, "ALTER TABLE users DROP COLUMN age", "view user_names depends on it")
+	expectRows(t, e, "SELECT * FROM user_names", "1\talice")
+
+	// 删除视图后可以修改列
+	mustExec(t, e,
+		"DROP VIEW user_names",
+		"ALTER TABLE users RENAME COLUMN name TO full_name",
+		"ALTER TABLE users DROP COLUMN age",
+	)
+	expectRows(t, e, "SELECT * FROM users", "1\talice")
+}
@@ -0,0 +1,269 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/storage"
+	"godb/transaction"
+	"godb/types"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	statsSampleRows       = 3000 // ANALYZE 计算列统计信息时最多采样的行数
+	statsHistogramBuckets = 10   // 等深直方图的桶数
+	// indexScanMaxSelectivity 条件估计匹配的行数超过表的这个比例时不使用索引，逐行按 RowID 读取不如全表扫描
+	indexScanMaxSelectivity = 0.3
+)
+
+// executeAnalyze 执行 ANALYZE，收集表的统计信息并保存在元数据中（godb_stats 查看）
+// 语法: ANALYZE [TABLE] [table]，不指定表时收集所有表和物化视图的统计信息
+func (e *Executor) executeAnalyze(sql string) (string, error) {
+	pattern := `(?i)^\s*ANALYZE(?:\s+TABLE)?(?:\s+([\w.]+))?\s*;?\s*$`
+	matches := regexp.MustCompile(pattern).FindStringSubmatch(sql)
+	if len(matches) != 2 {
+		return "", fmt.Errorf("invalid ANALYZE syntax, expected: ANALYZE [table]")
+	}
+
+	if matches[1] == "" {
+		tables := sortedTables(e.catalog)
+		for _, tableName := range tables {
+			if _, err := e.analyzeTable(tableName); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("%d table(s) analyzed", len(tables)), nil
+	}
+
+	tableName, err := e.resolveRelation(matches[1])
+	if err != nil {
+		return "", err
+	}
+	if db, _ := e.attachedRelation(tableName); db != nil {
+		return "", fmt.Errorf("cannot analyze %s: attached databases are read-only", tableName)
+	}
+	if isSystemTable(tableName) {
+		return "", fmt.Errorf("cannot analyze system table %s", tableName)
+	}
+	if _, err := e.catalog.GetView(tableName); err == nil {
+		return "", fmt.Errorf("cannot analyze view %s", tableName)
+	}
+
+	stats, err := e.analyzeTable(tableName)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Table '%s' analyzed: %d row(s), %d sampled", tableName, stats.RowCount, stats.SampleSize), nil
+}
+
+// analyzeTable 读取表中当前事务可见的行，统计行数，并在采样的行上计算各列的统计信息
+func (e *Executor) analyzeTable(tableName string) (*catalog.TableStats, error) {
+	txID := e.getCurrentTxID()
+	lockManager := e.txManager.GetLockManager()
+	if err := lockManager.AcquireReadLock(tableName, transaction.TransactionID(txID)); err != nil {
+		return nil, fmt.Errorf("failed to acquire read lock: %w", err)
+	}
+	if e.currentTx == nil {
+		defer lockManager.ReleaseLocks(transaction.TransactionID(txID))
+	}
+
+	schema, err := e.catalog.GetTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := e.readAllRows(schema, tableStorage)
+	if err != nil {
+		return nil, err
+	}
+	rows = e.filterVisibleRows(rows)
+
+	sample := sampleRows(rows, statsSampleRows)
+	stats := &catalog.TableStats{
+		Table:      tableName,
+		RowCount:   int64(len(rows)),
+		SampleSize: int64(len(sample)),
+		AnalyzedAt: time.Now(),
+		Columns:    make([]*catalog.ColumnStats, len(schema.Columns)),
+	}
+	for i, column := range schema.Columns {
+		stats.Columns[i] = e.columnStats(column, i, sample, stats.RowCount)
+	}
+
+	if err := e.catalog.SetTableStats(stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// sampleRows 用蓄水池抽样从行中随机选出最多 n 行，行数不超过 n 时返回全部行
+func sampleRows(rows []*storage.Row, n int) []*storage.Row {
+	if len(rows) <= n {
+		return rows
+	}
+	sample := append([]*storage.Row(nil), rows[:n]...)
+	for i := n; i < len(rows); i++ {
+		if j := rand.Intn(i + 1); j < n {
+			sample[j] = rows[i]
+		}
+	}
+	return sample
+}
+
+// columnStats 在样本上计算列的 NULL 比例、不同值个数和等深直方图
+func (e *Executor) columnStats(column catalog.Column, colIndex int, sample []*storage.Row, rowCount int64) *catalog.ColumnStats {
+	stats := &catalog.ColumnStats{Column: column.Name}
+	if len(sample) == 0 {
+		return stats
+	}
+
+	values := make([]types.Value, 0, len(sample))
+	for _, row := range sample {
+		if value := row.Values[colIndex]; !value.IsNull() {
+			values = append(values, value)
+		}
+	}
+	stats.NullFrac = float64(len(sample)-len(values)) / float64(len(sample))
+	if len(values) == 0 {
+		return stats
+	}
+
+	// 排序后相等的值相邻（TEXT 按列的排序规则比较）
+	sort.SliceStable(values, func(i, j int) bool {
+		return types.Compare(values[i], values[j], column.Collation) < 0
+	})
+	distinct, singletons := 0, 0
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && types.Compare(values[i], values[j], column.Collation) == 0 {
+			j++
+		}
+		distinct++
+		if j-i == 1 {
+			singletons++
+		}
+		i = j
+	}
+	stats.Distinct = estimateDistinct(distinct, singletons, len(values), float64(rowCount)*(1-stats.NullFrac), len(sample) == int(rowCount))
+
+	// 等深直方图：每个桶包含数量大致相同的值
+	if len(values) >= 2 {
+		buckets := statsHistogramBuckets
+		if buckets > len(values)-1 {
+			buckets = len(values) - 1
+		}
+		for i := 0; i <= buckets; i++ {
+			stats.Histogram = append(stats.Histogram, e.formatValue(column, values[i*(len(values)-1)/buckets]))
+		}
+	}
+	return stats
+}
+
+// estimateDistinct 由样本中的不同值个数估算表中的不同值个数（Haas-Stokes 估计，与 PostgreSQL 相同）
+// singletons 为样本中只出现一次的值的个数，n 为样本中非 NULL 值的个数，total 为表中非 NULL 值的个数
+func estimateDistinct(distinct, singletons, n int, total float64, complete bool) int64 {
+	if complete || singletons == 0 {
+		return int64(distinct)
+	}
+	estimate := float64(n*distinct) / (float64(n-singletons) + float64(singletons)*float64(n)/total)
+	if estimate < float64(distinct) {
+		estimate = float64(distinct)
+	}
+	if estimate > total {
+		estimate = total
+	}
+	return int64(estimate + 0.5)
+}
+
+// estimateSelectivity 根据统计信息估计 column operator value 匹配的行占表的比例
+// 没有统计信息时返回 false
+func (e *Executor) estimateSelectivity(tableName string, column catalog.Column, operator string, value types.Value) (float64, bool) {
+	stats := e.catalog.GetTableStats(tableName)
+	if stats == nil {
+		return 0, false
+	}
+	columnStats := stats.GetColumn(column.Name)
+	if columnStats == nil {
+		return 0, false
+	}
+	nonNull := 1 - columnStats.NullFrac
+
+	switch operator {
+	case "=":
+		if columnStats.Distinct == 0 {
+			return 0, true
+		}
+		return nonNull / float64(columnStats.Distinct), true
+	case "<", "<=", ">", ">=":
+		below, ok := e.histogramFraction(column, columnStats.Histogram, value)
+		if !ok {
+			return 0, false
+		}
+		if operator == ">" || operator == ">=" {
+			return nonNull * (1 - below), true
+		}
+		return nonNull * below, true
+	}
+	return 0, false
+}
+
+// histogramFraction 根据等深直方图估计小于 value 的非 NULL 值所占的比例
+// 值落在某个桶中时，数值按桶边界线性插值，其他类型取桶的一半
+func (e *Executor) histogramFraction(column catalog.Column, histogram []string, value types.Value) (float64, bool) {
+	if len(histogram) < 2 {
+		return 0, false
+	}
+	bounds := make([]types.Value, len(histogram))
+	for i, text := range histogram {
+		bound, err := e.convertToColumn(types.NewTextValue(text), column)
+		if err != nil {
+			return 0, false
+		}
+		bounds[i] = bound
+	}
+
+	buckets := len(bounds) - 1
+	if types.Compare(value, bounds[0], column.Collation) < 0 {
+		return 0, true
+	}
+	for i := 0; i < buckets; i++ {
+		if types.Compare(value, bounds[i+1], column.Collation) >= 0 {
+			continue
+		}
+		position := 0.5
+		lo, okLo := numericValue(bounds[i])
+		hi, okHi := numericValue(bounds[i+1])
+		v, okV := numericValue(value)
+		if okLo && okHi && okV && hi > lo {
+			position = (v - lo) / (hi - lo)
+		}
+		return (float64(i) + position) / float64(buckets), true
+	}
+	return 1, true
+}
+
+// numericValue 把整数或浮点数转换为 float64
+func numericValue(value types.Value) (float64, bool) {
+	switch value.Type {
+	case types.TypeInt:
+		intVal, _ := value.AsInt()
+		return float64(intVal), true
+	case types.TypeFloat:
+		floatVal, _ := value.AsFloat()
+		return floatVal, true
+	}
+	return 0, false
+}
+
+// isAnalyze 检查是否是 ANALYZE 语句
+func isAnalyze(sql string) bool {
+	fields := strings.Fields(strings.ToUpper(sql))
+	return len(fields) > 0 && strings.TrimSuffix(fields[0], ";") == "ANALYZE"
+}
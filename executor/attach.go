@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/index"
+	"godb/storage"
+	"godb/transaction"
+	"godb/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// attachedDatabase ATTACH DATABASE 打开的数据库文件
+// 附加的数据库只读，其中的表、视图和物化视图通过一个独立的执行器读取
+type attachedDatabase struct {
+	file   string
+	pager  *storage.Pager
+	reader *Executor
+}
+
+// executeAttach 执行 ATTACH DATABASE，打开另一个数据库文件及其元数据，之后可以用 alias.name 查询其中的表
+// 语法: ATTACH [DATABASE] 'file' AS alias
+func (e *Executor) executeAttach(sql string) (string, error) {
+	pattern := `(?i)^\s*ATTACH\s+(?:DATABASE\s+)?'([^']+)'\s+AS\s+(\w+)\s*;?\s*$`
+	matches := regexp.MustCompile(pattern).FindStringSubmatch(sql)
+	if len(matches) != 3 {
+		return "", fmt.Errorf("invalid ATTACH syntax, expected: ATTACH DATABASE 'file' AS alias")
+	}
+	file, alias := matches[1], matches[2]
+
+	// 附加的数据库名和模式名一样用来限定表名
+	if _, exists := e.attached[alias]; exists {
+		return "", fmt.Errorf("database %s is already attached", alias)
+	}
+	if e.catalog.SchemaExists(alias) {
+		return "", fmt.Errorf("database name %s conflicts with schema %s", alias, alias)
+	}
+
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return "", fmt.Errorf("invalid database file %s: %w", file, err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("database file not found: %s", file)
+	}
+	if mainPath, err := filepath.Abs(e.pager.FileName()); err == nil && mainPath == path {
+		return "", fmt.Errorf("cannot attach the current database file %s", file)
+	}
+	for name, db := range e.attached {
+		if db.file == path {
+			return "", fmt.Errorf("database file %s is already attached as %s", file, name)
+		}
+	}
+
+	pager, err := storage.NewPager(path)
+	if err != nil {
+		return "", err
+	}
+	catalogMgr, err := catalog.NewCatalog(metaFileFor(path))
+	if err != nil {
+		pager.Close()
+		return "", fmt.Errorf("failed to load catalog of %s: %w", file, err)
+	}
+
+	// 附加的数据库只用来读取，不需要重建索引
+	reader := NewExecutor(catalogMgr, pager, index.NewIndexManager(), transaction.NewTransactionManager(pager, catalogMgr))
+	if e.attached == nil {
+		e.attached = make(map[string]*attachedDatabase)
+	}
+	e.attached[alias] = &attachedDatabase{file: path, pager: pager, reader: reader}
+
+	return fmt.Sprintf("Database '%s' attached as %s", file, alias), nil
+}
+
+// executeDetach 执行 DETACH DATABASE，关闭附加的数据库文件
+// 语法: DETACH [DATABASE] alias
+func (e *Executor) executeDetach(sql string) (string, error) {
+	pattern := `(?i)^\s*DETACH\s+(?:DATABASE\s+)?(\w+)\s*;?\s*$`
+	matches := regexp.MustCompile(pattern).FindStringSubmatch(sql)
+	if len(matches) != 2 {
+		return "", fmt.Errorf("invalid DETACH syntax, expected: DETACH DATABASE alias")
+	}
+	alias := matches[1]
+
+	db, exists := e.attached[alias]
+	if !exists {
+		return "", fmt.Errorf("no such database: %s", alias)
+	}
+	delete(e.attached, alias)
+	if err := db.pager.Close(); err != nil {
+		return "", fmt.Errorf("failed to close database %s: %w", alias, err)
+	}
+
+	return fmt.Sprintf("Database '%s' detached", alias), nil
+}
+
+// metaFileFor 返回数据库文件对应的元数据文件（godb.db 对应 godb_meta.json）
+func metaFileFor(dbFile string) string {
+	return strings.TrimSuffix(dbFile, filepath.Ext(dbFile)) + "_meta.json"
+}
+
+// attachedRelation 如果名字是 alias.name 形式的附加数据库中的对象，返回附加的数据库和对象名
+func (e *Executor) attachedRelation(name string) (*attachedDatabase, string) {
+	alias, relation, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil, ""
+	}
+	return e.attached[alias], relation
+}
+
+// loadRelation 读取附加的数据库中的表、视图或系统表
+// 枚举类型属于附加的数据库，枚举列转换为 TEXT 标签
+func (db *attachedDatabase) loadRelation(name string) (*catalog.TableSchema, []*storage.Row, error) {
+	relation, err := db.reader.resolveRelation(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	schema, rows, err := db.reader.loadRelation(relation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema = schema.Clone()
+	for i, column := range schema.Columns {
+		if column.Type != types.TypeEnum {
+			continue
+		}
+		for _, row := range rows {
+			if value := row.Values[i]; !value.IsNull() {
+				row.Values[i] = types.NewTextValue(db.reader.formatValue(column, value))
+			}
+		}
+		schema.Columns[i] = catalog.Column{Name: column.Name, Type: types.TypeText, TypeName: "TEXT", NotNull: column.NotNull}
+	}
+	return schema, rows, nil
+}
+
+// isAttach 检查是否是 ATTACH DATABASE 语句
+func isAttach(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "ATTACH ")
+}
+
+// isDetach 检查是否是 DETACH DATABASE 语句
+func isDetach(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "DETACH ")
+}
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"godb/catalog"
+	"godb/index"
+	"godb/storage"
+	"godb/transaction"
+	"path/filepath"
+	"testing"
+)
+
+// createArchive 创建另一个数据库文件，执行 sqls 后关闭，返回数据文件名
+func createArchive(t *testing.T, sqls ...string) string {
+	t.Helper()
+	dbFile := filepath.Join(t.TempDir(), "archive.db")
+	pager, err := storage.NewPager(dbFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalogMgr, err := catalog.NewCatalog(metaFileFor(dbFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewExecutor(catalogMgr, pager, index.NewIndexManager(), transaction.NewTransactionManager(pager, catalogMgr))
+	mustExec(t, e, sqls...)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pager.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return dbFile
+}
+
+// 附加数据库中的表与本地的同名表做 JOIN
+func TestJoinAttachedTableWithLocalTable(t *testing.T) {
+	archive := createArchive(t,
+		"CREATE TABLE orders (id INT PRIMARY KEY, amt INT)",
+		"INSERT INTO orders VALUES (1, 100), (2, 200)",
+	)
+
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE orders (id INT, note TEXT)",
+		"INSERT INTO orders VALUES (3, 'c'), (1, 'a'), (2, 'b1'), (2, 'b2')",
+		"ATTACH DATABASE '"+archive+"' AS archive",
+	)
+
+	expectRows(t, e, "SELECT orders.id, orders.note, archive.orders.amt FROM orders JOIN archive.orders ON orders.id = archive.orders.id WHERE orders.id > 0 ORDER BY orders.note",
+		"1\ta\t100",
+		"2\tb1\t200",
+		"2\tb2\t200",
+	)
+	expectRows(t, e, "SELECT o.id, a.amt FROM orders o LEFT JOIN archive.orders a ON o.id = a.id WHERE o.id = 3", "3\tNULL")
+	mustFail(t, e, "SELECT id FROM orders JOIN archive.orders ON orders.id = archive.orders.id", "column reference id is ambiguous")
+
+	// 会话结束时关闭附加的数据库
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.attached) != 0 {
+		t.Fatalf("attached databases still open after Close: %d", len(e.attached))
+	}
+}
@@ -0,0 +1,37 @@
+package executor
+
+import "testing"
+
+// 括号表达式在 SELECT 和 WHERE 中做 :: 类型转换
+func TestCastParenthesizedOperand(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE nums (id INT PRIMARY KEY, f FLOAT)",
+		"INSERT INTO nums VALUES (1, 2.5), (2, 4.0)",
+	)
+
+	expectRows(t, e, "SELECT (id + 1)::FLOAT FROM nums", "2.000000", "3.000000")
+	expectRows(t, e, "SELECT id FROM nums WHERE (id * 2)::FLOAT > 3.5", "2")
+	expectRows(t, e, "SELECT id FROM nums WHERE id > 0 AND (f)::INT = 4", "2")
+}
+
+// CAST 的目标类型按列类型解析：文本按长度截断，整数检查范围，枚举类型从目录中查找
+func TestCastTypeModifiers(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TYPE status AS ENUM ('active', 'inactive')",
+		"CREATE TABLE accounts (id INT PRIMARY KEY, s status)",
+		"INSERT INTO accounts VALUES (1, 'active'), (2, 'inactive')",
+	)
+
+	expectRows(t, e, "SELECT CAST('abcdef' AS VARCHAR(3)), 'héllo'::CHAR(2), CAST('ab' AS VARCHAR(5))", "abc\thé\tab")
+	expectRows(t, e, "SELECT CAST(127 AS TINYINT), CAST(255 AS TINYINT UNSIGNED)", "127\t255")
+	mustFail(t, e, "SELECT CAST(300 AS TINYINT)", "value 300 out of range for type TINYINT")
+	mustFail(t, e, "SELECT CAST(-1 AS SMALLINT UNSIGNED)", "value -1 out of range for type SMALLINT UNSIGNED")
+
+	expectRows(t, e, "SELECT id FROM accounts WHERE s = CAST('inactive' AS status)", "2")
+	expectRows(t, e, "SELECT id FROM accounts WHERE s = 'active'::status", "1")
+	expectRows(t, e, "SELECT CAST(s AS TEXT) FROM accounts WHERE CAST(s AS status) = 'inactive'::status", "inactive")
+	mustFail(t, e, "SELECT CAST('unknown' AS status)", "invalid input value for enum status")
+	mustFail(t, e, "SELECT CAST(1 AS nosuch)", "unsupported cast target type: nosuch")
+}
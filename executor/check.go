@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/types"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// resolveCheck 根据 CHECK 约束声明创建约束定义
+// 表达式只能引用表中的列，未命名的约束命名为 <table>_<column>_check 或 <table>_check
+func (e *Executor) resolveCheck(schema *catalog.TableSchema, constraint *parser.TableConstraint) (*catalog.CheckConstraint, error) {
+	expr, err := parser.ParseExpr(constraint.Check)
+	if err != nil {
+		return nil, err
+	}
+
+	// 在全部为 NULL 的行上试算一次，检查列引用和表达式是否受支持
+	nullRow := &storage.Row{Values: make([]types.Value, len(schema.Columns))}
+	for i := range nullRow.Values {
+		nullRow.Values[i] = types.NewNullValue()
+	}
+	if _, _, err := e.evalPredicate(nullRow, expr, schema); err != nil {
+		return nil, fmt.Errorf("invalid check constraint %s: %w", constraint.Check, err)
+	}
+
+	name := constraint.Name
+	if name == "" {
+		base := catalog.BaseName(schema.Name) + "_check"
+		if len(constraint.Columns) == 1 {
+			base = fmt.Sprintf("%s_%s_check", catalog.BaseName(schema.Name), constraint.Columns[0])
+		}
+		name = base
+		for i := 1; schema.HasConstraint(name); i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+	} else if schema.HasConstraint(name) {
+		return nil, fmt.Errorf("constraint %s for table %s already exists", name, schema.Name)
+	}
+
+	return &catalog.CheckConstraint{Name: name, Expr: constraint.Check}, nil
+}
+
+// checkConstraints 检查写入的行是否满足表的 CHECK 约束，分区表的行还要属于某个分区
+// 表达式结果为 NULL（未知）时视为满足约束
+func (e *Executor) checkConstraints(schema *catalog.TableSchema, rows []*storage.Row) error {
+	// 分区表的每一行都要属于某个分区
+	if partitioning := schema.Partitioning; partitioning != nil {
+		colIndex := schema.GetColumnIndex(partitioning.Column)
+		for _, row := range rows {
+			if _, err := partitioning.PartitionFor(row.Values[colIndex], schema.Columns[colIndex]); err != nil {
+				return fmt.Errorf("constraint violation: table %s has %w", schema.Name, err)
+			}
+		}
+	}
+
+	for _, check := range schema.Checks {
+		expr, err := parser.ParseExpr(check.Expr)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			result, known, err := e.evalPredicate(row, expr, schema)
+			if err != nil {
+				return fmt.Errorf("failed to evaluate check constraint %s: %w", check.Name, err)
+			}
+			if known && !result {
+				return fmt.Errorf("constraint violation: new row for table %s violates check constraint %s: %s",
+					schema.Name, check.Name, check)
+			}
+		}
+	}
+	return nil
+}
+
+// evalPredicate 按三值逻辑计算条件，有 NULL 参与比较时结果未知（known 为 false）
+func (e *Executor) evalPredicate(row *storage.Row, expr sqlparser.Expr, schema *catalog.TableSchema) (result bool, known bool, err error) {
+	switch expr := expr.(type) {
+	case *sqlparser.ParenExpr:
+		return e.evalPredicate(row, expr.Expr, schema)
+
+	case *sqlparser.ComparisonExpr:
+		for _, operand := range []sqlparser.Expr{expr.Left, expr.Right} {
+			value, err := e.evalRowExpr(row, operand, schema)
+			if err != nil {
+				return false, false, err
+			}
+			if value.IsNull() {
+				return false, false, nil
+			}
+		}
+		result, err := e.evalComparison(row, expr, schema)
+		return result, err == nil, err
+
+	case *sqlparser.IsExpr:
+		value, err := e.evalRowExpr(row, expr.Expr, schema)
+		if err != nil {
+			return false, false, err
+		}
+		result, err := evalIsNull(value, expr.Operator)
+		return result, err == nil, err
+
+	case *sqlparser.NotExpr:
+		result, known, err := e.evalPredicate(row, expr.Expr, schema)
+		return !result, known, err
+
+	case *sqlparser.AndExpr:
+		left, leftKnown, err := e.evalPredicate(row, expr.Left, schema)
+		if err != nil {
+			return false, false, err
+		}
+		right, rightKnown, err := e.evalPredicate(row, expr.Right, schema)
+		if err != nil {
+			return false, false, err
+		}
+		// 任意一边为 false 时结果为 false
+		if (leftKnown && !left) || (rightKnown && !right) {
+			return false, true, nil
+		}
+		return true, leftKnown && rightKnown, nil
+
+	case *sqlparser.OrExpr:
+		left, leftKnown, err := e.evalPredicate(row, expr.Left, schema)
+		if err != nil {
+			return false, false, err
+		}
+		right, rightKnown, err := e.evalPredicate(row, expr.Right, schema)
+		if err != nil {
+			return false, false, err
+		}
+		// 任意一边为 true 时结果为 true
+		if (leftKnown && left) || (rightKnown && right) {
+			return true, true, nil
+		}
+		return false, leftKnown && rightKnown, nil
+
+	default:
+		value, err := e.evalRowExpr(row, expr, schema)
+		if err != nil {
+			return false, false, err
+		}
+		if value.IsNull() {
+			return false, false, nil
+		}
+		if value.Type != types.TypeBoolean {
+			return false, false, fmt.Errorf("expression must be boolean, got %s", value.Type)
+		}
+		result, err := value.AsBoolean()
+		return result, err == nil, err
+	}
+}
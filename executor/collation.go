@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"godb/catalog"
+	"godb/types"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// explicitCollation 返回表达式上显式声明的排序规则（expr COLLATE name）
+func explicitCollation(expr sqlparser.Expr) (types.Collation, bool, error) {
+	collateExpr, ok := expr.(*sqlparser.CollateExpr)
+	if !ok {
+		return "", false, nil
+	}
+	collation, err := types.ParseCollation(collateExpr.Charset)
+	if err != nil {
+		return "", false, err
+	}
+	return collation, true, nil
+}
+
+// unwrapCollate 去掉表达式外层的 COLLATE
+func unwrapCollate(expr sqlparser.Expr) sqlparser.Expr {
+	for {
+		collateExpr, ok := expr.(*sqlparser.CollateExpr)
+		if !ok {
+			return expr
+		}
+		expr = collateExpr.Expr
+	}
+}
+
+// resolveCollation 确定比较使用的排序规则
+// 显式的 COLLATE 优先，其次是列的排序规则（左操作数优先），都没有时使用 binary
+func resolveCollation(exprs []sqlparser.Expr, columns []*catalog.Column) (types.Collation, error) {
+	for _, expr := range exprs {
+		collation, ok, err := explicitCollation(expr)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return collation, nil
+		}
+	}
+
+	for _, column := range columns {
+		if column != nil && column.Type == types.TypeText && column.Collation != "" {
+			return column.Collation, nil
+		}
+	}
+
+	return types.CollationBinary, nil
+}
+
+// comparisonCollation 确定单表中两个表达式比较时使用的排序规则
+func comparisonCollation(left, right sqlparser.Expr, schema *catalog.TableSchema) (types.Collation, error) {
+	return resolveCollation([]sqlparser.Expr{left, right}, []*catalog.Column{
+		exprColumnRef(left, schema),
+		exprColumnRef(right, schema),
+	})
+}
+
+// exprColumnRef 与 exprColumn 相同，但在表达式不是列引用时返回 nil
+func exprColumnRef(expr sqlparser.Expr, schema *catalog.TableSchema) *catalog.Column {
+	column, ok := exprColumn(expr, schema)
+	if !ok {
+		return nil
+	}
+	return &column
+}
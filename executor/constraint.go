@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/index"
+	"godb/storage"
+	"godb/types"
+)
+
+// checkUniqueKeys 检查待写入的行在唯一索引上是否与表中已有的行或同一批次中的其他行冲突
+// replaced 中的行会被本次写入替换（UPDATE 的旧行），不参与冲突检查
+func (e *Executor) checkUniqueKeys(schema *catalog.TableSchema, tableStorage *storage.TableStorage, rows []*storage.Row, replaced map[storage.RowID]bool) error {
+	for _, idx := range e.indexManager.GetIndexesByTable(schema.Name) {
+		if !idx.Unique {
+			continue
+		}
+
+		colIndex := schema.GetColumnIndex(idx.ColumnName)
+		if colIndex == -1 {
+			continue
+		}
+
+		// 按排序规则下的键检查同一批次中的重复键
+		batch := make(map[string]bool, len(rows))
+
+		for _, row := range rows {
+			key := row.Values[colIndex]
+			if key.IsNull() {
+				// NULL 与任何值都不相等，不参与唯一性检查
+				continue
+			}
+
+			batchKey, err := key.Key(idx.Collation)
+			if err != nil {
+				return err
+			}
+			if batch[string(batchKey)] {
+				return e.uniqueViolation(schema, idx, key)
+			}
+			batch[string(batchKey)] = true
+
+			rowIDs, err := idx.Search(key)
+			if err != nil {
+				return err
+			}
+			for _, rowID := range rowIDs {
+				if replaced[rowID] {
+					continue
+				}
+				existing, err := e.getRowByID(tableStorage, rowID)
+				if err != nil {
+					return err
+				}
+				// 已回滚或已删除的行不占用键，清理残留的索引条目
+				if existing.Deleted {
+					idx.Delete(key, rowID)
+					continue
+				}
+				return e.uniqueViolation(schema, idx, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkIndexKeys 检查待写入的行在表的每个索引上的键都能写入索引（键不能过长）
+func (e *Executor) checkIndexKeys(schema *catalog.TableSchema, rows []*storage.Row) error {
+	for _, idx := range e.indexManager.GetIndexesByTable(schema.Name) {
+		colIndex := schema.GetColumnIndex(idx.ColumnName)
+		if colIndex == -1 {
+			continue
+		}
+		for _, row := range rows {
+			if err := idx.CheckKey(row.Values[colIndex]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// uniqueViolation 生成唯一约束冲突错误
+func (e *Executor) uniqueViolation(schema *catalog.TableSchema, idx *index.Index, key types.Value) error {
+	value := key.String()
+	if colIndex := schema.GetColumnIndex(idx.ColumnName); colIndex != -1 {
+		value = e.formatValue(schema.Columns[colIndex], key)
+	}
+
+	if idx.Name == schema.PrimaryKeyIndex {
+		return fmt.Errorf("constraint violation: duplicate primary key %s = %s in table %s", idx.ColumnName, value, schema.Name)
+	}
+	return fmt.Errorf("constraint violation: duplicate key %s = %s violates unique index %s", idx.ColumnName, value, idx.Name)
+}
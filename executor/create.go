@@ -3,66 +3,346 @@ package executor
 import (
 	"fmt"
 	"godb/catalog"
-	"godb/storage"
+	"godb/parser"
+	"godb/types"
 	"strings"
 
 	"github.com/xwb1989/sqlparser"
 )
 
 // executeCreateTable 执行 CREATE TABLE
-func (e *Executor) executeCreateTable(stmt *sqlparser.DDL) (string, error) {
-	tableName := stmt.NewName.Name.String()
+func (e *Executor) executeCreateTable(sql string) (string, error) {
+	stmt, err := parser.ParseCreateTable(sql)
+	if err != nil {
+		return "", err
+	}
 
-	// 检查 TableSpec 是否存在
-	if stmt.TableSpec == nil {
-		return "", fmt.Errorf("invalid CREATE TABLE statement: missing table specification")
+	var tableName string
+	if stmt.Temporary {
+		// 临时表属于本会话的 pg_temp 模式
+		if schemaName, _, ok := strings.Cut(stmt.Table, "."); ok && schemaName != catalog.TempSchema {
+			return "", fmt.Errorf("cannot create temporary table in schema %s", schemaName)
+		}
+		tableName = catalog.QualifiedName(catalog.TempSchema, catalog.BaseName(stmt.Table))
+	} else {
+		tableName, err = e.newRelationName(stmt.Table)
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := checkRelationName(tableName); err != nil {
+		return "", err
+	}
+	if stmt.IfNotExists && e.relationExists(tableName) {
+		return fmt.Sprintf("Table '%s' already exists, skipped", tableName), nil
 	}
 
 	// 解析列定义
 	columns := make([]catalog.Column, 0)
-	for _, colDef := range stmt.TableSpec.Columns {
-		colName := colDef.Name.String()
-		colTypeStr := strings.ToUpper(colDef.Type.Type)
-
-		// 解析数据类型
-		dataType, err := catalog.ParseDataType(colTypeStr)
+	autoIncrement := make([]catalog.Column, 0)
+	for _, colDef := range stmt.Columns {
+		column, isAutoIncrement, err := e.buildColumn(tableName, colDef)
 		if err != nil {
-			return "", fmt.Errorf("unsupported column type: %s", colTypeStr)
+			return "", err
+		}
+		columns = append(columns, column)
+		if isAutoIncrement {
+			autoIncrement = append(autoIncrement, column)
 		}
-
-		columns = append(columns, catalog.Column{
-			Name: colName,
-			Type: dataType,
-		})
 	}
 
 	if len(columns) == 0 {
 		return "", fmt.Errorf("table must have at least one column")
 	}
 
-	// 创建表存储
-	tableStorage, err := storage.NewTableStorage(e.pager, len(columns))
-	if err != nil {
+	schema := &catalog.TableSchema{
+		Name:      tableName,
+		Columns:   columns,
+		Temporary: stmt.Temporary,
+	}
+	if stmt.OnCommit != "PRESERVE ROWS" {
+		schema.OnCommit = stmt.OnCommit
+	}
+
+	// 分区方式
+	if stmt.Partition != nil {
+		if schema.Partitioning, err = e.buildPartitioning(schema, stmt.Partition); err != nil {
+			return "", err
+		}
+	}
+
+	// 解析约束
+	for _, constraint := range stmt.Constraints {
+		switch constraint.Kind {
+		case parser.ConstraintPrimaryKey:
+			if schema.PrimaryKey != "" {
+				return "", fmt.Errorf("multiple primary keys for table %s are not allowed", tableName)
+			}
+			if len(constraint.Columns) != 1 {
+				return "", fmt.Errorf("composite primary keys are not supported")
+			}
+			if schema.GetColumnIndex(constraint.Columns[0]) == -1 {
+				return "", fmt.Errorf("column not found: %s", constraint.Columns[0])
+			}
+			// 主键列不允许 NULL
+			schema.Columns[schema.GetColumnIndex(constraint.Columns[0])].NotNull = true
+			schema.PrimaryKey = constraint.Columns[0]
+			schema.PrimaryKeyIndex = schemaQualified(tableName, constraint.Name)
+			if constraint.Name == "" {
+				schema.PrimaryKeyIndex = tableName + "_pkey"
+			}
+		case parser.ConstraintUnique:
+			if len(constraint.Columns) != 1 {
+				return "", fmt.Errorf("composite unique constraints are not supported")
+			}
+			if schema.GetColumnIndex(constraint.Columns[0]) == -1 {
+				return "", fmt.Errorf("column not found: %s", constraint.Columns[0])
+			}
+		}
+	}
+
+	// 外键（在主键和 UNIQUE 之后解析，自引用外键可以引用它们）
+	for _, constraint := range stmt.Constraints {
+		if constraint.Kind != parser.ConstraintForeignKey {
+			continue
+		}
+		fk, err := e.resolveForeignKey(schema, constraint, stmt.Constraints)
+		if err != nil {
+			return "", err
+		}
+		schema.ForeignKeys = append(schema.ForeignKeys, fk)
+	}
+
+	// CHECK 约束
+	for _, constraint := range stmt.Constraints {
+		if constraint.Kind != parser.ConstraintCheck {
+			continue
+		}
+		check, err := e.resolveCheck(schema, constraint)
+		if err != nil {
+			return "", err
+		}
+		schema.Checks = append(schema.Checks, check)
+	}
+
+	// 检查生成列的表达式，VIRTUAL 生成列不能作为主键
+	if err := e.validateGeneratedColumns(schema); err != nil {
+		return "", err
+	}
+	if colIndex := schema.GetColumnIndex(schema.PrimaryKey); colIndex != -1 && schema.Columns[colIndex].IsVirtual() {
+		return "", fmt.Errorf("primary key on virtual generated column %s is not supported", schema.PrimaryKey)
+	}
+
+	// 检查默认值能否转换为列的类型
+	for _, column := range schema.Columns {
+		if err := e.validateColumnDefault(column); err != nil {
+			return "", err
+		}
+	}
+
+	// 事务中建表时加写锁，回滚时撤销
+	if err := e.lockDDL(tableName); err != nil {
+		return "", err
+	}
+
+	// 临时表的行保存在本会话的临时文件中
+	if schema.Temporary {
+		if err := e.ensureTempPager(); err != nil {
+			return "", err
+		}
+	}
+
+	// 创建表存储（分区表每个分区一个页链）
+	if _, err := catalog.AllocateTableStorage(e.pagerFor(schema), schema); err != nil {
 		return "", fmt.Errorf("failed to create table storage: %w", err)
 	}
 
 	// 在 catalog 中创建表
-	err = e.catalog.CreateTable(tableName, columns, tableStorage.GetFirstPageID())
-	if err != nil {
+	if err := e.catalog.CreateTable(schema); err != nil {
 		return "", err
 	}
 
+	for _, column := range autoIncrement {
+		if err := e.createColumnSequence(tableName, column); err != nil {
+			e.dropTableOnError(tableName)
+			return "", err
+		}
+	}
+
+	// 主键和 UNIQUE 约束由唯一索引保证
+	if schema.PrimaryKey != "" {
+		if _, err := e.createIndex(schema.PrimaryKeyIndex, tableName, schema.PrimaryKey, "", true); err != nil {
+			e.dropTableOnError(tableName)
+			return "", fmt.Errorf("failed to create primary key index: %w", err)
+		}
+	}
+	for _, constraint := range stmt.Constraints {
+		if constraint.Kind != parser.ConstraintUnique {
+			continue
+		}
+		indexName := schemaQualified(tableName, constraint.Name)
+		if constraint.Name == "" {
+			indexName = fmt.Sprintf("%s_%s_key", tableName, constraint.Columns[0])
+		}
+		if _, err := e.createIndex(indexName, tableName, constraint.Columns[0], "", true); err != nil {
+			e.dropTableOnError(tableName)
+			return "", fmt.Errorf("failed to create unique index: %w", err)
+		}
+	}
+
+	e.logCreateTable(tableName)
 	return fmt.Sprintf("Table '%s' created successfully", tableName), nil
 }
 
+// dropTableOnError 建表过程中出错时删除已创建的表和索引，并释放表和索引的页
+func (e *Executor) dropTableOnError(tableName string) {
+	var pageIDs []uint32
+	pager := e.pager
+	if schema, err := e.catalog.GetTable(tableName); err == nil {
+		pager = e.pagerFor(schema)
+		if tableStorage, err := catalog.CreateTableStorage(pager, schema); err == nil {
+			pageIDs, _ = tableStorage.GetPageIDs()
+		}
+	}
+	if err := e.catalog.DropTable(tableName); err != nil {
+		return
+	}
+	freeIndexes(e.indexManager.DropIndexesByTable(tableName))
+	pager.FreePages(pageIDs)
+}
+
+// buildColumn 根据列定义创建列（类型、排序规则、NOT NULL、DEFAULT）
+// SERIAL 和 AUTO_INCREMENT 列的默认值为所属序列的 nextval，返回的 bool 表示需要创建该序列
+func (e *Executor) buildColumn(tableName string, colDef *parser.ColumnDef) (catalog.Column, bool, error) {
+	// SERIAL 是自增整数列的简写
+	if intType, ok := serialTypes[colDef.Type]; ok {
+		colDef.Type = intType
+		colDef.AutoIncrement = true
+	}
+
+	// 解析数据类型
+	column, err := e.resolveColumnType(colDef)
+	if err != nil {
+		return catalog.Column{}, false, err
+	}
+
+	// 排序规则
+	if colDef.Collation != "" {
+		if err := column.SetCollation(colDef.Collation); err != nil {
+			return catalog.Column{}, false, err
+		}
+	}
+
+	column.NotNull = colDef.NotNull || colDef.PrimaryKey
+	column.Default = colDef.Default
+
+	// 生成列的值由表达式计算，不能同时有默认值
+	if colDef.Generated != "" {
+		if colDef.Default != "" || colDef.AutoIncrement {
+			return catalog.Column{}, false, fmt.Errorf("both default and generation expression specified for column %s", column.Name)
+		}
+		column.Generated = colDef.Generated
+		column.Stored = colDef.Stored
+	}
+
+	// 自增列从所属的序列取默认值
+	if colDef.AutoIncrement {
+		if column.Type != types.TypeInt {
+			return catalog.Column{}, false, fmt.Errorf("AUTO_INCREMENT column %s must be an integer type", column.Name)
+		}
+		if column.Default != "" {
+			return catalog.Column{}, false, fmt.Errorf("multiple default values specified for column %s", column.Name)
+		}
+		column.Default = fmt.Sprintf("nextval('%s')", columnSequenceName(tableName, column.Name))
+		column.NotNull = true
+	}
+
+	return column, colDef.AutoIncrement, nil
+}
+
+// validateColumnDefault 检查列的默认值能否转换为列的类型并满足列的约束
+// 调用 nextval 的默认值不试算，避免消耗序列的值
+func (e *Executor) validateColumnDefault(column catalog.Column) error {
+	if column.Default == "" {
+		return nil
+	}
+	expr, err := parser.ParseExpr(column.Default)
+	if err != nil {
+		return err
+	}
+	if hasSequenceCall(expr) {
+		return nil
+	}
+
+	value, err := e.columnDefault(column)
+	if err != nil {
+		return err
+	}
+	if err := column.Validate(value); err != nil {
+		return fmt.Errorf("invalid default value for column %s: %w", column.Name, err)
+	}
+	return nil
+}
+
+// resolveColumnType 根据列定义解析列类型（内置类型或 CREATE TYPE 定义的枚举类型）
+func (e *Executor) resolveColumnType(colDef *parser.ColumnDef) (catalog.Column, error) {
+	column, err := catalog.NewColumn(colDef.Name, colDef.Type, colDef.Length, colDef.Unsigned)
+	if err == nil {
+		return column, nil
+	}
+
+	enumType, enumErr := e.catalog.GetEnumType(colDef.Type)
+	if enumErr != nil {
+		return catalog.Column{}, fmt.Errorf("unsupported column type: %s", colDef.Type)
+	}
+	return catalog.NewEnumColumn(colDef.Name, enumType), nil
+}
+
 // executeDropTable 执行 DROP TABLE
 func (e *Executor) executeDropTable(stmt *sqlparser.DDL) (string, error) {
-	tableName := stmt.Table.Name.String()
-
-	err := e.catalog.DropTable(tableName)
+	tableName, err := e.resolveTableName(stmt.Table)
 	if err != nil {
 		return "", err
 	}
 
+	if schema, err := e.catalog.GetTable(tableName); err == nil && schema.View != nil {
+		return "", fmt.Errorf("%s is a materialized view, use DROP MATERIALIZED VIEW", tableName)
+	}
+
+	if err := e.dropTable(tableName); err != nil {
+		return "", err
+	}
+
 	return fmt.Sprintf("Table '%s' dropped successfully", tableName), nil
 }
+
+// dropTable 删除表（或物化视图）及其索引，事务中删除时保存元数据和索引用于回滚
+func (e *Executor) dropTable(tableName string) error {
+	def, err := e.catalog.GetTableDefinition(tableName)
+	if err != nil {
+		return err
+	}
+	if err := e.lockDDL(tableName); err != nil {
+		return err
+	}
+	indexes := e.indexManager.GetIndexesByTable(tableName)
+
+	if err := e.catalog.DropTable(tableName); err != nil {
+		return err
+	}
+
+	// 删除表上的索引
+	e.indexManager.DropIndexesByTable(tableName)
+
+	e.logDropTable(def, indexes)
+	return nil
+}
+
+// isCreateTable 检查是否是 CREATE TABLE 语句
+func isCreateTable(sql string) bool {
+	fields := strings.Fields(strings.ToUpper(sql))
+	if len(fields) >= 3 && fields[0] == "CREATE" && (fields[1] == "TEMPORARY" || fields[1] == "TEMP") {
+		return fields[2] == "TABLE"
+	}
+	return len(fields) >= 2 && fields[0] == "CREATE" && fields[1] == "TABLE"
+}
@@ -0,0 +1,39 @@
+package executor
+
+import "testing"
+
+// 声明的类型长度必须为正数，VARCHAR(0) 不会被当作不限长度
+func TestZeroLengthTypeRejected(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustFail(t, e, "CREATE TABLE notes (id INT PRIMARY KEY, body VARCHAR(0))", "length for type VARCHAR must be at least 1")
+	mustFail(t, e, "SELECT * FROM notes", "not found")
+
+	mustExec(t, e, "CREATE TABLE notes (id INT PRIMARY KEY, body VARCHAR(3))")
+	mustFail(t, e, "ALTER TABLE notes ADD COLUMN tag CHAR(0)", "length for type CHAR must be at least 1")
+	mustFail(t, e, "ALTER TABLE notes ALTER COLUMN body TYPE VARCHAR(0)", "length for type VARCHAR must be at least 1")
+
+	mustFail(t, e, "INSERT INTO notes VALUES (1, 'abcd')", "value too long for column body VARCHAR(3)")
+	expectRows(t, e, "SELECT column_name, data_type FROM godb_columns WHERE table_name = 'notes'",
+		"id\tINT",
+		"body\tVARCHAR(3)",
+	)
+}
+
+// CREATE TABLE IF NOT EXISTS 在表已存在时跳过，不修改已有的表
+func TestCreateTableIfNotExists(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE IF NOT EXISTS notes (id INT PRIMARY KEY)",
+		"INSERT INTO notes VALUES (1)",
+	)
+
+	result, err := e.Execute("CREATE TABLE IF NOT EXISTS notes (a INT, b TEXT)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "Table 'notes' already exists, skipped" {
+		t.Fatalf("unexpected result %q", result)
+	}
+	mustFail(t, e, "CREATE TABLE notes (a INT)", "already exists")
+	expectRows(t, e, "SELECT * FROM notes", "1")
+}
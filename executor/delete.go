@@ -3,9 +3,7 @@ package executor
 import (
 	"fmt"
 	"godb/catalog"
-	"godb/storage"
 	"godb/transaction"
-	"godb/types"
 
 	"github.com/xwb1989/sqlparser"
 )
@@ -13,10 +11,13 @@ import (
 // executeDelete 执行 DELETE 语句
 func (e *Executor) executeDelete(stmt *sqlparser.Delete) (string, error) {
 	// 获取表名
-	tableName := stmt.TableExprs[0].(*sqlparser.AliasedTableExpr).Expr.(sqlparser.TableName).Name.String()
+	tableName, err := e.resolveTableName(stmt.TableExprs[0].(*sqlparser.AliasedTableExpr).Expr.(sqlparser.TableName))
+	if err != nil {
+		return "", err
+	}
 
 	// 获取表定义
-	schema, err := e.catalog.GetTable(tableName)
+	schema, err := e.getWritableTable(tableName)
 	if err != nil {
 		return "", err
 	}
@@ -28,81 +29,48 @@ func (e *Executor) executeDelete(stmt *sqlparser.Delete) (string, error) {
 		return "", fmt.Errorf("failed to acquire write lock: %w", err)
 	}
 
+	// 如果是自动提交模式，在操作完成后释放锁（出错时也要释放）
+	if e.currentTx == nil {
+		defer lockManager.ReleaseLocks(transaction.TransactionID(txID))
+	}
+
 	// 创建表存储
-	tableStorage, err := catalog.CreateTableStorage(e.pager, schema)
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
 	if err != nil {
 		return "", err
 	}
 
-	// 读取所有行（包括已删除的，但我们只处理未删除的）
-	allRows, err := tableStorage.GetAllRowsWithDeleted(true)
+	// 读取满足 WHERE 条件的行（主键等值条件使用索引）
+	matchedRows, err := e.scanRows(tableName, stmt.Where, schema, tableStorage)
 	if err != nil {
 		return "", err
 	}
 
-	// 过滤并删除行
-	deleteCount := 0
-	for _, row := range allRows {
-		// 跳过已删除的行
-		if row.Deleted {
-			continue
-		}
-
-		// 应用 WHERE 条件
-		match := true
-		if stmt.Where != nil {
-			match, err = e.evaluateCondition(row, stmt.Where.Expr, schema)
-			if err != nil {
-				return "", err
-			}
-		}
-
-		if match {
-			// 保存旧行数据（用于回滚）
-			oldRowCopy := &storage.Row{
-				ID:      row.ID,
-				Deleted: row.Deleted,
-				TxID:    row.TxID,
-				Values:  make([]types.Value, len(row.Values)),
-			}
-			copy(oldRowCopy.Values, row.Values)
-
-			// 删除索引条目
-			columnNames := make([]string, len(schema.Columns))
-			for i, col := range schema.Columns {
-				columnNames[i] = col.Name
-			}
-			if err := e.indexManager.DeleteEntry(tableName, row, columnNames); err != nil {
-				return "", fmt.Errorf("failed to delete index entry: %w", err)
-			}
-
-			// 标记行为删除
-			if err := tableStorage.MarkRowDeleted(row.ID); err != nil {
-				return "", fmt.Errorf("failed to delete row: %w", err)
-			}
+	if err := e.fireTriggers(schema, "BEFORE", "DELETE", matchedRows, nil); err != nil {
+		return "", err
+	}
 
-			// 记录操作到事务日志（用于回滚）
-			if e.currentTx != nil {
-				op := &transaction.Operation{
-					Type:      transaction.OpDelete,
-					TableName: tableName,
-					RowID:     row.ID,
-					OldData:   oldRowCopy,
-				}
-				e.currentTx.AddOperation(op)
-			}
+	// 收集要删除的行，检查约束后再统一写入
+	ws := &writeSet{}
+	tw, err := e.writesFor(ws, tableName)
+	if err != nil {
+		return "", err
+	}
+	for _, row := range matchedRows {
+		tw.delete(row)
+	}
 
-			deleteCount++
-		}
+	// 外键的 ON DELETE 动作
+	if err := e.cascadeDeletes(ws, tw, matchedRows, 0); err != nil {
+		return "", err
 	}
 
-	// 如果是自动提交模式，立即释放锁和刷新
-	if e.currentTx == nil {
-		lockManager.ReleaseLocks(transaction.TransactionID(txID))
-		if err := e.pager.FlushAll(); err != nil {
-			return "", fmt.Errorf("failed to flush pages: %w", err)
-		}
+	if err := e.applyWriteSet(ws); err != nil {
+		return "", err
+	}
+	if err := e.fireTriggers(schema, "AFTER", "DELETE", matchedRows, nil); err != nil {
+		return "", err
 	}
 
-	return fmt.Sprintf("%d row(s) deleted", deleteCount), nil
+	return fmt.Sprintf("%d row(s) deleted", len(matchedRows)), nil
 }
@@ -2,12 +2,12 @@ package executor
 
 import (
 	"fmt"
+	"github.com/xwb1989/sqlparser"
 	"godb/catalog"
 	"godb/index"
 	"godb/parser"
 	"godb/storage"
 	"godb/transaction"
-	"github.com/xwb1989/sqlparser"
 )
 
 // Executor 查询执行器
@@ -17,6 +17,19 @@ type Executor struct {
 	indexManager *index.IndexManager
 	txManager    *transaction.TransactionManager
 	currentTx    *transaction.Transaction // 当前活跃事务（nil表示自动提交模式）
+
+	constraintModes    map[string]bool // SET CONSTRAINTS 设置的延迟模式（约束名 -> 是否延迟，空名表示 ALL）
+	pendingForeignKeys map[string]bool // 当前事务中延迟到提交时检查的外键
+
+	sequenceValues map[string]int64 // 本会话中 nextval 最近返回的值（用于 currval）
+
+	searchPath []string // 本会话的 search_path（nil 表示只有 public）
+
+	attached map[string]*attachedDatabase // ATTACH DATABASE 附加的数据库（别名 -> 数据库）
+
+	triggerDepth int // 正在执行的触发器的嵌套深度
+
+	tempPager *storage.Pager // 本会话临时表使用的页管理器（第一次创建临时表时创建）
 }
 
 // NewExecutor 创建执行器
@@ -37,6 +50,74 @@ func (e *Executor) Execute(sql string) (string, error) {
 		return e.executeTransactionCommand(sql)
 	}
 
+	if isSetConstraints(sql) {
+		return e.executeSetConstraints(sql)
+	}
+	if isSetSearchPath(sql) {
+		return e.executeSetSearchPath(sql)
+	}
+
+	if isAttach(sql) {
+		return e.executeAttach(sql)
+	}
+	if isDetach(sql) {
+		return e.executeDetach(sql)
+	}
+
+	if isCreateSchema(sql) {
+		return e.executeCreateSchema(sql)
+	}
+	if isDropSchema(sql) {
+		return e.executeDropSchema(sql)
+	}
+
+	// CREATE TABLE 使用自己的解析器
+	if isCreateTable(sql) {
+		return e.executeCreateTable(sql)
+	}
+
+	if isCreateSequence(sql) {
+		return e.executeCreateSequence(sql)
+	}
+	if isDropSequence(sql) {
+		return e.executeDropSequence(sql)
+	}
+
+	if isCreateView(sql) {
+		return e.executeCreateView(sql)
+	}
+	if isDropView(sql) {
+		return e.executeDropView(sql)
+	}
+	if isRefreshMaterializedView(sql) {
+		return e.executeRefreshMaterializedView(sql)
+	}
+
+	if isCreateTrigger(sql) {
+		return e.executeCreateTrigger(sql)
+	}
+	if isDropTrigger(sql) {
+		return e.executeDropTrigger(sql)
+	}
+
+	if isAlterTable(sql) {
+		return e.executeAlterTable(sql)
+	}
+	if isAnalyze(sql) {
+		return e.executeAnalyze(sql)
+	}
+
+	// 检查是否是类型相关语句
+	if isCreateType(sql) {
+		return e.executeCreateType(sql)
+	}
+	if isAlterType(sql) {
+		return e.executeAlterType(sql)
+	}
+	if isDropType(sql) {
+		return e.executeDropType(sql)
+	}
+
 	// 检查是否是索引相关语句
 	if isCreateIndex(sql) {
 		return e.executeCreateIndex(sql)
@@ -45,6 +126,14 @@ func (e *Executor) Execute(sql string) (string, error) {
 		return e.executeDropIndex(sql)
 	}
 
+	// 检查是否是表结构查看语句
+	if isDescribe(sql) {
+		return e.executeDescribe(sql)
+	}
+	if isShow(sql) {
+		return e.executeShow(sql)
+	}
+
 	// 解析 SQL
 	stmt, err := parser.Parse(sql)
 	if err != nil {
@@ -56,13 +145,13 @@ func (e *Executor) Execute(sql string) (string, error) {
 	case *sqlparser.DDL:
 		return e.executeDDL(stmt)
 	case *sqlparser.Insert:
-		return e.executeInsert(stmt)
+		return e.executeDML(stmt.Table, func() (string, error) { return e.executeInsert(stmt) })
 	case *sqlparser.Select:
 		return e.executeSelect(stmt)
 	case *sqlparser.Update:
-		return e.executeUpdate(stmt)
+		return e.executeDML(dmlTable(stmt.TableExprs), func() (string, error) { return e.executeUpdate(stmt) })
 	case *sqlparser.Delete:
-		return e.executeDelete(stmt)
+		return e.executeDML(dmlTable(stmt.TableExprs), func() (string, error) { return e.executeDelete(stmt) })
 	default:
 		return "", fmt.Errorf("unsupported statement type")
 	}
@@ -71,8 +160,6 @@ func (e *Executor) Execute(sql string) (string, error) {
 // executeDDL 执行 DDL 语句（CREATE, DROP 等）
 func (e *Executor) executeDDL(stmt *sqlparser.DDL) (string, error) {
 	switch stmt.Action {
-	case "create":
-		return e.executeCreateTable(stmt)
 	case "drop":
 		return e.executeDropTable(stmt)
 	default:
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"godb/catalog"
+	"godb/index"
+	"godb/storage"
+	"godb/transaction"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestExecutor 在临时目录中创建数据库，返回执行器和数据文件名
+func newTestExecutor(t *testing.T) (*Executor, string) {
+	t.Helper()
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "test.db")
+
+	pager, err := storage.NewPager(dbFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalogMgr, err := catalog.NewCatalog(filepath.Join(dir, "test_meta.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewExecutor(catalogMgr, pager, index.NewIndexManager(), transaction.NewTransactionManager(pager, catalogMgr))
+	t.Cleanup(func() {
+		e.Close()
+		pager.Close()
+	})
+	return e, dbFile
+}
+
+// mustExec 执行语句，出错时测试失败
+func mustExec(t *testing.T, e *Executor, sqls ...string) {
+	t.Helper()
+	for _, sql := range sqls {
+		if _, err := e.Execute(sql); err != nil {
+			t.Fatalf("%s: %v", sql, err)
+		}
+	}
+}
+
+// mustFail 执行语句，没有出错或错误信息不包含 want 时测试失败
+func mustFail(t *testing.T, e *Executor, sql, want string) {
+	t.Helper()
+	_, err := e.Execute(sql)
+	if err == nil {
+		t.Fatalf("%s: expected error containing %q", sql, want)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("%s: error %q does not contain %q", sql, err, want)
+	}
+}
+
+// queryRows 执行查询，返回结果的各行（每行的列用制表符分隔）
+func queryRows(t *testing.T, e *Executor, sql string) []string {
+	t.Helper()
+	result, err := e.Execute(sql)
+	if err != nil {
+		t.Fatalf("%s: %v", sql, err)
+	}
+	lines := strings.Split(result, "\n")
+	rows := make([]string, 0)
+	for _, line := range lines[2:] {
+		if line == "" {
+			break
+		}
+		rows = append(rows, line)
+	}
+	return rows
+}
+
+// expectRows 执行查询并比较结果的各行
+func expectRows(t *testing.T, e *Executor, sql string, want ...string) {
+	t.Helper()
+	got := queryRows(t, e, sql)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("%s:\ngot  %q\nwant %q", sql, got, want)
+	}
+}
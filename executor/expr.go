@@ -0,0 +1,407 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/types"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// castTypeAliases MySQL CAST/CONVERT 目标类型到 godb 类型名的映射
+var castTypeAliases = map[string]string{
+	"SIGNED":   "BIGINT",
+	"UNSIGNED": "BIGINT",
+	"CHAR":     "TEXT",
+	"NCHAR":    "TEXT",
+	"BINARY":   "TEXT",
+	"DECIMAL":  "DOUBLE",
+	"NUMERIC":  "DOUBLE",
+}
+
+// evalExpr 计算常量表达式的值，并隐式转换为期望的类型
+func (e *Executor) evalExpr(expr sqlparser.Expr, expectedType types.DataType) (types.Value, error) {
+	value, err := e.evalRowExpr(nil, expr, nil)
+	if err != nil {
+		return types.Value{}, err
+	}
+	return types.ImplicitCast(value, expectedType)
+}
+
+// evalColumnExpr 按列定义计算常量表达式的值（用于赋值和与列比较）
+func (e *Executor) evalColumnExpr(expr sqlparser.Expr, column catalog.Column) (types.Value, error) {
+	value, err := e.evalRowExpr(nil, expr, nil)
+	if err != nil {
+		return types.Value{}, err
+	}
+	return e.coerceToColumn(value, column)
+}
+
+// evalAssignExpr 计算赋值给列的表达式（INSERT 的值和 UPDATE 的 SET），DEFAULT 关键字取列的默认值
+func (e *Executor) evalAssignExpr(expr sqlparser.Expr, column catalog.Column) (types.Value, error) {
+	if _, ok := expr.(*sqlparser.Default); ok {
+		return e.columnDefault(column)
+	}
+	return e.evalColumnExpr(expr, column)
+}
+
+// columnDefault 计算列的默认值，没有声明 DEFAULT 时为 NULL
+func (e *Executor) columnDefault(column catalog.Column) (types.Value, error) {
+	if column.Default == "" {
+		return types.NewNullValue(), nil
+	}
+	expr, err := parser.ParseExpr(column.Default)
+	if err != nil {
+		return types.Value{}, err
+	}
+	value, err := e.evalColumnExpr(expr, column)
+	if err != nil {
+		return types.Value{}, fmt.Errorf("invalid default value for column %s: %w", column.Name, err)
+	}
+	return value, nil
+}
+
+// coerceToColumn 把值隐式转换为列的类型（枚举列需要把标签转换为序号）
+func (e *Executor) coerceToColumn(value types.Value, column catalog.Column) (types.Value, error) {
+	if column.Type == types.TypeEnum && value.Type == types.TypeText {
+		return e.enumFromLabel(column.TypeName, value)
+	}
+	return types.ImplicitCast(value, column.Type)
+}
+
+// evalRowExpr 在行上下文中计算表达式的值
+// row 和 schema 可以为 nil，此时表达式中不能引用列
+func (e *Executor) evalRowExpr(row *storage.Row, expr sqlparser.Expr, schema *catalog.TableSchema) (types.Value, error) {
+	switch expr := expr.(type) {
+	case *sqlparser.SQLVal:
+		return e.evalSQLVal(expr)
+
+	case sqlparser.BoolVal:
+		return types.NewBooleanValue(bool(expr)), nil
+
+	case *sqlparser.NullVal:
+		return types.NewNullValue(), nil
+
+	case *sqlparser.ColName:
+		if row == nil || schema == nil {
+			return types.Value{}, fmt.Errorf("column reference not allowed here: %s", expr.Name.String())
+		}
+		colIndex := schema.GetColumnIndex(expr.Name.String())
+		if colIndex == -1 {
+			return types.Value{}, fmt.Errorf("column not found: %s", expr.Name.String())
+		}
+		return row.Values[colIndex], nil
+
+	case *sqlparser.ParenExpr:
+		return e.evalRowExpr(row, expr.Expr, schema)
+
+	case *sqlparser.UnaryExpr:
+		value, err := e.evalRowExpr(row, expr.Expr, schema)
+		if err != nil {
+			return types.Value{}, err
+		}
+		return negateValue(expr.Operator, value)
+
+	case *sqlparser.BinaryExpr:
+		left, err := e.evalRowExpr(row, expr.Left, schema)
+		if err != nil {
+			return types.Value{}, err
+		}
+		right, err := e.evalRowExpr(row, expr.Right, schema)
+		if err != nil {
+			return types.Value{}, err
+		}
+		return evalArithmetic(expr.Operator, left, right)
+
+	case *sqlparser.CollateExpr:
+		// 排序规则只影响比较，不改变值
+		if _, err := types.ParseCollation(expr.Charset); err != nil {
+			return types.Value{}, err
+		}
+		return e.evalRowExpr(row, expr.Expr, schema)
+
+	case *sqlparser.ConvertExpr:
+		return e.evalCast(row, expr.Expr, convertTypeName(expr.Type), schema)
+
+	case *sqlparser.FuncExpr:
+		return e.evalFuncExpr(row, expr, schema)
+
+	default:
+		return types.Value{}, fmt.Errorf("unsupported expression type: %T", expr)
+	}
+}
+
+// evalSQLVal 计算 SQL 字面量
+func (e *Executor) evalSQLVal(val *sqlparser.SQLVal) (types.Value, error) {
+	switch val.Type {
+	case sqlparser.IntVal:
+		intVal, err := strconv.ParseInt(string(val.Val), 10, 64)
+		if err != nil {
+			return types.Value{}, err
+		}
+		return types.NewIntValue(intVal), nil
+
+	case sqlparser.StrVal:
+		return types.NewTextValue(string(val.Val)), nil
+
+	case sqlparser.FloatVal:
+		floatVal, err := strconv.ParseFloat(string(val.Val), 64)
+		if err != nil {
+			return types.Value{}, err
+		}
+		return types.NewFloatValue(floatVal), nil
+
+	default:
+		return types.Value{}, fmt.Errorf("unsupported value type: %v", val.Type)
+	}
+}
+
+// negateValue 计算一元运算，NULL 的运算结果仍是 NULL
+func negateValue(operator string, value types.Value) (types.Value, error) {
+	if value.IsNull() && (operator == sqlparser.UPlusStr || operator == sqlparser.UMinusStr) {
+		return value, nil
+	}
+
+	switch operator {
+	case sqlparser.UPlusStr:
+		if value.Type == types.TypeInt || value.Type == types.TypeFloat {
+			return value, nil
+		}
+	case sqlparser.UMinusStr:
+		switch value.Type {
+		case types.TypeInt:
+			intVal, _ := value.AsInt()
+			return types.NewIntValue(-intVal), nil
+		case types.TypeFloat:
+			floatVal, _ := value.AsFloat()
+			return types.NewFloatValue(-floatVal), nil
+		}
+	default:
+		return types.Value{}, fmt.Errorf("unsupported unary operator: %s", operator)
+	}
+	return types.Value{}, fmt.Errorf("operator %s cannot be applied to %s", strings.TrimSpace(operator), value.Type)
+}
+
+// evalArithmetic 计算算术运算（+、-、*、/、%），NULL 的运算结果仍是 NULL
+// 两个整数的运算结果是整数（除法向零取整），有一个是浮点数时结果是浮点数
+func evalArithmetic(operator string, left, right types.Value) (types.Value, error) {
+	if left.IsNull() || right.IsNull() {
+		return types.NewNullValue(), nil
+	}
+	if !isNumeric(left) || !isNumeric(right) {
+		return types.Value{}, fmt.Errorf("operator %s cannot be applied to %s and %s", operator, left.Type, right.Type)
+	}
+
+	if left.Type == types.TypeInt && right.Type == types.TypeInt {
+		a, _ := left.AsInt()
+		b, _ := right.AsInt()
+		switch operator {
+		case sqlparser.PlusStr:
+			return types.NewIntValue(a + b), nil
+		case sqlparser.MinusStr:
+			return types.NewIntValue(a - b), nil
+		case sqlparser.MultStr:
+			return types.NewIntValue(a * b), nil
+		case sqlparser.DivStr, sqlparser.IntDivStr:
+			if b == 0 {
+				return types.Value{}, fmt.Errorf("division by zero")
+			}
+			return types.NewIntValue(a / b), nil
+		case sqlparser.ModStr:
+			if b == 0 {
+				return types.Value{}, fmt.Errorf("division by zero")
+			}
+			return types.NewIntValue(a % b), nil
+		}
+		return types.Value{}, fmt.Errorf("unsupported binary operator: %s", operator)
+	}
+
+	a, _ := types.ImplicitCast(left, types.TypeFloat)
+	b, _ := types.ImplicitCast(right, types.TypeFloat)
+	x, _ := a.AsFloat()
+	y, _ := b.AsFloat()
+	switch operator {
+	case sqlparser.PlusStr:
+		return types.NewFloatValue(x + y), nil
+	case sqlparser.MinusStr:
+		return types.NewFloatValue(x - y), nil
+	case sqlparser.MultStr:
+		return types.NewFloatValue(x * y), nil
+	case sqlparser.DivStr:
+		if y == 0 {
+			return types.Value{}, fmt.Errorf("division by zero")
+		}
+		return types.NewFloatValue(x / y), nil
+	case sqlparser.IntDivStr:
+		if y == 0 {
+			return types.Value{}, fmt.Errorf("division by zero")
+		}
+		return types.NewIntValue(int64(x / y)), nil
+	case sqlparser.ModStr:
+		if y == 0 {
+			return types.Value{}, fmt.Errorf("division by zero")
+		}
+		return types.NewFloatValue(math.Mod(x, y)), nil
+	}
+	return types.Value{}, fmt.Errorf("unsupported binary operator: %s", operator)
+}
+
+// isNumeric 判断值是否是数值（整数或浮点数）
+func isNumeric(value types.Value) bool {
+	return value.Type == types.TypeInt || value.Type == types.TypeFloat
+}
+
+// evalFuncExpr 计算函数调用
+func (e *Executor) evalFuncExpr(row *storage.Row, expr *sqlparser.FuncExpr, schema *catalog.TableSchema) (types.Value, error) {
+	funcName := expr.Name.Lowered()
+
+	switch funcName {
+	case parser.CastFunc:
+		// 由 CAST(x AS type) 或 x::type 改写而来
+		if len(expr.Exprs) != 2 {
+			return types.Value{}, fmt.Errorf("invalid CAST expression")
+		}
+		operand, ok := expr.Exprs[0].(*sqlparser.AliasedExpr)
+		if !ok {
+			return types.Value{}, fmt.Errorf("invalid CAST expression")
+		}
+		typeExpr, ok := expr.Exprs[1].(*sqlparser.AliasedExpr)
+		if !ok {
+			return types.Value{}, fmt.Errorf("invalid CAST expression")
+		}
+		typeVal, ok := typeExpr.Expr.(*sqlparser.SQLVal)
+		if !ok || typeVal.Type != sqlparser.StrVal {
+			return types.Value{}, fmt.Errorf("invalid CAST expression")
+		}
+		return e.evalCast(row, operand.Expr, string(typeVal.Val), schema)
+
+	case "gen_random_uuid":
+		if len(expr.Exprs) != 0 {
+			return types.Value{}, fmt.Errorf("%s() takes no arguments", funcName)
+		}
+		uuidVal, err := types.NewRandomUUID()
+		if err != nil {
+			return types.Value{}, err
+		}
+		return types.NewUUIDValue(uuidVal), nil
+
+	case "now", "current_timestamp", "localtimestamp", "localtime":
+		if len(expr.Exprs) != 0 {
+			return types.Value{}, fmt.Errorf("%s() takes no arguments", funcName)
+		}
+		return types.NewDateValue(time.Now()), nil
+
+	case "nextval", "currval", "setval":
+		return e.evalSequenceFunc(row, expr, schema)
+
+	case "current_date", "curdate":
+		if len(expr.Exprs) != 0 {
+			return types.Value{}, fmt.Errorf("%s() takes no arguments", funcName)
+		}
+		year, month, day := time.Now().Date()
+		return types.NewDateValue(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)), nil
+
+	default:
+		return types.Value{}, fmt.Errorf("unsupported function: %s", funcName)
+	}
+}
+
+// evalCast 计算显式类型转换
+// 目标类型按列定义中的类型解析：转换为 VARCHAR(n)/CHAR(n) 时截断到 n 个字符，整数超出类型范围时报错
+func (e *Executor) evalCast(row *storage.Row, operand sqlparser.Expr, typeName string, schema *catalog.TableSchema) (types.Value, error) {
+	value, err := e.evalRowExpr(row, operand, schema)
+	if err != nil {
+		return types.Value{}, err
+	}
+
+	colDef, err := parser.ParseCastType(typeName)
+	if err != nil {
+		return types.Value{}, fmt.Errorf("invalid cast target type %s: %w", typeName, err)
+	}
+	if alias, ok := castTypeAliases[colDef.Type]; ok {
+		colDef.Type = alias
+	}
+	target, err := e.resolveColumnType(colDef)
+	if err != nil {
+		return types.Value{}, fmt.Errorf("unsupported cast target type: %s", typeName)
+	}
+
+	// 枚举列转换为文本或枚举时按标签转换
+	if value.Type == types.TypeEnum && (target.Type == types.TypeText || target.Type == types.TypeEnum) {
+		if column, ok := exprColumn(operand, schema); ok {
+			value = types.NewTextValue(e.formatValue(column, value))
+		}
+	}
+
+	result, err := e.convertToColumn(value, target)
+	if err != nil {
+		return types.Value{}, err
+	}
+
+	switch result.Type {
+	case types.TypeText:
+		if target.Length > 0 {
+			text, _ := result.AsText()
+			if runes := []rune(text); len(runes) > target.Length {
+				result = types.NewTextValue(string(runes[:target.Length]))
+			}
+		}
+	case types.TypeInt:
+		intVal, _ := result.AsInt()
+		if min, max := target.IntRange(); intVal < min || intVal > max {
+			return types.Value{}, fmt.Errorf("value %d out of range for type %s", intVal, target.DeclaredType())
+		}
+	}
+	return result, nil
+}
+
+// convertTypeName 返回 CONVERT(x, type) 的目标类型（带长度修饰）
+func convertTypeName(convertType *sqlparser.ConvertType) string {
+	name := convertType.Type
+	if convertType.Length != nil {
+		name += "(" + string(convertType.Length.Val)
+		if convertType.Scale != nil {
+			name += ", " + string(convertType.Scale.Val)
+		}
+		name += ")"
+	}
+	return name
+}
+
+// enumFromLabel 把文本标签转换为枚举值
+func (e *Executor) enumFromLabel(typeName string, value types.Value) (types.Value, error) {
+	label, err := value.AsText()
+	if err != nil {
+		return types.Value{}, err
+	}
+
+	enumType, err := e.catalog.GetEnumType(typeName)
+	if err != nil {
+		return types.Value{}, err
+	}
+
+	ordinal, ok := enumType.Ordinal(label)
+	if !ok {
+		return types.Value{}, fmt.Errorf("invalid input value for enum %s: '%s'", enumType.Name, label)
+	}
+	return types.NewEnumValue(ordinal), nil
+}
+
+// exprColumn 如果表达式是列引用（可以带 COLLATE），返回对应的列定义
+func exprColumn(expr sqlparser.Expr, schema *catalog.TableSchema) (catalog.Column, bool) {
+	colName, ok := unwrapCollate(expr).(*sqlparser.ColName)
+	if !ok || schema == nil {
+		return catalog.Column{}, false
+	}
+	colIndex := schema.GetColumnIndex(colName.Name.String())
+	if colIndex == -1 {
+		return catalog.Column{}, false
+	}
+	return schema.Columns[colIndex], true
+}
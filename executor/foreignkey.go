@@ -0,0 +1,622 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/transaction"
+	"godb/types"
+	"regexp"
+	"strings"
+)
+
+// maxCascadeDepth 外键级联的最大深度，防止循环引用导致无限级联
+const maxCascadeDepth = 64
+
+// resolveForeignKey 根据 CREATE TABLE 中的外键声明构造外键定义
+// schema 是正在创建的表，自引用外键引用的列必须是该表的主键或 UNIQUE 列
+func (e *Executor) resolveForeignKey(schema *catalog.TableSchema, constraint *parser.TableConstraint, constraints []*parser.TableConstraint) (*catalog.ForeignKey, error) {
+	if len(constraint.Columns) != 1 || len(constraint.RefColumns) > 1 {
+		return nil, fmt.Errorf("composite foreign keys are not supported")
+	}
+
+	colIndex := schema.GetColumnIndex(constraint.Columns[0])
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column not found: %s", constraint.Columns[0])
+	}
+	column := schema.Columns[colIndex]
+
+	// 被引用的表（可以是正在创建的表自身）
+	refTable, err := e.resolveRelation(constraint.RefTable)
+	if err != nil {
+		return nil, err
+	}
+	refSchema := schema
+	if refTable != schema.Name {
+		refSchema, err = e.catalog.GetTable(refTable)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// 临时表在会话结束时删除，永久表不能引用临时表
+	if refSchema.Temporary && !schema.Temporary {
+		return nil, fmt.Errorf("constraints on permanent tables may reference only permanent tables")
+	}
+
+	refColumnName := refSchema.PrimaryKey
+	if len(constraint.RefColumns) == 1 {
+		refColumnName = constraint.RefColumns[0]
+	} else if refColumnName == "" {
+		return nil, fmt.Errorf("table %s has no primary key to reference", refSchema.Name)
+	}
+
+	refColIndex := refSchema.GetColumnIndex(refColumnName)
+	if refColIndex == -1 {
+		return nil, fmt.Errorf("column not found: %s.%s", refSchema.Name, refColumnName)
+	}
+	refColumn := refSchema.Columns[refColIndex]
+
+	// 被引用列必须唯一
+	unique := refSchema.PrimaryKey == refColumnName
+	if refSchema == schema {
+		for _, other := range constraints {
+			if other.Kind == parser.ConstraintUnique && len(other.Columns) == 1 && other.Columns[0] == refColumnName {
+				unique = true
+			}
+		}
+	} else {
+		for _, info := range e.catalog.GetIndexesByTable(refSchema.Name) {
+			if info.Unique && info.ColumnName == refColumnName {
+				unique = true
+			}
+		}
+	}
+	if !unique {
+		return nil, fmt.Errorf("referenced column %s.%s must have a primary key or unique constraint", refSchema.Name, refColumnName)
+	}
+
+	if column.Type != refColumn.Type || (column.Type == types.TypeEnum && column.TypeName != refColumn.TypeName) {
+		return nil, fmt.Errorf("foreign key column %s (%s) and referenced column %s.%s (%s) are of incompatible types",
+			column.Name, column.DeclaredType(), refSchema.Name, refColumn.Name, refColumn.DeclaredType())
+	}
+
+	onDelete, err := catalog.ParseReferentialAction(constraint.OnDelete)
+	if err != nil {
+		return nil, err
+	}
+	onUpdate, err := catalog.ParseReferentialAction(constraint.OnUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 生成列的值由表达式决定：VIRTUAL 生成列不能作为外键列，STORED 生成列不能使用修改该列的动作
+	if column.IsVirtual() {
+		return nil, fmt.Errorf("foreign key constraints on virtual generated column %s are not supported", column.Name)
+	}
+	if column.Generated != "" {
+		if onDelete == catalog.ActionSetNull || onDelete == catalog.ActionSetDefault {
+			return nil, fmt.Errorf("invalid ON DELETE action for foreign key on generated column %s", column.Name)
+		}
+		if onUpdate != catalog.ActionNoAction && onUpdate != catalog.ActionRestrict {
+			return nil, fmt.Errorf("invalid ON UPDATE action for foreign key on generated column %s", column.Name)
+		}
+	}
+
+	// 外键名在模式内唯一
+	name := schemaQualified(schema.Name, constraint.Name)
+	if constraint.Name == "" {
+		name = fmt.Sprintf("%s_%s_fkey", schema.Name, column.Name)
+	}
+	if _, err := e.catalog.GetForeignKey(name); err == nil {
+		return nil, fmt.Errorf("constraint already exists: %s", name)
+	}
+	for _, fk := range schema.ForeignKeys {
+		if fk.Name == name {
+			return nil, fmt.Errorf("constraint already exists: %s", name)
+		}
+	}
+
+	return &catalog.ForeignKey{
+		Name:              name,
+		Table:             schema.Name,
+		Column:            column.Name,
+		RefTable:          refSchema.Name,
+		RefColumn:         refColumn.Name,
+		OnDelete:          onDelete,
+		OnUpdate:          onUpdate,
+		Deferrable:        constraint.Deferrable || constraint.InitiallyDeferred,
+		InitiallyDeferred: constraint.InitiallyDeferred,
+	}, nil
+}
+
+// cascadeDeletes 对引用被删除行的外键执行 ON DELETE 动作
+// RESTRICT 和 NO ACTION 在 checkForeignKeys 中检查
+func (e *Executor) cascadeDeletes(ws *writeSet, parent *tableWrites, rows []*storage.Row, depth int) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if depth > maxCascadeDepth {
+		return fmt.Errorf("foreign key cascade depth exceeds %d", maxCascadeDepth)
+	}
+
+	for _, fk := range e.catalog.GetForeignKeysReferencing(parent.schema.Name) {
+		if fk.OnDelete == catalog.ActionNoAction || fk.OnDelete == catalog.ActionRestrict {
+			continue
+		}
+
+		refIndex := parent.schema.GetColumnIndex(fk.RefColumn)
+		for _, row := range rows {
+			key := row.Values[refIndex]
+			if key.IsNull() {
+				continue
+			}
+			if err := e.applyReferentialAction(ws, fk, fk.OnDelete, key, nil, depth); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// cascadeUpdates 对引用被更新行的外键执行 ON UPDATE 动作（只处理被引用列的值发生变化的行）
+func (e *Executor) cascadeUpdates(ws *writeSet, parent *tableWrites, changes []*rowUpdate, depth int) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if depth > maxCascadeDepth {
+		return fmt.Errorf("foreign key cascade depth exceeds %d", maxCascadeDepth)
+	}
+
+	for _, fk := range e.catalog.GetForeignKeysReferencing(parent.schema.Name) {
+		if fk.OnUpdate == catalog.ActionNoAction || fk.OnUpdate == catalog.ActionRestrict {
+			continue
+		}
+
+		refIndex := parent.schema.GetColumnIndex(fk.RefColumn)
+		collation := parent.schema.Columns[refIndex].Collation
+		for _, change := range changes {
+			oldKey, newKey := change.oldRow.Values[refIndex], change.newRow.Values[refIndex]
+			if oldKey.IsNull() || keysEqual(oldKey, newKey, collation) {
+				continue
+			}
+			if err := e.applyReferentialAction(ws, fk, fk.OnUpdate, oldKey, &newKey, depth); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyReferentialAction 对引用 key 的子表行执行引用动作
+// newKey 不为 nil 时表示父表的键被更新为 newKey（ON UPDATE CASCADE 使用）
+func (e *Executor) applyReferentialAction(ws *writeSet, fk *catalog.ForeignKey, action catalog.ReferentialAction, key types.Value, newKey *types.Value, depth int) error {
+	child, err := e.writesFor(ws, fk.Table)
+	if err != nil {
+		return err
+	}
+
+	rows, err := e.referencingRows(ws, fk, key)
+	if err != nil {
+		return err
+	}
+
+	if action == catalog.ActionCascade && newKey == nil {
+		// ON DELETE CASCADE：删除引用行
+		deleted := make([]*storage.Row, 0, len(rows))
+		for _, row := range rows {
+			if child.delete(row) {
+				deleted = append(deleted, row)
+			}
+		}
+		return e.cascadeDeletes(ws, child, deleted, depth+1)
+	}
+
+	colIndex := child.schema.GetColumnIndex(fk.Column)
+	column := child.schema.Columns[colIndex]
+
+	var value types.Value
+	switch action {
+	case catalog.ActionCascade:
+		value = *newKey
+	case catalog.ActionSetNull:
+		value = types.NewNullValue()
+	case catalog.ActionSetDefault:
+		value, err = e.columnDefault(column)
+		if err != nil {
+			return err
+		}
+	}
+	if err := column.Validate(value); err != nil {
+		return err
+	}
+
+	changes := make([]*rowUpdate, 0, len(rows))
+	for _, row := range rows {
+		before := child.current(row)
+		newRow := copyRow(before)
+		newRow.TxID = e.getCurrentTxID()
+		newRow.Values[colIndex] = value
+		if err := e.computeGeneratedColumns(child.schema, newRow); err != nil {
+			return err
+		}
+		if child.update(row, newRow) {
+			changes = append(changes, &rowUpdate{oldRow: before, newRow: newRow})
+		}
+	}
+
+	// 子表的列也可能被其他外键引用
+	return e.cascadeUpdates(ws, child, changes, depth+1)
+}
+
+// referencingRows 返回子表中（按本条语句修改后的值）引用 key 的行，返回的是表中已有的行
+func (e *Executor) referencingRows(ws *writeSet, fk *catalog.ForeignKey, key types.Value) ([]*storage.Row, error) {
+	schema, err := e.catalog.GetTable(fk.Table)
+	if err != nil {
+		return nil, err
+	}
+	parentSchema, err := e.catalog.GetTable(fk.RefTable)
+	if err != nil {
+		return nil, err
+	}
+	colIndex := schema.GetColumnIndex(fk.Column)
+	collation := parentSchema.Columns[parentSchema.GetColumnIndex(fk.RefColumn)].Collation
+
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return nil, err
+	}
+	tw := ws.find(fk.Table)
+
+	// 子表的引用列有索引时使用索引查找，否则全表扫描
+	var candidates []*storage.Row
+	if idx := e.findIndex(fk.Table, fk.Column, collation); idx != nil {
+		rowIDs, err := idx.Search(key)
+		if err != nil {
+			return nil, err
+		}
+		if candidates, err = e.getRowsByIDs(tableStorage, rowIDs); err != nil {
+			return nil, err
+		}
+		if err := e.fillVirtualColumns(schema, candidates); err != nil {
+			return nil, err
+		}
+	} else {
+		if candidates, err = e.readAllRows(schema, tableStorage); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*storage.Row, 0)
+	seen := make(map[storage.RowID]bool)
+	for _, row := range candidates {
+		current := row
+		if tw != nil {
+			current = tw.current(row)
+		}
+		if current != nil && keysEqual(current.Values[colIndex], key, collation) {
+			result = append(result, row)
+			seen[row.ID] = true
+		}
+	}
+
+	// 本条语句中被更新为 key 的行不一定出现在索引中
+	if tw != nil {
+		for _, u := range tw.updates {
+			if !seen[u.oldRow.ID] && keysEqual(u.newRow.Values[colIndex], key, collation) {
+				result = append(result, u.oldRow)
+				seen[u.oldRow.ID] = true
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// parentKeyExists 判断父表中（按本条语句修改后的值）是否存在 key，ws 可以为 nil
+func (e *Executor) parentKeyExists(ws *writeSet, fk *catalog.ForeignKey, key types.Value) (bool, error) {
+	schema, err := e.catalog.GetTable(fk.RefTable)
+	if err != nil {
+		return false, err
+	}
+	refIndex := schema.GetColumnIndex(fk.RefColumn)
+	collation := schema.Columns[refIndex].Collation
+
+	var tw *tableWrites
+	if ws != nil {
+		tw = ws.find(fk.RefTable)
+	}
+
+	// 本条语句写入的行
+	if tw != nil {
+		for _, row := range tw.newRows() {
+			if keysEqual(row.Values[refIndex], key, collation) {
+				return true, nil
+			}
+		}
+	}
+
+	// 表中已有的行（通过被引用列的唯一索引查找）
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return false, err
+	}
+	for _, idx := range e.indexManager.GetIndexesByTable(fk.RefTable) {
+		if !idx.Unique || idx.ColumnName != fk.RefColumn {
+			continue
+		}
+		rowIDs, err := idx.Search(key)
+		if err != nil {
+			return false, err
+		}
+		for _, rowID := range rowIDs {
+			if tw != nil {
+				if _, removed := tw.removed[rowID]; removed {
+					continue
+				}
+			}
+			row, err := e.getRowByID(tableStorage, rowID)
+			if err != nil {
+				return false, err
+			}
+			if !row.Deleted {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return false, fmt.Errorf("referenced column %s.%s has no unique index", fk.RefTable, fk.RefColumn)
+}
+
+// checkForeignKeys 检查本条语句的修改是否满足外键约束
+// 当前事务中延迟检查的外键记录下来，在提交时检查
+func (e *Executor) checkForeignKeys(ws *writeSet) error {
+	txID := transaction.TransactionID(e.getCurrentTxID())
+	lockManager := e.txManager.GetLockManager()
+
+	for _, tw := range ws.tables {
+		// 子表：写入的值必须在父表中存在
+		for _, fk := range tw.schema.ForeignKeys {
+			colIndex := tw.schema.GetColumnIndex(fk.Column)
+			if err := lockManager.AcquireReadLock(fk.RefTable, txID); err != nil {
+				return fmt.Errorf("failed to acquire read lock: %w", err)
+			}
+
+			rows := append([]*storage.Row{}, tw.inserts...)
+			for _, u := range tw.updates {
+				// 引用列没有变化的行不需要检查
+				if !keysEqual(u.oldRow.Values[colIndex], u.newRow.Values[colIndex], "") {
+					rows = append(rows, u.newRow)
+				}
+			}
+
+			for _, row := range rows {
+				key := row.Values[colIndex]
+				if key.IsNull() {
+					continue
+				}
+				exists, err := e.parentKeyExists(ws, fk, key)
+				if err != nil {
+					return err
+				}
+				if !exists {
+					if e.deferForeignKey(fk) {
+						break
+					}
+					return fmt.Errorf("constraint violation: insert or update on table %s violates foreign key %s: %s = %s is not present in table %s",
+						fk.Table, fk.Name, fk.Column, e.formatValue(tw.schema.Columns[colIndex], key), fk.RefTable)
+				}
+			}
+		}
+
+		// 父表：被删除或被修改的键不能仍被引用
+		if len(tw.deletes) == 0 && len(tw.updates) == 0 {
+			continue
+		}
+
+		for _, fk := range e.catalog.GetForeignKeysReferencing(tw.schema.Name) {
+			if err := lockManager.AcquireReadLock(fk.Table, txID); err != nil {
+				return fmt.Errorf("failed to acquire read lock: %w", err)
+			}
+
+			// RESTRICT 不能延迟
+			for _, row := range tw.deletes {
+				if err := e.checkRemovedKey(ws, tw, fk, row, fk.OnDelete == catalog.ActionRestrict); err != nil {
+					return err
+				}
+			}
+			for _, u := range tw.updates {
+				if err := e.checkRemovedKey(ws, tw, fk, u.oldRow, fk.OnUpdate == catalog.ActionRestrict); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkRemovedKey 检查父表中被删除或被修改的行的键是否仍被子表引用
+func (e *Executor) checkRemovedKey(ws *writeSet, parent *tableWrites, fk *catalog.ForeignKey, row *storage.Row, restrict bool) error {
+	refIndex := parent.schema.GetColumnIndex(fk.RefColumn)
+	key := row.Values[refIndex]
+	if key.IsNull() {
+		return nil
+	}
+
+	// 键仍然存在（例如更新时没有修改被引用列）
+	exists, err := e.parentKeyExists(ws, fk, key)
+	if err != nil || exists {
+		return err
+	}
+
+	children, err := e.referencingRows(ws, fk, key)
+	if err != nil || len(children) == 0 {
+		return err
+	}
+	if !restrict && e.deferForeignKey(fk) {
+		return nil
+	}
+	return fmt.Errorf("constraint violation: update or delete on table %s violates foreign key %s: %s = %s is still referenced from table %s",
+		parent.schema.Name, fk.Name, fk.RefColumn, e.formatValue(parent.schema.Columns[refIndex], key), fk.Table)
+}
+
+// keysEqual 判断两个键是否相等（NULL 与任何值都不相等）
+func keysEqual(a, b types.Value, collation types.Collation) bool {
+	if a.IsNull() || b.IsNull() || a.Type != b.Type {
+		return false
+	}
+	return types.Compare(a, b, collation) == 0
+}
+
+// deferForeignKey 如果外键在当前事务中延迟检查，记录下来在提交时检查并返回 true
+func (e *Executor) deferForeignKey(fk *catalog.ForeignKey) bool {
+	if !e.isConstraintDeferred(fk) {
+		return false
+	}
+	if e.pendingForeignKeys == nil {
+		e.pendingForeignKeys = make(map[string]bool)
+	}
+	e.pendingForeignKeys[fk.Name] = true
+	return true
+}
+
+// isConstraintDeferred 判断外键在当前事务中是否延迟到提交时检查
+func (e *Executor) isConstraintDeferred(fk *catalog.ForeignKey) bool {
+	if e.currentTx == nil || !fk.Deferrable {
+		return false
+	}
+	if deferred, ok := e.constraintModes[fk.Name]; ok {
+		return deferred
+	}
+	if deferred, ok := e.constraintModes[""]; ok {
+		return deferred
+	}
+	return fk.InitiallyDeferred
+}
+
+// verifyForeignKey 检查子表中所有行是否满足外键约束（延迟检查时使用）
+func (e *Executor) verifyForeignKey(fk *catalog.ForeignKey) error {
+	schema, err := e.catalog.GetTable(fk.Table)
+	if err != nil {
+		return err
+	}
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return err
+	}
+	rows, err := tableStorage.GetAllRows()
+	if err != nil {
+		return err
+	}
+
+	colIndex := schema.GetColumnIndex(fk.Column)
+	for _, row := range rows {
+		key := row.Values[colIndex]
+		if key.IsNull() {
+			continue
+		}
+		exists, err := e.parentKeyExists(nil, fk, key)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("constraint violation: table %s violates foreign key %s: %s = %s is not present in table %s",
+				fk.Table, fk.Name, fk.Column, e.formatValue(schema.Columns[colIndex], key), fk.RefTable)
+		}
+	}
+	return nil
+}
+
+// checkDeferredForeignKeys 检查当前事务中延迟检查的外键
+// names 为空时检查所有延迟的外键，检查通过的外键不再延迟
+func (e *Executor) checkDeferredForeignKeys(names map[string]bool) error {
+	for name := range e.pendingForeignKeys {
+		if len(names) > 0 && !names[name] {
+			continue
+		}
+		fk, err := e.catalog.GetForeignKey(name)
+		if err == nil {
+			if err := e.verifyForeignKey(fk); err != nil {
+				return err
+			}
+		}
+		delete(e.pendingForeignKeys, name)
+	}
+	return nil
+}
+
+// resetConstraintModes 事务结束时清除延迟检查的状态
+func (e *Executor) resetConstraintModes() {
+	e.constraintModes = nil
+	e.pendingForeignKeys = nil
+}
+
+// executeSetConstraints 执行 SET CONSTRAINTS
+// 语法: SET CONSTRAINTS { ALL | name [, ...] } { DEFERRED | IMMEDIATE }
+func (e *Executor) executeSetConstraints(sql string) (string, error) {
+	pattern := `(?i)^\s*SET\s+CONSTRAINTS\s+(ALL|[\w.]+(?:\s*,\s*[\w.]+)*)\s+(DEFERRED|IMMEDIATE)\s*;?\s*$`
+	re := regexp.MustCompile(pattern)
+	matches := re.FindStringSubmatch(sql)
+
+	if len(matches) != 3 {
+		return "", fmt.Errorf("invalid SET CONSTRAINTS syntax, expected: SET CONSTRAINTS { ALL | name [, ...] } { DEFERRED | IMMEDIATE }")
+	}
+	if e.currentTx == nil {
+		return "", fmt.Errorf("SET CONSTRAINTS can only be used in transaction blocks")
+	}
+
+	deferred := strings.EqualFold(matches[2], "DEFERRED")
+
+	// 约束名
+	names := make(map[string]bool)
+	if !strings.EqualFold(matches[1], "ALL") {
+		for _, name := range strings.Split(matches[1], ",") {
+			// 外键名按 search_path 解析
+			name, err := e.resolveName(strings.TrimSpace(name), func(qualified string) bool {
+				_, err := e.catalog.GetForeignKey(qualified)
+				return err == nil
+			})
+			if err != nil {
+				return "", err
+			}
+			fk, err := e.catalog.GetForeignKey(name)
+			if err != nil {
+				return "", err
+			}
+			if !fk.Deferrable {
+				return "", fmt.Errorf("constraint %s is not deferrable", name)
+			}
+			names[name] = true
+		}
+	}
+
+	// 改为立即检查时，先检查之前延迟的修改
+	if !deferred {
+		if err := e.checkDeferredForeignKeys(names); err != nil {
+			return "", err
+		}
+	}
+
+	if len(names) == 0 {
+		e.constraintModes = map[string]bool{"": deferred}
+	} else {
+		if e.constraintModes == nil {
+			e.constraintModes = make(map[string]bool)
+		}
+		for name := range names {
+			e.constraintModes[name] = deferred
+		}
+	}
+
+	return "SET CONSTRAINTS", nil
+}
+
+// isSetConstraints 检查是否是 SET CONSTRAINTS 语句
+func isSetConstraints(sql string) bool {
+	fields := strings.Fields(strings.ToUpper(sql))
+	return len(fields) >= 2 && fields[0] == "SET" && fields[1] == "CONSTRAINTS"
+}
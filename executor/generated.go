@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/types"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// validateGeneratedColumns 检查表中生成列的表达式
+// 表达式只能引用本表中不是生成列的列，不能调用序列函数；在全部为 NULL 的行上试算一次，检查表达式是否受支持
+func (e *Executor) validateGeneratedColumns(schema *catalog.TableSchema) error {
+	nullRow := &storage.Row{Values: make([]types.Value, len(schema.Columns))}
+	for i := range nullRow.Values {
+		nullRow.Values[i] = types.NewNullValue()
+	}
+
+	for _, column := range schema.Columns {
+		if column.Generated == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(column.Generated)
+		if err != nil {
+			return err
+		}
+		if hasSequenceCall(expr) {
+			return fmt.Errorf("generation expression of column %s cannot call sequence functions", column.Name)
+		}
+		for _, name := range referencedColumns(expr) {
+			colIndex := schema.GetColumnIndex(name)
+			if colIndex == -1 {
+				return fmt.Errorf("column not found: %s", name)
+			}
+			if schema.Columns[colIndex].Generated != "" {
+				return fmt.Errorf("generation expression of column %s cannot reference generated column %s", column.Name, name)
+			}
+		}
+		if _, err := e.evalRowExpr(nullRow, expr, schema); err != nil {
+			return fmt.Errorf("invalid generation expression for column %s: %w", column.Name, err)
+		}
+	}
+	return nil
+}
+
+// computeGeneratedColumns 计算行中所有生成列的值（INSERT 和 UPDATE 写入前调用），并检查列约束
+func (e *Executor) computeGeneratedColumns(schema *catalog.TableSchema, row *storage.Row) error {
+	for i, column := range schema.Columns {
+		if column.Generated == "" {
+			continue
+		}
+		value, err := e.evalGenerated(schema, column, row)
+		if err != nil {
+			return err
+		}
+		if err := column.Validate(value); err != nil {
+			return err
+		}
+		row.Values[i] = value
+	}
+	return nil
+}
+
+// fillVirtualColumns 计算读取的行中 VIRTUAL 生成列的值（行中不保存这些值）
+func (e *Executor) fillVirtualColumns(schema *catalog.TableSchema, rows []*storage.Row) error {
+	if !hasVirtualColumns(schema) {
+		return nil
+	}
+	for _, row := range rows {
+		for i, column := range schema.Columns {
+			if !column.IsVirtual() || i >= len(row.Values) {
+				continue
+			}
+			value, err := e.evalGenerated(schema, column, row)
+			if err != nil {
+				return err
+			}
+			row.Values[i] = value
+		}
+	}
+	return nil
+}
+
+// FillVirtualColumns 计算行中 VIRTUAL 生成列的值（启动时重建索引使用）
+func (e *Executor) FillVirtualColumns(schema *catalog.TableSchema, rows []*storage.Row) error {
+	return e.fillVirtualColumns(schema, rows)
+}
+
+// readAllRows 读取表的所有未删除行，并计算 VIRTUAL 生成列的值
+func (e *Executor) readAllRows(schema *catalog.TableSchema, tableStorage *storage.TableStorage) ([]*storage.Row, error) {
+	rows, err := tableStorage.GetAllRows()
+	if err != nil {
+		return nil, err
+	}
+	if err := e.fillVirtualColumns(schema, rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// evalGenerated 在行上计算生成列的表达式，并转换为列的类型
+func (e *Executor) evalGenerated(schema *catalog.TableSchema, column catalog.Column, row *storage.Row) (types.Value, error) {
+	expr, err := parser.ParseExpr(column.Generated)
+	if err != nil {
+		return types.Value{}, err
+	}
+	value, err := e.evalRowExpr(row, expr, schema)
+	if err != nil {
+		return types.Value{}, fmt.Errorf("failed to compute generated column %s: %w", column.Name, err)
+	}
+	value, err = e.coerceToColumn(value, column)
+	if err != nil {
+		return types.Value{}, fmt.Errorf("failed to compute generated column %s: %w", column.Name, err)
+	}
+	return value, nil
+}
+
+// storedRow 返回写入存储的行：VIRTUAL 生成列的值不保存，写为 NULL
+// 表中没有 VIRTUAL 生成列时返回原行
+func storedRow(schema *catalog.TableSchema, row *storage.Row) *storage.Row {
+	if !hasVirtualColumns(schema) {
+		return row
+	}
+	stored := copyRow(row)
+	for i, column := range schema.Columns {
+		if column.IsVirtual() {
+			stored.Values[i] = types.NewNullValue()
+		}
+	}
+	return stored
+}
+
+// hasVirtualColumns 判断表中是否有 VIRTUAL 生成列
+func hasVirtualColumns(schema *catalog.TableSchema) bool {
+	for _, column := range schema.Columns {
+		if column.IsVirtual() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGeneratedAssignment 检查赋值的目标列：生成列只能赋值为 DEFAULT
+func checkGeneratedAssignment(column catalog.Column, expr sqlparser.Expr) error {
+	if column.Generated == "" {
+		return nil
+	}
+	if _, ok := expr.(*sqlparser.Default); ok {
+		return nil
+	}
+	return fmt.Errorf("cannot assign to generated column %s", column.Name)
+}
+
+// generatedReferencesColumn 判断生成列的表达式是否引用了列
+func generatedReferencesColumn(column catalog.Column, columnName string) (bool, error) {
+	expr, err := parser.ParseExpr(column.Generated)
+	if err != nil {
+		return false, err
+	}
+	for _, name := range referencedColumns(expr) {
+		if name == columnName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// referencedColumns 返回表达式中引用的列名
+func referencedColumns(expr sqlparser.Expr) []string {
+	names := make([]string, 0)
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if colName, ok := node.(*sqlparser.ColName); ok {
+			names = append(names, colName.Name.String())
+		}
+		return true, nil
+	}, expr)
+	return names
+}
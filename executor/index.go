@@ -3,7 +3,9 @@ package executor
 import (
 	"fmt"
 	"godb/catalog"
+	"godb/index"
 	"godb/storage"
+	"godb/types"
 	"regexp"
 	"strings"
 )
@@ -14,78 +16,133 @@ func CreateTableStorage(pager *storage.Pager, schema *catalog.TableSchema) (*sto
 }
 
 // executeCreateIndex 执行 CREATE INDEX
-// 语法: CREATE INDEX index_name ON table_name (column_name)
+// 语法: CREATE [UNIQUE] INDEX index_name ON table_name (column_name [COLLATE collation])
 func (e *Executor) executeCreateIndex(sql string) (string, error) {
 	// 使用正则表达式解析 CREATE INDEX 语句
-	// CREATE INDEX index_name ON table_name (column_name)
-	pattern := `(?i)CREATE\s+INDEX\s+(\w+)\s+ON\s+(\w+)\s*\(\s*(\w+)\s*\)`
+	// CREATE [UNIQUE] INDEX index_name ON table_name (column_name [COLLATE collation])
+	pattern := `(?i)CREATE\s+(UNIQUE\s+)?INDEX\s+(\w+)\s+ON\s+(\w+(?:\.\w+)?)\s*\(\s*(\w+)(?:\s+COLLATE\s+(\w+))?\s*\)`
 	re := regexp.MustCompile(pattern)
 	matches := re.FindStringSubmatch(sql)
 
-	if len(matches) != 4 {
-		return "", fmt.Errorf("invalid CREATE INDEX syntax, expected: CREATE INDEX index_name ON table_name (column_name)")
+	if len(matches) != 6 {
+		return "", fmt.Errorf("invalid CREATE INDEX syntax, expected: CREATE [UNIQUE] INDEX index_name ON table_name (column_name)")
 	}
 
-	indexName := matches[1]
-	tableName := matches[2]
-	columnName := matches[3]
+	unique := matches[1] != ""
+	tableName, err := e.resolveRelation(matches[3])
+	if err != nil {
+		return "", err
+	}
+	// 索引创建在表所在的模式中
+	indexName := schemaQualified(tableName, matches[2])
+	columnName := matches[4]
+
+	// 索引可以使用与列不同的排序规则，只有排序规则一致的比较才能使用该索引
+	var collation types.Collation
+	if matches[5] != "" {
+		if collation, err = types.ParseCollation(matches[5]); err != nil {
+			return "", err
+		}
+	}
 
-	// 在 catalog 中创建索引元数据
-	if err := e.catalog.CreateIndex(indexName, tableName, columnName); err != nil {
+	if err := e.lockDDL(tableName); err != nil {
 		return "", err
 	}
+	entries, err := e.createIndex(indexName, tableName, columnName, collation, unique)
+	if err != nil {
+		return "", err
+	}
+	e.logCreateIndex(tableName, indexName)
+
+	kind := "Index"
+	if unique {
+		kind = "Unique index"
+	}
+	return fmt.Sprintf("%s '%s' created successfully on %s(%s) with %d entries",
+		kind, indexName, tableName, columnName, entries), nil
+}
+
+// createIndex 创建索引并用表中现有数据构建 B+ 树，返回索引条目数
+func (e *Executor) createIndex(indexName, tableName, columnName string, collation types.Collation, unique bool) (int, error) {
+	// 在 catalog 中创建索引元数据
+	if err := e.catalog.CreateIndex(indexName, tableName, columnName, collation, unique); err != nil {
+		return 0, err
+	}
+
+	indexInfo, err := e.catalog.GetIndex(indexName)
+	if err != nil {
+		return 0, err
+	}
 
 	// 获取表定义
 	schema, err := e.catalog.GetTable(tableName)
 	if err != nil {
-		return "", err
+		return 0, err
 	}
 
 	// 获取列索引
 	colIndex := schema.GetColumnIndex(columnName)
 	if colIndex == -1 {
-		return "", fmt.Errorf("column not found: %s", columnName)
+		return 0, fmt.Errorf("column not found: %s", columnName)
 	}
 
-	// 在索引管理器中创建索引
+	// 在索引管理器中创建索引（B+ 树与表的行在同一个页管理器中）
 	columnType := schema.Columns[colIndex].Type
-	if err := e.indexManager.CreateIndex(indexName, tableName, columnName, columnType); err != nil {
-		return "", err
-	}
-
-	// 构建索引：读取表中所有现有数据并插入索引
-	tableStorage, err := CreateTableStorage(e.pager, schema)
+	idx, err := e.indexManager.CreateIndex(e.pagerFor(schema), indexName, tableName, columnName, columnType, indexInfo.Collation, unique)
 	if err != nil {
-		return "", err
+		e.catalog.DropIndex(indexName)
+		return 0, err
 	}
 
-	rows, err := tableStorage.GetAllRows()
+	// 构建索引：读取表中所有现有数据并插入索引
+	tableStorage, err := CreateTableStorage(e.pagerFor(schema), schema)
 	if err != nil {
-		return "", err
+		e.dropIndexOnError(idx)
+		return 0, err
 	}
 
-	// 获取索引
-	idx, err := e.indexManager.GetIndex(indexName)
+	rows, err := e.readAllRows(schema, tableStorage)
 	if err != nil {
-		return "", err
+		e.dropIndexOnError(idx)
+		return 0, err
 	}
 
-	// 为每一行插入索引条目
+	// 为每一行插入索引条目（唯一索引会检查现有数据中的重复键）
 	for _, row := range rows {
 		if err := idx.Insert(row.Values[colIndex], row.ID); err != nil {
-			return "", fmt.Errorf("failed to build index: %w", err)
+			e.dropIndexOnError(idx)
+			return 0, fmt.Errorf("failed to build index %s: %w", indexName, err)
 		}
 	}
 
-	return fmt.Sprintf("Index '%s' created successfully on %s(%s) with %d entries",
-		indexName, tableName, columnName, len(rows)), nil
+	// 构建完成后才记录根节点页 ID，之前中断时启动时重新构建
+	if err := e.catalog.SetIndexRootPage(indexName, idx.RootPageID()); err != nil {
+		e.dropIndexOnError(idx)
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+// dropIndexOnError 创建索引出错时删除索引的元数据并释放 B+ 树的页
+func (e *Executor) dropIndexOnError(idx *index.Index) {
+	e.indexManager.DropIndex(idx.Name)
+	e.catalog.DropIndex(idx.Name)
+	idx.Free()
+}
+
+// freeIndexes 释放新建但不再使用的索引的页
+func freeIndexes(indexes []*index.Index) {
+	for _, idx := range indexes {
+		idx.Free()
+	}
 }
 
 // executeDropIndex 执行 DROP INDEX
 // 语法: DROP INDEX index_name
 func (e *Executor) executeDropIndex(sql string) (string, error) {
 	// 解析 DROP INDEX 语句
-	pattern := `(?i)DROP\s+INDEX\s+(\w+)`
+	pattern := `(?i)DROP\s+INDEX\s+(\w+(?:\.\w+)?)`
 	re := regexp.MustCompile(pattern)
 	matches := re.FindStringSubmatch(sql)
 
@@ -93,25 +150,41 @@ func (e *Executor) executeDropIndex(sql string) (string, error) {
 		return "", fmt.Errorf("invalid DROP INDEX syntax, expected: DROP INDEX index_name")
 	}
 
-	indexName := matches[1]
+	indexName, err := e.resolveIndexName(matches[1])
+	if err != nil {
+		return "", err
+	}
 
-	// 从索引管理器中删除
-	if err := e.indexManager.DropIndex(indexName); err != nil {
+	info, err := e.catalog.GetIndex(indexName)
+	if err != nil {
+		return "", err
+	}
+	if err := e.lockDDL(info.TableName); err != nil {
+		return "", err
+	}
+	idx, err := e.indexManager.GetIndex(indexName)
+	if err != nil {
 		return "", err
 	}
 
-	// 从 catalog 中删除
+	// 从 catalog 中删除（主键索引不能单独删除）
 	if err := e.catalog.DropIndex(indexName); err != nil {
 		return "", err
 	}
 
+	// 从索引管理器中删除
+	if err := e.indexManager.DropIndex(indexName); err != nil {
+		return "", err
+	}
+	e.logDropIndex(info, idx)
+
 	return fmt.Sprintf("Index '%s' dropped successfully", indexName), nil
 }
 
 // isCreateIndex 检查是否是 CREATE INDEX 语句
 func isCreateIndex(sql string) bool {
 	sql = strings.TrimSpace(strings.ToUpper(sql))
-	return strings.HasPrefix(sql, "CREATE INDEX")
+	return strings.HasPrefix(sql, "CREATE INDEX") || strings.HasPrefix(sql, "CREATE UNIQUE INDEX")
 }
 
 // isDropIndex 检查是否是 DROP INDEX 语句
@@ -6,9 +6,6 @@ import (
 	"godb/storage"
 	"godb/transaction"
 	"godb/types"
-	"strconv"
-	"strings"
-	"time"
 
 	"github.com/xwb1989/sqlparser"
 )
@@ -16,10 +13,13 @@ import (
 // executeInsert 执行 INSERT 语句
 func (e *Executor) executeInsert(stmt *sqlparser.Insert) (string, error) {
 	// 获取表名
-	tableName := stmt.Table.Name.String()
+	tableName, err := e.resolveTableName(stmt.Table)
+	if err != nil {
+		return "", err
+	}
 
 	// 获取表定义
-	schema, err := e.catalog.GetTable(tableName)
+	schema, err := e.getWritableTable(tableName)
 	if err != nil {
 		return "", err
 	}
@@ -31,23 +31,57 @@ func (e *Executor) executeInsert(stmt *sqlparser.Insert) (string, error) {
 		return "", fmt.Errorf("failed to acquire write lock: %w", err)
 	}
 
-	// 创建表存储
-	tableStorage, err := catalog.CreateTableStorage(e.pager, schema)
-	if err != nil {
-		return "", err
+	// 如果是自动提交模式，在操作完成后释放锁（出错时也要释放）
+	if e.currentTx == nil {
+		defer lockManager.ReleaseLocks(transaction.TransactionID(txID))
 	}
 
-	// 解析插入的值
-	rows, ok := stmt.Rows.(sqlparser.Values)
-	if !ok {
+	// 目标列：没有列出的列使用默认值
+	targetColumns := make([]int, 0, len(schema.Columns))
+	if len(stmt.Columns) == 0 {
+		for i := range schema.Columns {
+			targetColumns = append(targetColumns, i)
+		}
+	} else {
+		listed := make(map[int]bool, len(stmt.Columns))
+		for _, colIdent := range stmt.Columns {
+			colIndex := schema.GetColumnIndex(colIdent.String())
+			if colIndex == -1 {
+				return "", fmt.Errorf("column not found: %s", colIdent.String())
+			}
+			if listed[colIndex] {
+				return "", fmt.Errorf("column %s specified more than once", colIdent.String())
+			}
+			listed[colIndex] = true
+			targetColumns = append(targetColumns, colIndex)
+		}
+	}
+
+	// 插入的值：VALUES 列表中的表达式，或者 INSERT ... SELECT 的查询结果
+	var values sqlparser.Values
+	var selected *catalog.TableSchema
+	var selectedRows []*storage.Row
+	switch source := stmt.Rows.(type) {
+	case sqlparser.Values:
+		values = source
+	case *sqlparser.Select:
+		if selected, selectedRows, err = e.queryRelation(source); err != nil {
+			return "", err
+		}
+		if len(selected.Columns) != len(targetColumns) {
+			return "", fmt.Errorf("column count mismatch: expected %d, got %d", len(targetColumns), len(selected.Columns))
+		}
+	default:
 		return "", fmt.Errorf("unsupported insert syntax")
 	}
 
-	insertCount := 0
-	for _, valTuple := range rows {
+	// 先计算所有行的值并检查约束，避免部分写入
+	numRows := len(values) + len(selectedRows)
+	newRows := make([]*storage.Row, 0, numRows)
+	for n := 0; n < numRows; n++ {
 		// 检查值的数量
-		if len(valTuple) != len(schema.Columns) {
-			return "", fmt.Errorf("column count mismatch: expected %d, got %d", len(schema.Columns), len(valTuple))
+		if values != nil && len(values[n]) != len(targetColumns) {
+			return "", fmt.Errorf("column count mismatch: expected %d, got %d", len(targetColumns), len(values[n]))
 		}
 
 		// 构造行
@@ -56,116 +90,80 @@ func (e *Executor) executeInsert(stmt *sqlparser.Insert) (string, error) {
 			Values: make([]types.Value, len(schema.Columns)),
 		}
 
-		for i, expr := range valTuple {
-			value, err := e.evalExpr(expr, schema.Columns[i].Type)
+		assigned := make([]bool, len(schema.Columns))
+		for i, colIndex := range targetColumns {
+			var value types.Value
+			if values != nil {
+				if err := checkGeneratedAssignment(schema.Columns[colIndex], values[n][i]); err != nil {
+					return "", err
+				}
+				value, err = e.evalAssignExpr(values[n][i], schema.Columns[colIndex])
+			} else if schema.Columns[colIndex].Generated != "" {
+				return "", fmt.Errorf("cannot assign to generated column %s", schema.Columns[colIndex].Name)
+			} else {
+				value, err = e.coerceSelected(selected.Columns[i], selectedRows[n].Values[i], schema.Columns[colIndex])
+			}
 			if err != nil {
-				return "", fmt.Errorf("failed to evaluate value for column %s: %w", schema.Columns[i].Name, err)
+				return "", fmt.Errorf("failed to evaluate value for column %s: %w", schema.Columns[colIndex].Name, err)
 			}
-			row.Values[i] = value
+			row.Values[colIndex] = value
+			assigned[colIndex] = true
 		}
 
-		// 插入行
-		if err := tableStorage.InsertRow(row); err != nil {
-			return "", fmt.Errorf("failed to insert row: %w", err)
-		}
-
-		// 更新所有相关索引
-		columnNames := make([]string, len(schema.Columns))
-		for i, col := range schema.Columns {
-			columnNames[i] = col.Name
-		}
-		if err := e.indexManager.InsertEntry(tableName, row, columnNames); err != nil {
-			return "", fmt.Errorf("failed to update index: %w", err)
+		for i, column := range schema.Columns {
+			// 与 MySQL 一样，自增列显式插入 NULL 时和省略该列一样生成下一个值
+			if !assigned[i] || row.Values[i].IsNull() && e.catalog.IsAutoIncrement(schema, column) {
+				value, err := e.columnDefault(column)
+				if err != nil {
+					return "", err
+				}
+				row.Values[i] = value
+			}
+			if column.Generated == "" {
+				if err := column.Validate(row.Values[i]); err != nil {
+					return "", err
+				}
+			}
 		}
 
-		// 记录操作到事务日志（用于回滚）
-		if e.currentTx != nil {
-			op := &transaction.Operation{
-				Type:      transaction.OpInsert,
-				TableName: tableName,
-				RowID:     row.ID,
-				NewData:   row,
-			}
-			e.currentTx.AddOperation(op)
+		// 生成列在其他列的值确定之后计算
+		if err := e.computeGeneratedColumns(schema, row); err != nil {
+			return "", err
 		}
 
-		insertCount++
+		newRows = append(newRows, row)
 	}
 
-	// 如果是自动提交模式，立即释放锁和刷新
-	if e.currentTx == nil {
-		lockManager.ReleaseLocks(transaction.TransactionID(txID))
-		if err := e.pager.FlushAll(); err != nil {
-			return "", fmt.Errorf("failed to flush pages: %w", err)
-		}
+	if err := e.fireTriggers(schema, "BEFORE", "INSERT", nil, newRows); err != nil {
+		return "", err
 	}
 
-	return fmt.Sprintf("%d row(s) inserted", insertCount), nil
-}
-
-// evalExpr 计算表达式值
-func (e *Executor) evalExpr(expr sqlparser.Expr, expectedType types.DataType) (types.Value, error) {
-	switch expr := expr.(type) {
-	case *sqlparser.SQLVal:
-		return e.evalSQLVal(expr, expectedType)
-	default:
-		return types.Value{}, fmt.Errorf("unsupported expression type: %T", expr)
+	// 检查约束后写入
+	ws := &writeSet{}
+	tw, err := e.writesFor(ws, tableName)
+	if err != nil {
+		return "", err
+	}
+	for _, row := range newRows {
+		tw.insert(row)
+	}
+	if err := e.applyWriteSet(ws); err != nil {
+		return "", err
+	}
+	if err := e.advanceAutoIncrement(schema, newRows); err != nil {
+		return "", err
+	}
+	if err := e.fireTriggers(schema, "AFTER", "INSERT", nil, newRows); err != nil {
+		return "", err
 	}
-}
-
-// evalSQLVal 计算 SQL 值
-func (e *Executor) evalSQLVal(val *sqlparser.SQLVal, expectedType types.DataType) (types.Value, error) {
-	switch val.Type {
-	case sqlparser.IntVal:
-		intVal, err := strconv.ParseInt(string(val.Val), 10, 64)
-		if err != nil {
-			return types.Value{}, err
-		}
-		// 支持 INT 到 FLOAT 的自动转换
-		if expectedType == types.TypeFloat {
-			return types.NewFloatValue(float64(intVal)), nil
-		}
-		if expectedType != types.TypeInt {
-			return types.Value{}, fmt.Errorf("type mismatch: expected %s, got INT", expectedType)
-		}
-		return types.NewIntValue(intVal), nil
-
-	case sqlparser.StrVal:
-		strVal := string(val.Val)
-		switch expectedType {
-		case types.TypeText:
-			return types.NewTextValue(strVal), nil
-		case types.TypeDate:
-			// 解析日期（支持 YYYY-MM-DD 格式）
-			date, err := time.Parse("2006-01-02", strVal)
-			if err != nil {
-				return types.Value{}, fmt.Errorf("invalid date format: %s", strVal)
-			}
-			return types.NewDateValue(date), nil
-		case types.TypeBoolean:
-			// 支持 'true'/'false' 字符串
-			lowerStr := strings.ToLower(strVal)
-			if lowerStr == "true" {
-				return types.NewBooleanValue(true), nil
-			} else if lowerStr == "false" {
-				return types.NewBooleanValue(false), nil
-			}
-			return types.Value{}, fmt.Errorf("invalid boolean value: %s", strVal)
-		default:
-			return types.Value{}, fmt.Errorf("type mismatch: expected %s, got TEXT", expectedType)
-		}
 
-	case sqlparser.FloatVal:
-		floatVal, err := strconv.ParseFloat(string(val.Val), 64)
-		if err != nil {
-			return types.Value{}, err
-		}
-		if expectedType != types.TypeFloat {
-			return types.Value{}, fmt.Errorf("type mismatch: expected %s, got FLOAT", expectedType)
-		}
-		return types.NewFloatValue(floatVal), nil
+	return fmt.Sprintf("%d row(s) inserted", len(newRows)), nil
+}
 
-	default:
-		return types.Value{}, fmt.Errorf("unsupported value type: %v", val.Type)
+// coerceSelected 把 INSERT ... SELECT 查询结果中的值转换为目标列的类型（枚举值按标签转换，两列的枚举类型可以不同）
+func (e *Executor) coerceSelected(source catalog.Column, value types.Value, column catalog.Column) (types.Value, error) {
+	if value.Type == types.TypeEnum {
+		value = types.NewTextValue(e.formatValue(source, value))
 	}
+	return e.coerceToColumn(value, column)
 }
@@ -0,0 +1,53 @@
+package executor
+
+import "testing"
+
+// 自增列显式插入 NULL 时和省略该列一样生成下一个值
+func TestInsertNullIntoAutoIncrement(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE items (id INT AUTO_INCREMENT PRIMARY KEY, v TEXT)",
+		"INSERT INTO items (id, v) VALUES (NULL, 'a')",
+		"INSERT INTO items (v) VALUES ('b')",
+		"INSERT INTO items VALUES (NULL, 'c'), (10, 'd'), (NULL, 'e')",
+		"INSERT INTO items (id, v) SELECT NULL, 'f'",
+	)
+	// 显式插入的 10 推进了序列
+	expectRows(t, e, "SELECT id, v FROM items ORDER BY id",
+		"1\ta",
+		"2\tb",
+		"3\tc",
+		"4\te",
+		"10\td",
+		"11\tf",
+	)
+
+	// 其他 NOT NULL 列仍然不能插入 NULL
+	mustExec(t, e, "CREATE TABLE tags (id SERIAL PRIMARY KEY, name TEXT NOT NULL)")
+	mustFail(t, e, "INSERT INTO tags (id, name) VALUES (NULL, NULL)", "null value in column name violates not-null constraint")
+	// 失败的插入也消耗了序列的值
+	mustExec(t, e, "INSERT INTO tags (id, name) VALUES (NULL, 'x')")
+	expectRows(t, e, "SELECT id, name FROM tags", "2\tx")
+}
+
+// 显式插入的值超过自增列的当前值时，之后生成的值从该值之后继续
+func TestInsertExplicitValueAdvancesAutoIncrement(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE ai (id INT AUTO_INCREMENT PRIMARY KEY, v TEXT)",
+		"INSERT INTO ai VALUES (2, 'explicit')",
+		"INSERT INTO ai (v) VALUES ('x')",
+		"INSERT INTO ai (v) VALUES ('y')",
+		"INSERT INTO ai VALUES (100, 'far')",
+		"INSERT INTO ai VALUES (50, 'smaller')",
+		"INSERT INTO ai (v) VALUES ('z')",
+	)
+	expectRows(t, e, "SELECT id, v FROM ai ORDER BY id",
+		"2\texplicit",
+		"3\tx",
+		"4\ty",
+		"50\tsmaller",
+		"100\tfar",
+		"101\tz",
+	)
+}
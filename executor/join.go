@@ -27,41 +27,37 @@ type JoinedRow struct {
 
 // JoinContext JOIN 上下文
 type JoinContext struct {
-	LeftTable  string
-	RightTable string
-	LeftSchema *catalog.TableSchema
+	LeftTable   string // 左表在语句中的写法（带别名时为别名），用作列名的前缀
+	RightTable  string // 右表在语句中的写法（带别名时为别名）
+	LeftName    string // 左表解析后的名字（元数据中的名字，附加数据库的表为 alias.name）
+	RightName   string // 右表解析后的名字
+	LeftSchema  *catalog.TableSchema
 	RightSchema *catalog.TableSchema
-	JoinType   JoinType
-	OnExpr     sqlparser.Expr // JOIN ON 条件
+	LeftRows    []*storage.Row // 左表（或视图）的行
+	RightRows   []*storage.Row // 右表（或视图）的行
+	JoinType    JoinType
+	OnExpr      sqlparser.Expr // JOIN ON 条件
 }
 
 // executeJoin 执行 JOIN 查询
 func (e *Executor) executeJoin(stmt *sqlparser.Select) (string, error) {
-	// 解析 JOIN 信息
-	joinCtx, err := e.parseJoinContext(stmt)
+	joinedRows, selectedColumns, joinCtx, err := e.joinRows(stmt)
 	if err != nil {
 		return "", err
 	}
 
-	// 加载左表数据
-	leftStorage, err := catalog.CreateTableStorage(e.pager, joinCtx.LeftSchema)
-	if err != nil {
-		return "", err
-	}
-	leftRows, err := leftStorage.GetAllRows()
-	if err != nil {
-		return "", err
-	}
+	// 格式化输出
+	return e.formatJoinedResult(joinedRows, selectedColumns, joinCtx), nil
+}
 
-	// 加载右表数据
-	rightStorage, err := catalog.CreateTableStorage(e.pager, joinCtx.RightSchema)
-	if err != nil {
-		return "", err
-	}
-	rightRows, err := rightStorage.GetAllRows()
+// joinRows 执行 JOIN 查询（两侧可以是表或视图），返回排序后的连接行、要显示的列和 JOIN 上下文
+func (e *Executor) joinRows(stmt *sqlparser.Select) ([]*JoinedRow, []columnInfo, *JoinContext, error) {
+	// 解析 JOIN 信息并加载两侧的数据
+	joinCtx, err := e.parseJoinContext(stmt)
 	if err != nil {
-		return "", err
+		return nil, nil, nil, err
 	}
+	leftRows, rightRows := joinCtx.LeftRows, joinCtx.RightRows
 
 	// 执行 JOIN
 	var joinedRows []*JoinedRow
@@ -73,29 +69,33 @@ func (e *Executor) executeJoin(stmt *sqlparser.Select) (string, error) {
 	case RightJoin:
 		joinedRows, err = e.rightJoin(leftRows, rightRows, joinCtx)
 	default:
-		return "", fmt.Errorf("unsupported join type")
+		return nil, nil, nil, fmt.Errorf("unsupported join type")
 	}
 
 	if err != nil {
-		return "", err
+		return nil, nil, nil, err
 	}
 
 	// 应用 WHERE 过滤
 	if stmt.Where != nil {
 		joinedRows, err = e.filterJoinedRows(joinedRows, stmt.Where.Expr, joinCtx)
 		if err != nil {
-			return "", err
+			return nil, nil, nil, err
 		}
 	}
 
 	// 选择要显示的列
 	selectedColumns, err := e.getJoinedSelectedColumns(stmt.SelectExprs, joinCtx)
 	if err != nil {
-		return "", err
+		return nil, nil, nil, err
 	}
 
-	// 格式化输出
-	return e.formatJoinedResult(joinedRows, selectedColumns, joinCtx), nil
+	// 排序
+	if err := e.sortJoinedRows(joinedRows, stmt.OrderBy, joinCtx); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return joinedRows, selectedColumns, joinCtx, nil
 }
 
 // parseJoinContext 解析 JOIN 上下文
@@ -115,22 +115,34 @@ func (e *Executor) parseJoinContext(stmt *sqlparser.Select) (*JoinContext, error
 	if !ok {
 		return nil, fmt.Errorf("invalid left table expression")
 	}
-	leftTableName := leftTableExpr.Expr.(sqlparser.TableName).Name.String()
+	leftTable := leftTableExpr.Expr.(sqlparser.TableName)
 
 	// 获取右表名
 	rightTableExpr, ok := joinExpr.RightExpr.(*sqlparser.AliasedTableExpr)
 	if !ok {
 		return nil, fmt.Errorf("invalid right table expression")
 	}
-	rightTableName := rightTableExpr.Expr.(sqlparser.TableName).Name.String()
+	rightTable := rightTableExpr.Expr.(sqlparser.TableName)
+
+	// 列名按语句中的写法（或别名）限定，表按 search_path 解析
+	leftTableName := joinTableLabel(leftTableExpr)
+	rightTableName := joinTableLabel(rightTableExpr)
 
-	// 获取表定义
-	leftSchema, err := e.catalog.GetTable(leftTableName)
+	// 获取表定义和数据
+	leftName, err := e.resolveTableName(leftTable)
+	if err != nil {
+		return nil, err
+	}
+	leftSchema, leftRows, err := e.loadRelation(leftName)
 	if err != nil {
 		return nil, fmt.Errorf("left table not found: %w", err)
 	}
 
-	rightSchema, err := e.catalog.GetTable(rightTableName)
+	rightName, err := e.resolveTableName(rightTable)
+	if err != nil {
+		return nil, err
+	}
+	rightSchema, rightRows, err := e.loadRelation(rightName)
 	if err != nil {
 		return nil, fmt.Errorf("right table not found: %w", err)
 	}
@@ -151,13 +163,25 @@ func (e *Executor) parseJoinContext(stmt *sqlparser.Select) (*JoinContext, error
 	return &JoinContext{
 		LeftTable:   leftTableName,
 		RightTable:  rightTableName,
+		LeftName:    leftName,
+		RightName:   rightName,
 		LeftSchema:  leftSchema,
 		RightSchema: rightSchema,
+		LeftRows:    leftRows,
+		RightRows:   rightRows,
 		JoinType:    joinType,
 		OnExpr:      joinExpr.Condition.On,
 	}, nil
 }
 
+// joinTableLabel 返回 JOIN 一侧的表在语句中的写法，带别名时为别名
+func joinTableLabel(tableExpr *sqlparser.AliasedTableExpr) string {
+	if !tableExpr.As.IsEmpty() {
+		return tableExpr.As.String()
+	}
+	return sqlparser.String(tableExpr.Expr)
+}
+
 // innerJoin 执行 INNER JOIN
 func (e *Executor) innerJoin(leftRows, rightRows []*storage.Row, ctx *JoinContext) ([]*JoinedRow, error) {
 	result := make([]*JoinedRow, 0)
@@ -262,8 +286,16 @@ func (e *Executor) evaluateJoinCondition(leftRow, rightRow *storage.Row, ctx *Jo
 		return false, err
 	}
 
+	collation, err := resolveCollation(
+		[]sqlparser.Expr{compExpr.Left, compExpr.Right},
+		[]*catalog.Column{ctx.column(leftCol), ctx.column(rightCol)},
+	)
+	if err != nil {
+		return false, err
+	}
+
 	// 比较值
-	return e.compareJoinValues(leftRow, rightRow, leftCol, rightCol, compExpr.Operator, ctx)
+	return e.compareJoinValues(leftRow, rightRow, leftCol, rightCol, compExpr.Operator, collation)
 }
 
 // parseJoinColumns 解析 JOIN 的列
@@ -288,50 +320,80 @@ type columnInfo struct {
 	isLeft     bool
 }
 
-// parseColumnInfo 解析列信息
+// column 返回列信息对应的列定义
+func (ctx *JoinContext) column(col *columnInfo) *catalog.Column {
+	if col.isLeft {
+		return &ctx.LeftSchema.Columns[col.colIndex]
+	}
+	return &ctx.RightSchema.Columns[col.colIndex]
+}
+
+// parseColumnInfo 解析列信息（列名可以带 COLLATE）
 func (e *Executor) parseColumnInfo(expr sqlparser.Expr, ctx *JoinContext) (*columnInfo, error) {
-	colName, ok := expr.(*sqlparser.ColName)
+	colName, ok := unwrapCollate(expr).(*sqlparser.ColName)
 	if !ok {
 		return nil, fmt.Errorf("expected column name in JOIN condition")
 	}
 
-	tableName := ""
-	if !colName.Qualifier.IsEmpty() {
-		tableName = colName.Qualifier.Name.String()
-	}
-
 	columnName := colName.Name.String()
+	leftIndex := ctx.LeftSchema.GetColumnIndex(columnName)
+	rightIndex := ctx.RightSchema.GetColumnIndex(columnName)
 
-	// 判断是左表还是右表的列
-	if tableName == "" || tableName == ctx.LeftTable {
-		colIndex := ctx.LeftSchema.GetColumnIndex(columnName)
-		if colIndex != -1 {
-			return &columnInfo{
-				tableName:  ctx.LeftTable,
-				columnName: columnName,
-				colIndex:   colIndex,
-				isLeft:     true,
-			}, nil
+	// 按限定名判断是左表还是右表的列，两侧都匹配时取匹配程度高的一侧
+	if !colName.Qualifier.IsEmpty() {
+		leftMatch := e.qualifierMatch(colName.Qualifier, ctx.LeftTable, ctx.LeftName)
+		rightMatch := e.qualifierMatch(colName.Qualifier, ctx.RightTable, ctx.RightName)
+		if leftMatch == 0 || leftMatch < rightMatch {
+			leftIndex = -1
+		}
+		if rightMatch == 0 || rightMatch < leftMatch {
+			rightIndex = -1
 		}
 	}
 
-	if tableName == "" || tableName == ctx.RightTable {
-		colIndex := ctx.RightSchema.GetColumnIndex(columnName)
-		if colIndex != -1 {
-			return &columnInfo{
-				tableName:  ctx.RightTable,
-				columnName: columnName,
-				colIndex:   colIndex,
-				isLeft:     false,
-			}, nil
-		}
+	switch {
+	case leftIndex != -1 && rightIndex != -1:
+		return nil, fmt.Errorf("column reference %s is ambiguous", sqlparser.String(colName))
+	case leftIndex != -1:
+		return &columnInfo{
+			tableName:  ctx.LeftTable,
+			columnName: columnName,
+			colIndex:   leftIndex,
+			isLeft:     true,
+		}, nil
+	case rightIndex != -1:
+		return &columnInfo{
+			tableName:  ctx.RightTable,
+			columnName: columnName,
+			colIndex:   rightIndex,
+			isLeft:     false,
+		}, nil
 	}
 
 	return nil, fmt.Errorf("column not found: %s", columnName)
 }
 
+// qualifierMatch 返回列的限定名与 JOIN 一侧的匹配程度：
+// 2 表示与语句中的写法（或别名）相同，或者带模式的限定名（schema.table 或 alias.table）解析后是同一张表；
+// 1 表示只有表名部分相同（如 orders 与 archive.orders）；0 表示不匹配
+func (e *Executor) qualifierMatch(qualifier sqlparser.TableName, label, resolved string) int {
+	if qualifier.Qualifier.IsEmpty() {
+		switch qualifier.Name.String() {
+		case label:
+			return 2
+		case catalog.BaseName(label):
+			return 1
+		}
+		return 0
+	}
+	if name, err := e.resolveTableName(qualifier); err == nil && name == resolved {
+		return 2
+	}
+	return 0
+}
+
 // compareJoinValues 比较 JOIN 的值
-func (e *Executor) compareJoinValues(leftRow, rightRow *storage.Row, leftCol, rightCol *columnInfo, operator string, ctx *JoinContext) (bool, error) {
+func (e *Executor) compareJoinValues(leftRow, rightRow *storage.Row, leftCol, rightCol *columnInfo, operator string, collation types.Collation) (bool, error) {
 	// 获取左值
 	var leftValue types.Value
 	if leftCol.isLeft {
@@ -349,7 +411,7 @@ func (e *Executor) compareJoinValues(leftRow, rightRow *storage.Row, leftCol, ri
 	}
 
 	// 比较
-	return e.compareValues(leftValue, rightValue, operator)
+	return e.compareValues(leftValue, rightValue, operator, collation)
 }
 
 // filterJoinedRows 过滤连接后的行
@@ -374,6 +436,17 @@ func (e *Executor) evaluateJoinedRowCondition(joinedRow *JoinedRow, expr sqlpars
 	switch expr := expr.(type) {
 	case *sqlparser.ComparisonExpr:
 		return e.evalJoinedComparison(joinedRow, expr, ctx)
+	case *sqlparser.IsExpr:
+		col, err := e.parseColumnInfo(expr.Expr, ctx)
+		if err != nil {
+			return false, err
+		}
+		// 外连接中不存在的一侧视为 NULL
+		value := types.NewNullValue()
+		if source := joinedSource(joinedRow, col); source != nil {
+			value = source.Values[col.colIndex]
+		}
+		return evalIsNull(value, expr.Operator)
 	case *sqlparser.AndExpr:
 		left, err := e.evaluateJoinedRowCondition(joinedRow, expr.Left, ctx)
 		if err != nil {
@@ -399,6 +472,14 @@ func (e *Executor) evaluateJoinedRowCondition(joinedRow *JoinedRow, expr sqlpars
 	}
 }
 
+// joinedSource 返回连接行中列所在一侧的行，外连接中不存在的一侧返回 nil
+func joinedSource(joinedRow *JoinedRow, col *columnInfo) *storage.Row {
+	if col.isLeft {
+		return joinedRow.LeftRow
+	}
+	return joinedRow.RightRow
+}
+
 // evalJoinedComparison 求值连接行的比较
 func (e *Executor) evalJoinedComparison(joinedRow *JoinedRow, expr *sqlparser.ComparisonExpr, ctx *JoinContext) (bool, error) {
 	leftCol, err := e.parseColumnInfo(expr.Left, ctx)
@@ -408,26 +489,34 @@ func (e *Executor) evalJoinedComparison(joinedRow *JoinedRow, expr *sqlparser.Co
 
 	// 获取左值
 	var leftValue types.Value
+	var column catalog.Column
 	if leftCol.isLeft {
 		if joinedRow.LeftRow == nil {
 			return false, nil // NULL 值
 		}
 		leftValue = joinedRow.LeftRow.Values[leftCol.colIndex]
+		column = ctx.LeftSchema.Columns[leftCol.colIndex]
 	} else {
 		if joinedRow.RightRow == nil {
 			return false, nil // NULL 值
 		}
 		leftValue = joinedRow.RightRow.Values[leftCol.colIndex]
+		column = ctx.RightSchema.Columns[leftCol.colIndex]
 	}
 
 	// 获取右值
-	rightValue, err := e.evalExpr(expr.Right, leftValue.Type)
+	rightValue, err := e.evalColumnExpr(expr.Right, column)
+	if err != nil {
+		return false, err
+	}
+
+	collation, err := resolveCollation([]sqlparser.Expr{expr.Left, expr.Right}, []*catalog.Column{&column})
 	if err != nil {
 		return false, err
 	}
 
 	// 比较
-	return e.compareValues(leftValue, rightValue, expr.Operator)
+	return e.compareValues(leftValue, rightValue, expr.Operator, collation)
 }
 
 // getJoinedSelectedColumns 获取连接后要显示的列
@@ -496,13 +585,13 @@ func (e *Executor) formatJoinedResult(rows []*JoinedRow, selectedColumns []colum
 		for i, col := range selectedColumns {
 			if col.isLeft {
 				if row.LeftRow != nil {
-					values[i] = row.LeftRow.Values[col.colIndex].String()
+					values[i] = e.formatValue(ctx.LeftSchema.Columns[col.colIndex], row.LeftRow.Values[col.colIndex])
 				} else {
 					values[i] = "NULL"
 				}
 			} else {
 				if row.RightRow != nil {
-					values[i] = row.RightRow.Values[col.colIndex].String()
+					values[i] = e.formatValue(ctx.RightSchema.Columns[col.colIndex], row.RightRow.Values[col.colIndex])
 				} else {
 					values[i] = "NULL"
 				}
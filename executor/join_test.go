@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+// 不同模式中的同名表做 JOIN 时按模式区分两侧的列
+func TestJoinSchemaQualifiedColumns(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE users (id INT PRIMARY KEY, n TEXT)",
+		"INSERT INTO users VALUES (1, 'pub1'), (2, 'pub2')",
+		"CREATE SCHEMA ta",
+		"CREATE TABLE ta.users (id INT PRIMARY KEY, n TEXT)",
+		"INSERT INTO ta.users VALUES (1, 'ta1')",
+	)
+
+	// 不带模式的 users 指语句中写作 users 的一侧
+	sql := "SELECT users.n, ta.users.n FROM users JOIN ta.users ON users.id = ta.users.id"
+	expectRows(t, e, sql, "pub1\tta1")
+	result, err := e.Execute(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "users.n\tta.users.n\n"; !strings.HasPrefix(result, want) {
+		t.Fatalf("unexpected header in %q", result)
+	}
+	expectRows(t, e, "SELECT public.users.n, ta.users.n FROM users JOIN ta.users ON public.users.id = ta.users.id", "pub1\tta1")
+
+	// 两侧都只有表名部分相同时有歧义
+	mustExec(t, e,
+		"CREATE SCHEMA tb",
+		"CREATE TABLE tb.users (id INT PRIMARY KEY, n TEXT)",
+		"INSERT INTO tb.users VALUES (1, 'tb1')",
+	)
+	mustFail(t, e, "SELECT ta.users.n FROM ta.users JOIN tb.users ON users.id = tb.users.id", "column reference users.id is ambiguous")
+	expectRows(t, e, "SELECT ta.users.n, tb.users.n FROM ta.users JOIN tb.users ON ta.users.id = tb.users.id", "ta1\ttb1")
+
+	expectRows(t, e, "SELECT p.n, t.n FROM users p LEFT JOIN ta.users t ON p.id = t.id ORDER BY p.id",
+		"pub1\tta1",
+		"pub2\tNULL",
+	)
+	mustFail(t, e, "SELECT n FROM users p JOIN ta.users t ON p.id = t.id", "column reference n is ambiguous")
+}
@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/transaction"
+	"strings"
+)
+
+// createMaterializedView 创建物化视图：查询结果保存在一张普通的堆表中，表定义记录视图的查询
+// 物化视图可以像表一样查询和创建索引，但不能直接修改，只能通过 REFRESH MATERIALIZED VIEW 重新计算
+func (e *Executor) createMaterializedView(view *catalog.View, result *catalog.TableSchema, rows []*storage.Row) error {
+	schema := &catalog.TableSchema{
+		Name:    view.Name,
+		Columns: result.Columns,
+		View:    view,
+	}
+
+	if err := e.lockDDL(view.Name); err != nil {
+		return err
+	}
+
+	// 创建表存储
+	tableStorage, err := storage.NewTableStorage(e.pager, len(schema.Columns))
+	if err != nil {
+		return fmt.Errorf("failed to create table storage: %w", err)
+	}
+	schema.FirstPageID = tableStorage.GetFirstPageID()
+
+	// 在 catalog 中创建表
+	if err := e.catalog.CreateTable(schema); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := tableStorage.InsertRow(row); err != nil {
+			e.dropTableOnError(view.Name)
+			return fmt.Errorf("failed to insert row: %w", err)
+		}
+	}
+	e.logCreateTable(view.Name)
+	return e.pager.FlushAll()
+}
+
+// executeRefreshMaterializedView 执行 REFRESH MATERIALIZED VIEW
+// 重新计算的结果写入新的页，索引重建成功后一起替换，失败时物化视图保持原来的内容
+func (e *Executor) executeRefreshMaterializedView(sql string) (string, error) {
+	stmt, err := parser.ParseRefreshMaterializedView(sql)
+	if err != nil {
+		return "", err
+	}
+	if e.currentTx != nil {
+		return "", fmt.Errorf("REFRESH MATERIALIZED VIEW cannot run inside a transaction")
+	}
+
+	name, err := e.resolveRelation(stmt.Name)
+	if err != nil {
+		return "", err
+	}
+	schema, err := e.getMaterializedView(name)
+	if err != nil {
+		return "", err
+	}
+
+	txID := e.getCurrentTxID()
+	lockManager := e.txManager.GetLockManager()
+	if err := lockManager.AcquireWriteLock(name, transaction.TransactionID(txID)); err != nil {
+		return "", fmt.Errorf("failed to acquire write lock: %w", err)
+	}
+	defer lockManager.ReleaseLocks(transaction.TransactionID(txID))
+
+	result, rows, err := e.runViewQuery(schema.View)
+	if err != nil {
+		return "", fmt.Errorf("materialized view %s: %w", name, err)
+	}
+
+	// 引用的表修改后查询结果的列可能不再一致
+	if len(result.Columns) != len(schema.Columns) {
+		return "", fmt.Errorf("materialized view %s: query returns %d columns, but the view has %d", name, len(result.Columns), len(schema.Columns))
+	}
+	for i, column := range schema.Columns {
+		for _, row := range rows {
+			if value := row.Values[i]; !value.IsNull() && value.Type != column.Type {
+				return "", fmt.Errorf("materialized view %s: column %s is now %s, but the view has %s; drop and recreate it", name, column.Name, value.Type, column.Type)
+			}
+		}
+	}
+
+	if err := e.replaceTableRows(schema, schema.Clone(), e.catalog.GetIndexesByTable(name), rows); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Materialized view '%s' refreshed with %d row(s)", name, len(rows)), nil
+}
+
+// dropMaterializedView 删除物化视图及其索引
+func (e *Executor) dropMaterializedView(name string) error {
+	if _, err := e.getMaterializedView(name); err != nil {
+		return err
+	}
+	return e.dropTable(name)
+}
+
+// getMaterializedView 获取物化视图的表定义
+func (e *Executor) getMaterializedView(name string) (*catalog.TableSchema, error) {
+	schema, err := e.catalog.GetTable(name)
+	if err != nil || schema.View == nil {
+		return nil, fmt.Errorf("materialized view not found: %s", name)
+	}
+	return schema, nil
+}
+
+// isRefreshMaterializedView 检查是否是 REFRESH MATERIALIZED VIEW 语句
+func isRefreshMaterializedView(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "REFRESH MATERIALIZED VIEW")
+}
@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// 重复 REFRESH 复用旧的行和索引释放的页，数据文件不会增长
+func TestRefreshMaterializedViewReusesPages(t *testing.T) {
+	e, dbFile := newTestExecutor(t)
+	mustExec(t, e, "CREATE TABLE items (id INT PRIMARY KEY, name TEXT)")
+	for i := 1; i <= 20; i++ {
+		mustExec(t, e, fmt.Sprintf("INSERT INTO items VALUES (%d, 'item %d')", i, i))
+	}
+	mustExec(t, e,
+		"CREATE MATERIALIZED VIEW item_names AS SELECT id, name FROM items",
+		"CREATE INDEX item_names_id ON item_names (id)",
+		"REFRESH MATERIALIZED VIEW item_names",
+	)
+
+	info, err := os.Stat(dbFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := info.Size()
+
+	for i := 0; i < 50; i++ {
+		mustExec(t, e, "REFRESH MATERIALIZED VIEW item_names")
+	}
+	if info, err = os.Stat(dbFile); err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > size {
+		t.Fatalf("data file grew from %d to %d bytes after repeated REFRESH", size, info.Size())
+	}
+
+	expectRows(t, e, "SELECT name FROM item_names WHERE id = 7", "item 7")
+	expectRows(t, e, "SELECT index_name, entries FROM godb_indexes WHERE table_name = 'item_names'", "item_names_id\t20")
+}
@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/storage"
+	"godb/types"
+	"sort"
+	"strconv"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// orderKey ORDER BY 中的一个排序键
+type orderKey struct {
+	expr      sqlparser.Expr  // 排序表达式
+	desc      bool            // 是否降序
+	collation types.Collation // TEXT 值的排序规则
+}
+
+// sortRows 按 ORDER BY 对单表查询结果排序
+// 排序键可以是列、表达式、SELECT 列表中的别名或位置（从 1 开始）
+func (e *Executor) sortRows(rows []*storage.Row, orderBy sqlparser.OrderBy, schema *catalog.TableSchema, items []selectItem) error {
+	if len(orderBy) == 0 {
+		return nil
+	}
+
+	keys := make([]orderKey, 0, len(orderBy))
+	for _, order := range orderBy {
+		expr, err := resolveOrderExpr(order.Expr, schema, items)
+		if err != nil {
+			return err
+		}
+
+		collation, err := resolveCollation([]sqlparser.Expr{expr}, []*catalog.Column{exprColumnRef(expr, schema)})
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, orderKey{
+			expr:      expr,
+			desc:      order.Direction == sqlparser.DescScr,
+			collation: collation,
+		})
+	}
+
+	// 先计算每行的排序键值
+	values := make(map[*storage.Row][]types.Value, len(rows))
+	for _, row := range rows {
+		rowValues := make([]types.Value, len(keys))
+		for i, key := range keys {
+			value, err := e.evalRowExpr(row, key.expr, schema)
+			if err != nil {
+				return err
+			}
+			rowValues[i] = value
+		}
+		values[row] = rowValues
+	}
+
+	var sortErr error
+	sort.SliceStable(rows, func(i, j int) bool {
+		left, right := values[rows[i]], values[rows[j]]
+		for k, key := range keys {
+			cmp, err := compareOrderValue(&left[k], &right[k], key)
+			if err != nil && sortErr == nil {
+				sortErr = err
+			}
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+
+	return sortErr
+}
+
+// resolveOrderExpr 把 ORDER BY 中的位置和别名解析为对应的 SELECT 表达式
+func resolveOrderExpr(expr sqlparser.Expr, schema *catalog.TableSchema, items []selectItem) (sqlparser.Expr, error) {
+	// ORDER BY 1
+	if val, ok := expr.(*sqlparser.SQLVal); ok && val.Type == sqlparser.IntVal {
+		pos, err := strconv.Atoi(string(val.Val))
+		if err != nil || pos < 1 || pos > len(items) {
+			return nil, fmt.Errorf("ORDER BY position %s is not in select list", string(val.Val))
+		}
+		return itemExpr(items[pos-1], schema), nil
+	}
+
+	// ORDER BY alias（列名优先）
+	if colName, ok := unwrapCollate(expr).(*sqlparser.ColName); ok && colName.Qualifier.IsEmpty() {
+		if schema.GetColumnIndex(colName.Name.String()) == -1 {
+			for _, item := range items {
+				if item.name != colName.Name.String() {
+					continue
+				}
+				resolved := itemExpr(item, schema)
+				if collateExpr, ok := expr.(*sqlparser.CollateExpr); ok {
+					resolved = &sqlparser.CollateExpr{Expr: resolved, Charset: collateExpr.Charset}
+				}
+				return resolved, nil
+			}
+		}
+	}
+
+	return expr, nil
+}
+
+// itemExpr 返回 SELECT 列表项对应的表达式
+func itemExpr(item selectItem, schema *catalog.TableSchema) sqlparser.Expr {
+	if item.colIndex != -1 {
+		return &sqlparser.ColName{Name: sqlparser.NewColIdent(schema.Columns[item.colIndex].Name)}
+	}
+	return item.expr
+}
+
+// sortJoinedRows 按 ORDER BY 对 JOIN 查询结果排序，排序键必须是列
+// 外连接中不存在的一侧视为 NULL，升序时排在最前面
+func (e *Executor) sortJoinedRows(rows []*JoinedRow, orderBy sqlparser.OrderBy, ctx *JoinContext) error {
+	if len(orderBy) == 0 {
+		return nil
+	}
+
+	keys := make([]orderKey, 0, len(orderBy))
+	columns := make([]*columnInfo, 0, len(orderBy))
+	for _, order := range orderBy {
+		col, err := e.parseColumnInfo(order.Expr, ctx)
+		if err != nil {
+			return err
+		}
+
+		collation, err := resolveCollation([]sqlparser.Expr{order.Expr}, []*catalog.Column{ctx.column(col)})
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, orderKey{
+			expr:      order.Expr,
+			desc:      order.Direction == sqlparser.DescScr,
+			collation: collation,
+		})
+		columns = append(columns, col)
+	}
+
+	// 取出排序键值，nil 表示 NULL
+	values := make(map[*JoinedRow][]*types.Value, len(rows))
+	for _, row := range rows {
+		rowValues := make([]*types.Value, len(columns))
+		for i, col := range columns {
+			source := row.RightRow
+			if col.isLeft {
+				source = row.LeftRow
+			}
+			if source != nil {
+				rowValues[i] = &source.Values[col.colIndex]
+			}
+		}
+		values[row] = rowValues
+	}
+
+	var sortErr error
+	sort.SliceStable(rows, func(i, j int) bool {
+		left, right := values[rows[i]], values[rows[j]]
+		for k, key := range keys {
+			cmp, err := compareOrderValue(left[k], right[k], key)
+			if err != nil && sortErr == nil {
+				sortErr = err
+			}
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+
+	return sortErr
+}
+
+// compareOrderValue 按排序键比较两个值（nil 表示 NULL，升序时排在最前面）
+func compareOrderValue(left, right *types.Value, key orderKey) (int, error) {
+	var cmp int
+	switch {
+	case left == nil && right == nil:
+		cmp = 0
+	case left == nil:
+		cmp = -1
+	case right == nil:
+		cmp = 1
+	default:
+		l, r, err := types.CoerceForComparison(*left, *right)
+		if err != nil {
+			return 0, err
+		}
+		cmp = types.Compare(l, r, key.collation)
+	}
+
+	if key.desc {
+		cmp = -cmp
+	}
+	return cmp, nil
+}
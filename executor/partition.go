@@ -0,0 +1,433 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/types"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// buildPartitioning 根据 PARTITION BY 子句创建表的分区方式
+// 分区键不能是 VIRTUAL 生成列（行中不保存它的值）或枚举列；HASH 分区命名为 p0、p1、...
+func (e *Executor) buildPartitioning(schema *catalog.TableSchema, spec *parser.PartitionSpec) (*catalog.Partitioning, error) {
+	colIndex := schema.GetColumnIndex(spec.Column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column not found: %s", spec.Column)
+	}
+	column := schema.Columns[colIndex]
+	if column.IsVirtual() {
+		return nil, fmt.Errorf("partition key cannot be virtual generated column %s", column.Name)
+	}
+	if column.Type == types.TypeEnum {
+		return nil, fmt.Errorf("partition key of type %s is not supported", column.DeclaredType())
+	}
+
+	partitioning := &catalog.Partitioning{Method: spec.Method, Column: column.Name}
+	if spec.Method == "HASH" {
+		for i := 0; i < spec.Count; i++ {
+			partitioning.Partitions = append(partitioning.Partitions, &catalog.Partition{Name: fmt.Sprintf("p%d", i)})
+		}
+	} else {
+		partitioning.Partitions = newPartitions(spec.Partitions)
+	}
+
+	if err := partitioning.Validate(column); err != nil {
+		return nil, err
+	}
+	return partitioning, nil
+}
+
+// newPartitions 把分区定义转换为分区（尚未分配数据页）
+func newPartitions(defs []*parser.PartitionDef) []*catalog.Partition {
+	partitions := make([]*catalog.Partition, len(defs))
+	for i, def := range defs {
+		partitions[i] = &catalog.Partition{
+			Name:     def.Name,
+			LessThan: def.LessThan,
+			MaxValue: def.MaxValue,
+			Values:   def.Values,
+			HasNull:  def.HasNull,
+		}
+	}
+	return partitions
+}
+
+// partitionKey 返回分区键的列序号和列定义
+func partitionKey(schema *catalog.TableSchema) (int, catalog.Column) {
+	colIndex := schema.GetColumnIndex(schema.Partitioning.Column)
+	return colIndex, schema.Columns[colIndex]
+}
+
+// prunePartitions 分区裁剪：根据 WHERE 条件返回只读取可能包含匹配行的分区的表存储
+// 不是分区表或无法根据条件排除任何分区时返回原来的表存储
+func (e *Executor) prunePartitions(schema *catalog.TableSchema, where sqlparser.Expr, tableStorage *storage.TableStorage) *storage.TableStorage {
+	if schema.Partitioning == nil {
+		return tableStorage
+	}
+	matched, ok := e.matchPartitions(schema, where)
+	if !ok {
+		return tableStorage
+	}
+
+	partitions := make([]int, 0, len(matched))
+	for i, match := range matched {
+		if match {
+			partitions = append(partitions, i)
+		}
+	}
+	if len(partitions) == len(matched) {
+		return tableStorage
+	}
+	return tableStorage.Prune(partitions)
+}
+
+// matchPartitions 返回每个分区是否可能包含满足条件的行，ok 为 false 表示无法根据条件判断
+// 支持分区键与常量的比较（=、<、<=、>、>=）和 IS NULL，以及它们的 AND / OR 组合
+func (e *Executor) matchPartitions(schema *catalog.TableSchema, expr sqlparser.Expr) ([]bool, bool) {
+	switch expr := expr.(type) {
+	case *sqlparser.ParenExpr:
+		return e.matchPartitions(schema, expr.Expr)
+
+	case *sqlparser.AndExpr:
+		left, leftOK := e.matchPartitions(schema, expr.Left)
+		right, rightOK := e.matchPartitions(schema, expr.Right)
+		switch {
+		case !leftOK:
+			return right, rightOK
+		case !rightOK:
+			return left, true
+		}
+		for i := range left {
+			left[i] = left[i] && right[i]
+		}
+		return left, true
+
+	case *sqlparser.OrExpr:
+		left, leftOK := e.matchPartitions(schema, expr.Left)
+		right, rightOK := e.matchPartitions(schema, expr.Right)
+		if !leftOK || !rightOK {
+			return nil, false
+		}
+		for i := range left {
+			left[i] = left[i] || right[i]
+		}
+		return left, true
+
+	case *sqlparser.IsExpr:
+		if expr.Operator != sqlparser.IsNullStr || !e.isPartitionKey(schema, expr.Expr) {
+			return nil, false
+		}
+		return e.partitionsForValues(schema, []types.Value{types.NewNullValue()}), true
+
+	case *sqlparser.ComparisonExpr:
+		return e.matchComparison(schema, expr)
+	}
+	return nil, false
+}
+
+// matchComparison 根据分区键与常量的比较确定可能包含匹配行的分区
+func (e *Executor) matchComparison(schema *catalog.TableSchema, expr *sqlparser.ComparisonExpr) ([]bool, bool) {
+	left, right, operator := expr.Left, expr.Right, expr.Operator
+	if !e.isPartitionKey(schema, left) {
+		// 常量在左边时交换操作数
+		flipped := map[string]string{"=": "=", "<": ">", "<=": ">=", ">": "<", ">=": "<="}
+		if _, ok := flipped[operator]; !ok || !e.isPartitionKey(schema, right) {
+			return nil, false
+		}
+		left, right, operator = right, left, flipped[operator]
+	}
+
+	// 比较的排序规则与分区键不同时不能裁剪
+	_, column := partitionKey(schema)
+	collation, err := comparisonCollation(left, right, schema)
+	if err != nil || (column.Type == types.TypeText && collation.String() != column.Collation.String()) {
+		return nil, false
+	}
+
+	switch operator {
+	case "=", "<", "<=", ">", ">=":
+		value, err := e.evalColumnExpr(right, column)
+		if err != nil {
+			return nil, false
+		}
+		if value.IsNull() {
+			// 与 NULL 比较的结果总是未知，没有匹配的行
+			return make([]bool, len(schema.Partitioning.Partitions)), true
+		}
+		if operator == "=" {
+			return e.partitionsForValues(schema, []types.Value{value}), true
+		}
+		return e.partitionsInRange(schema, operator, value)
+	}
+	return nil, false
+}
+
+// isPartitionKey 判断表达式是否是对分区键的引用
+func (e *Executor) isPartitionKey(schema *catalog.TableSchema, expr sqlparser.Expr) bool {
+	column, ok := exprColumn(expr, schema)
+	return ok && column.Name == schema.Partitioning.Column
+}
+
+// partitionsForValues 返回包含这些值的分区（不属于任何分区的值没有匹配的行）
+func (e *Executor) partitionsForValues(schema *catalog.TableSchema, values []types.Value) []bool {
+	partitioning := schema.Partitioning
+	_, column := partitionKey(schema)
+	matched := make([]bool, len(partitioning.Partitions))
+	for _, value := range values {
+		if partition, err := partitioning.PartitionFor(value, column); err == nil {
+			matched[partition] = true
+		}
+	}
+	return matched
+}
+
+// partitionsInRange 返回可能包含满足 key operator value 的行的分区
+// RANGE 分区比较分区的上下界，LIST 分区比较列出的值，HASH 分区无法按范围裁剪
+func (e *Executor) partitionsInRange(schema *catalog.TableSchema, operator string, value types.Value) ([]bool, bool) {
+	partitioning := schema.Partitioning
+	_, column := partitionKey(schema)
+	matched := make([]bool, len(partitioning.Partitions))
+
+	// satisfies 判断边界值或列出的值是否满足 operator value（无法比较时保守地认为满足）
+	satisfies := func(text, operator string) bool {
+		bound, err := e.convertToColumn(types.NewTextValue(text), column)
+		if err != nil {
+			return true
+		}
+		return compareWith(types.Compare(bound, value, column.Collation), operator)
+	}
+
+	switch partitioning.Method {
+	case "RANGE":
+		// 分区 i 包含 [上一个分区的上界, 本分区的上界) 中的值：
+		// 小于（等于）value 的值要求下界小于（等于）value，大于（等于）value 的值要求上界大于 value
+		for i, partition := range partitioning.Partitions {
+			if operator == "<" || operator == "<=" {
+				matched[i] = i == 0 || satisfies(partitioning.Partitions[i-1].LessThan, operator)
+			} else {
+				matched[i] = partition.MaxValue || satisfies(partition.LessThan, ">")
+			}
+		}
+	case "LIST":
+		for i, partition := range partitioning.Partitions {
+			for _, text := range partition.Values {
+				if satisfies(text, operator) {
+					matched[i] = true
+					break
+				}
+			}
+		}
+	default:
+		return nil, false
+	}
+	return matched, true
+}
+
+// compareWith 判断比较结果 cmp（-1、0、1）是否满足比较运算符
+func compareWith(cmp int, operator string) bool {
+	switch operator {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return cmp == 0
+}
+
+// alterAddPartition 为 RANGE 或 LIST 分区表添加分区（RANGE 分区只能添加在最后，上界大于已有的分区）
+func (e *Executor) alterAddPartition(schema *catalog.TableSchema, action *parser.AlterTableAction) error {
+	if schema.Partitioning == nil {
+		return fmt.Errorf("table %s is not partitioned", schema.Name)
+	}
+	method := schema.Partitioning.Method
+	if method == "HASH" {
+		return fmt.Errorf("cannot add partitions to HASH partitioned table %s", schema.Name)
+	}
+	for _, def := range action.Partitions {
+		if def.Range != (method == "RANGE") {
+			return fmt.Errorf("partition %s does not match partitioning method %s", def.Name, method)
+		}
+	}
+
+	newSchema := schema.Clone()
+	added := newPartitions(action.Partitions)
+	newSchema.Partitioning.Partitions = append(newSchema.Partitioning.Partitions, added...)
+	_, column := partitionKey(newSchema)
+	if err := newSchema.Partitioning.Validate(column); err != nil {
+		return err
+	}
+
+	pager := e.pagerFor(schema)
+	for _, partition := range added {
+		if err := catalog.AllocatePartition(pager, newSchema, partition); err != nil {
+			return fmt.Errorf("failed to create partition storage: %w", err)
+		}
+	}
+	if err := e.catalog.ReplaceTable(newSchema, e.catalog.GetIndexesByTable(schema.Name)); err != nil {
+		for _, partition := range added {
+			pager.FreePages([]uint32{partition.FirstPageID})
+		}
+		return err
+	}
+	return nil
+}
+
+// alterDropPartition 删除分区和分区中的行，分区的页被释放
+func (e *Executor) alterDropPartition(schema *catalog.TableSchema, action *parser.AlterTableAction) error {
+	partitionIndex, err := e.removablePartition(schema, action.Name, action.IfExists)
+	if err != nil || partitionIndex == -1 {
+		return err
+	}
+	pager := e.pagerFor(schema)
+	tableStorage, err := catalog.CreateTableStorage(pager, schema)
+	if err != nil {
+		return err
+	}
+	partitionStorage := tableStorage.Prune([]int{partitionIndex})
+	pageIDs, err := partitionStorage.GetPageIDs()
+	if err != nil {
+		return err
+	}
+
+	if err := e.removePartition(schema, partitionIndex, partitionStorage); err != nil {
+		return err
+	}
+	pager.FreePages(pageIDs)
+	return nil
+}
+
+// alterDetachPartition 把分区分离为同一模式中与分区同名的独立表，分区的页直接交给新表
+// 新表有原表的列和 CHECK 约束，不带主键、索引、外键和自增列的默认值
+func (e *Executor) alterDetachPartition(schema *catalog.TableSchema, action *parser.AlterTableAction) error {
+	partitionIndex, err := e.removablePartition(schema, action.Name, false)
+	if err != nil {
+		return err
+	}
+	partition := schema.Partitioning.Partitions[partitionIndex]
+
+	tableName := schemaQualified(schema.Name, partition.Name)
+	if err := checkRelationName(tableName); err != nil {
+		return err
+	}
+	if e.relationExists(tableName) {
+		return fmt.Errorf("cannot detach partition %s: relation %s already exists", partition.Name, tableName)
+	}
+
+	detached := &catalog.TableSchema{
+		Name:        tableName,
+		Columns:     append([]catalog.Column(nil), schema.Columns...),
+		FirstPageID: partition.FirstPageID,
+		Temporary:   schema.Temporary,
+	}
+	for i, column := range detached.Columns {
+		if column.Default == fmt.Sprintf("nextval('%s')", columnSequenceName(schema.Name, column.Name)) {
+			detached.Columns[i].Default = ""
+		}
+	}
+	for _, check := range schema.Checks {
+		checkCopy := *check
+		detached.Checks = append(detached.Checks, &checkCopy)
+	}
+
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return err
+	}
+	if err := e.catalog.CreateTable(detached); err != nil {
+		return err
+	}
+	if err := e.removePartition(schema, partitionIndex, tableStorage.Prune([]int{partitionIndex})); err != nil {
+		e.catalog.DropTable(tableName)
+		return err
+	}
+	return nil
+}
+
+// removablePartition 查找要删除或分离的分区，返回分区序号（分区不存在且 ifExists 时返回 -1）
+// HASH 分区表的分区数决定行所在的分区，不能删除或分离分区；表至少保留一个分区
+func (e *Executor) removablePartition(schema *catalog.TableSchema, name string, ifExists bool) (int, error) {
+	if schema.Partitioning == nil {
+		return -1, fmt.Errorf("table %s is not partitioned", schema.Name)
+	}
+	partitionIndex := schema.Partitioning.GetPartition(name)
+	if partitionIndex == -1 {
+		if ifExists {
+			return -1, nil
+		}
+		return -1, fmt.Errorf("partition %s of table %s not found", name, schema.Name)
+	}
+	if schema.Partitioning.Method == "HASH" {
+		return -1, fmt.Errorf("cannot remove partition %s: table %s is HASH partitioned", name, schema.Name)
+	}
+	if len(schema.Partitioning.Partitions) == 1 {
+		return -1, fmt.Errorf("cannot remove partition %s: it is the only partition of table %s", name, schema.Name)
+	}
+	return partitionIndex, nil
+}
+
+// removePartition 从表中移除分区：分区中的行不能被其他行的外键引用，
+// 移除后分区的行从表的索引中删除，表的统计信息需要重新收集
+func (e *Executor) removePartition(schema *catalog.TableSchema, partitionIndex int, partitionStorage *storage.TableStorage) error {
+	rows, err := e.readAllRows(schema, partitionStorage)
+	if err != nil {
+		return err
+	}
+	if err := e.checkPartitionReferences(schema, rows); err != nil {
+		return err
+	}
+
+	newSchema := schema.Clone()
+	partitions := newSchema.Partitioning.Partitions
+	newSchema.Partitioning.Partitions = append(partitions[:partitionIndex:partitionIndex], partitions[partitionIndex+1:]...)
+	newSchema.FirstPageID = newSchema.Partitioning.Partitions[0].FirstPageID
+	if err := e.catalog.ReplaceTable(newSchema, e.catalog.GetIndexesByTable(schema.Name)); err != nil {
+		return err
+	}
+
+	columnNames := make([]string, len(schema.Columns))
+	for i, column := range schema.Columns {
+		columnNames[i] = column.Name
+	}
+	for _, row := range rows {
+		if err := e.indexManager.DeleteEntry(schema.Name, row, columnNames); err != nil {
+			return err
+		}
+	}
+	return e.pager.FlushAll()
+}
+
+// checkPartitionReferences 检查分区中的行没有被分区外的行通过外键引用
+func (e *Executor) checkPartitionReferences(schema *catalog.TableSchema, rows []*storage.Row) error {
+	inPartition := make(map[storage.RowID]bool, len(rows))
+	for _, row := range rows {
+		inPartition[row.ID] = true
+	}
+
+	for _, fk := range e.catalog.GetForeignKeysReferencing(schema.Name) {
+		colIndex := schema.GetColumnIndex(fk.RefColumn)
+		for _, row := range rows {
+			key := row.Values[colIndex]
+			if key.IsNull() {
+				continue
+			}
+			referencing, err := e.referencingRows(&writeSet{}, fk, key)
+			if err != nil {
+				return err
+			}
+			for _, other := range referencing {
+				if fk.Table != schema.Name || !inPartition[other.ID] {
+					return fmt.Errorf("cannot remove partition of table %s: its rows are referenced by foreign key %s on table %s",
+						schema.Name, fk.Name, fk.Table)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// executeCreateSchema 执行 CREATE SCHEMA
+// 语法: CREATE SCHEMA [IF NOT EXISTS] name
+func (e *Executor) executeCreateSchema(sql string) (string, error) {
+	pattern := `(?i)^\s*CREATE\s+SCHEMA\s+(IF\s+NOT\s+EXISTS\s+)?(\w+)\s*;?\s*$`
+	matches := regexp.MustCompile(pattern).FindStringSubmatch(sql)
+	if len(matches) != 3 {
+		return "", fmt.Errorf("invalid CREATE SCHEMA syntax, expected: CREATE SCHEMA [IF NOT EXISTS] name")
+	}
+	name := matches[2]
+
+	if e.currentTx != nil {
+		return "", fmt.Errorf("CREATE SCHEMA cannot run inside a transaction")
+	}
+	if _, attached := e.attached[name]; attached {
+		return "", fmt.Errorf("schema name %s conflicts with attached database %s", name, name)
+	}
+	if matches[1] != "" && e.catalog.SchemaExists(name) {
+		return fmt.Sprintf("Schema '%s' already exists, skipped", name), nil
+	}
+
+	if err := e.catalog.CreateSchema(name); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Schema '%s' created successfully", name), nil
+}
+
+// executeDropSchema 执行 DROP SCHEMA
+// 语法: DROP SCHEMA [IF EXISTS] name [CASCADE | RESTRICT]
+// CASCADE 删除模式中的视图、表和序列，以及其他模式中依赖它们的视图和外键
+func (e *Executor) executeDropSchema(sql string) (string, error) {
+	pattern := `(?i)^\s*DROP\s+SCHEMA\s+(IF\s+EXISTS\s+)?(\w+)(?:\s+(CASCADE|RESTRICT))?\s*;?\s*$`
+	matches := regexp.MustCompile(pattern).FindStringSubmatch(sql)
+	if len(matches) != 4 {
+		return "", fmt.Errorf("invalid DROP SCHEMA syntax, expected: DROP SCHEMA [IF EXISTS] name [CASCADE | RESTRICT]")
+	}
+	name := matches[2]
+
+	// CASCADE 删除的视图、序列和外键不记录在事务日志中，无法回滚
+	if e.currentTx != nil {
+		return "", fmt.Errorf("DROP SCHEMA cannot run inside a transaction")
+	}
+	if matches[1] != "" && !e.catalog.SchemaExists(name) {
+		return fmt.Sprintf("Schema '%s' does not exist, skipped", name), nil
+	}
+	if name == catalog.DefaultSchema {
+		return "", fmt.Errorf("cannot drop schema %s", name)
+	}
+	if !e.catalog.SchemaExists(name) {
+		return "", fmt.Errorf("schema not found: %s", name)
+	}
+
+	if strings.EqualFold(matches[3], "CASCADE") {
+		if err := e.dropSchemaObjects(name); err != nil {
+			return "", err
+		}
+	}
+
+	if err := e.catalog.DropSchema(name); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Schema '%s' dropped successfully", name), nil
+}
+
+// dropSchemaObjects 删除模式中的所有对象（DROP SCHEMA ... CASCADE）
+func (e *Executor) dropSchemaObjects(name string) error {
+	tables, views, sequences := e.catalog.SchemaObjects(name)
+	inSchema := make(map[string]bool)
+	for _, relation := range append(append(append([]string{}, tables...), views...), sequences...) {
+		inSchema[relation] = true
+	}
+
+	// 先删除模式中的视图和物化视图，以及直接或间接依赖模式中对象的其他视图
+	// 视图之间可能相互依赖，反复删除没有被依赖的视图
+	if err := dropRepeatedly(e.dependentViews(inSchema), func(view string) error {
+		if _, err := e.catalog.GetView(view); err == nil {
+			return e.catalog.DropView(view)
+		}
+		return e.dropTable(view)
+	}); err != nil {
+		return err
+	}
+
+	// 其他模式中的表引用模式中的表的外键随之删除
+	for _, tableName := range e.catalog.ListTables() {
+		if inSchema[tableName] {
+			continue
+		}
+		table, err := e.catalog.GetTable(tableName)
+		if err != nil {
+			continue
+		}
+		for _, fk := range append([]*catalog.ForeignKey(nil), table.ForeignKeys...) {
+			if inSchema[fk.RefTable] {
+				if _, err := e.catalog.DropConstraint(tableName, fk.Name, false); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// 模式中的表之间可能有外键，反复删除没有被其他表引用的表
+	tables, _, _ = e.catalog.SchemaObjects(name)
+	if err := dropRepeatedly(tables, e.dropTable); err != nil {
+		return err
+	}
+
+	for _, seqName := range sequences {
+		if err := e.catalog.DropSequence(seqName); err != nil {
+			return err
+		}
+		delete(e.sequenceValues, seqName)
+	}
+	return nil
+}
+
+// dropRepeatedly 反复删除对象直到全部删除，一轮中没有任何对象删除成功时返回最后的错误
+func dropRepeatedly(names []string, drop func(string) error) error {
+	for len(names) > 0 {
+		remaining := make([]string, 0)
+		var lastErr error
+		for _, name := range names {
+			if err := drop(name); err != nil {
+				remaining = append(remaining, name)
+				lastErr = err
+			}
+		}
+		if len(remaining) == len(names) {
+			return lastErr
+		}
+		names = remaining
+	}
+	return nil
+}
+
+// dependentViews 返回 relations 中的视图和物化视图，以及直接或间接依赖 relations 中对象的视图和物化视图
+func (e *Executor) dependentViews(relations map[string]bool) []string {
+	depends := make(map[string][]string)
+	for _, viewName := range e.catalog.ListViews() {
+		if view, err := e.catalog.GetView(viewName); err == nil {
+			depends[viewName] = view.Depends
+		}
+	}
+	for _, tableName := range e.catalog.ListTables() {
+		if table, err := e.catalog.GetTable(tableName); err == nil && table.View != nil {
+			depends[tableName] = table.View.Depends
+		}
+	}
+
+	selected := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for viewName, deps := range depends {
+			if selected[viewName] {
+				continue
+			}
+			hit := relations[viewName]
+			for _, dep := range deps {
+				hit = hit || relations[dep] || selected[dep]
+			}
+			if hit {
+				selected[viewName] = true
+				changed = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(selected))
+	for viewName := range selected {
+		result = append(result, viewName)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// executeSetSearchPath 执行 SET search_path，设置本会话解析不带模式的名字时依次查找的模式
+// 语法: SET search_path { TO | = } schema [, ...]
+func (e *Executor) executeSetSearchPath(sql string) (string, error) {
+	pattern := `(?i)^\s*SET\s+search_path\s*(?:TO\s+|=\s*)(\w+(?:\s*,\s*\w+)*)\s*;?\s*$`
+	matches := regexp.MustCompile(pattern).FindStringSubmatch(sql)
+	if len(matches) != 2 {
+		return "", fmt.Errorf("invalid SET search_path syntax, expected: SET search_path TO schema [, ...]")
+	}
+
+	// 和 PostgreSQL 一样，不存在的模式在解析名字时跳过
+	path := make([]string, 0)
+	for _, name := range strings.Split(matches[1], ",") {
+		path = append(path, strings.TrimSpace(name))
+	}
+	e.searchPath = path
+	return "SET", nil
+}
+
+// showSearchPath 输出本会话的 search_path
+func (e *Executor) showSearchPath() string {
+	return formatRows([]string{"search_path"}, [][]string{{strings.Join(e.getSearchPath(), ", ")}})
+}
+
+// getSearchPath 返回本会话的 search_path
+func (e *Executor) getSearchPath() []string {
+	if e.searchPath == nil {
+		return []string{catalog.DefaultSchema}
+	}
+	return e.searchPath
+}
+
+// withSearchPath 按给定的 search_path 执行 fn（展开视图时按创建视图时的 search_path 解析查询中的名字）
+func (e *Executor) withSearchPath(path []string, fn func() error) error {
+	saved := e.searchPath
+	e.searchPath = path
+	defer func() { e.searchPath = saved }()
+	return fn()
+}
+
+// resolveName 把语句中的 [schema.]name 解析为元数据中的名字
+// 带模式时模式必须存在；不带模式时按 search_path 依次查找 exists 为真的名字，都不存在时返回第一个存在的模式中的名字
+func (e *Executor) resolveName(name string, exists func(string) bool) (string, error) {
+	if schema, base, ok := strings.Cut(name, "."); ok {
+		if _, attached := e.attached[schema]; attached {
+			// 附加的数据库中的对象，读取时由附加的数据库解析
+			return name, nil
+		}
+		if !e.catalog.SchemaExists(schema) {
+			return "", fmt.Errorf("schema not found: %s", schema)
+		}
+		return catalog.QualifiedName(schema, base), nil
+	}
+
+	// 本会话的临时表优先于 search_path 中的同名对象
+	if temp := catalog.QualifiedName(catalog.TempSchema, name); exists(temp) {
+		return temp, nil
+	}
+
+	first := ""
+	for _, schema := range e.getSearchPath() {
+		if !e.catalog.SchemaExists(schema) {
+			continue
+		}
+		qualified := catalog.QualifiedName(schema, name)
+		if exists(qualified) {
+			return qualified, nil
+		}
+		if first == "" && schema != catalog.TempSchema {
+			first = qualified
+		}
+	}
+	if first == "" {
+		return "", fmt.Errorf("no schema in search_path exists: %s", strings.Join(e.getSearchPath(), ", "))
+	}
+	return first, nil
+}
+
+// resolveRelation 解析表、视图或序列名（系统表不属于任何模式）
+func (e *Executor) resolveRelation(name string) (string, error) {
+	if isSystemTable(name) {
+		return name, nil
+	}
+	return e.resolveName(name, e.relationExists)
+}
+
+// resolveTableName 解析 sqlparser 中的表名
+func (e *Executor) resolveTableName(table sqlparser.TableName) (string, error) {
+	name := table.Name.String()
+	if !table.Qualifier.IsEmpty() {
+		name = table.Qualifier.String() + "." + name
+	}
+	return e.resolveRelation(name)
+}
+
+// resolveIndexName 解析索引名
+func (e *Executor) resolveIndexName(name string) (string, error) {
+	return e.resolveName(name, func(qualified string) bool {
+		_, err := e.catalog.GetIndex(qualified)
+		return err == nil
+	})
+}
+
+// newRelationName 返回新建的表、视图或序列在元数据中的名字：不带模式时创建在 search_path 中第一个存在的模式中
+func (e *Executor) newRelationName(name string) (string, error) {
+	if db, _ := e.attachedRelation(name); db != nil {
+		return "", fmt.Errorf("cannot create %s: attached databases are read-only", name)
+	}
+	if catalog.IsTemporaryName(name) {
+		return "", fmt.Errorf("cannot create %s in temporary schema, use CREATE TEMPORARY TABLE", name)
+	}
+	return e.resolveName(name, func(string) bool { return false })
+}
+
+// schemaQualified 返回与 relation 在同一模式中的对象名（索引和外键的名字在模式内唯一）
+func schemaQualified(relation, name string) string {
+	schema, _ := catalog.SplitName(relation)
+	return catalog.QualifiedName(schema, name)
+}
+
+// relationExists 判断表、视图或序列是否存在
+func (e *Executor) relationExists(name string) bool {
+	if _, err := e.catalog.GetTable(name); err == nil {
+		return true
+	}
+	if _, err := e.catalog.GetView(name); err == nil {
+		return true
+	}
+	_, err := e.catalog.GetSequence(name)
+	return err == nil
+}
+
+// isCreateSchema 检查是否是 CREATE SCHEMA 语句
+func isCreateSchema(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "CREATE SCHEMA")
+}
+
+// isDropSchema 检查是否是 DROP SCHEMA 语句
+func isDropSchema(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "DROP SCHEMA")
+}
+
+// isSetSearchPath 检查是否是 SET search_path 语句
+func isSetSearchPath(sql string) bool {
+	fields := strings.Fields(strings.ToUpper(strings.ReplaceAll(sql, "=", " = ")))
+	return len(fields) >= 2 && fields[0] == "SET" && fields[1] == "SEARCH_PATH"
+}
@@ -3,10 +3,11 @@ package executor
 import (
 	"fmt"
 	"godb/catalog"
+	"godb/index"
+	"godb/parser"
 	"godb/storage"
 	"godb/transaction"
 	"godb/types"
-	"strings"
 
 	"github.com/xwb1989/sqlparser"
 )
@@ -20,84 +21,140 @@ func (e *Executor) executeSelect(stmt *sqlparser.Select) (string, error) {
 		}
 	}
 
+	rows, schema, selectedColumns, err := e.selectRows(stmt)
+	if err != nil {
+		return "", err
+	}
+
+	// 格式化输出
+	return e.formatResult(rows, schema, selectedColumns)
+}
+
+// selectRows 执行单表查询（FROM 可以是表或视图），返回排序后的结果行、行对应的表定义和要显示的列
+func (e *Executor) selectRows(stmt *sqlparser.Select) ([]*storage.Row, *catalog.TableSchema, []selectItem, error) {
 	// 单表查询
 	if len(stmt.From) != 1 {
-		return "", fmt.Errorf("only single table select is supported")
+		return nil, nil, nil, fmt.Errorf("only single table select is supported")
 	}
 
 	aliasedTable, ok := stmt.From[0].(*sqlparser.AliasedTableExpr)
 	if !ok {
-		return "", fmt.Errorf("invalid FROM clause")
-	}
-
-	tableName := aliasedTable.Expr.(sqlparser.TableName).Name.String()
-
-	// 获取读锁
-	txID := e.getCurrentTxID()
-	lockManager := e.txManager.GetLockManager()
-	if err := lockManager.AcquireReadLock(tableName, transaction.TransactionID(txID)); err != nil {
-		return "", fmt.Errorf("failed to acquire read lock: %w", err)
+		return nil, nil, nil, fmt.Errorf("invalid FROM clause")
 	}
 
-	// 如果是自动提交模式，在操作完成后释放锁
-	if e.currentTx == nil {
-		defer lockManager.ReleaseLocks(transaction.TransactionID(txID))
-	}
-
-	// 获取表定义
-	schema, err := e.catalog.GetTable(tableName)
-	if err != nil {
-		return "", err
-	}
-
-	// 创建表存储
-	tableStorage, err := catalog.CreateTableStorage(e.pager, schema)
-	if err != nil {
-		return "", err
-	}
+	table := aliasedTable.Expr.(sqlparser.TableName)
+	tableName := table.Name.String()
 
-	var filteredRows []*storage.Row
-
-	// 尝试使用索引查询
-	if stmt.Where != nil {
-		indexRows, used, err := e.tryIndexScan(tableName, stmt.Where.Expr, schema, tableStorage)
+	var schema *catalog.TableSchema
+	var visibleRows []*storage.Row
+	var err error
+	if tableName == "dual" && table.Qualifier.IsEmpty() {
+		// 没有 FROM 子句时 sqlparser 使用 dual 表，结果为一行
+		if stmt.Where != nil {
+			return nil, nil, nil, fmt.Errorf("WHERE requires a FROM clause")
+		}
+		schema = &catalog.TableSchema{}
+		visibleRows = []*storage.Row{{}}
+	} else if tableName, err = e.resolveTableName(table); err != nil {
+		return nil, nil, nil, err
+	} else if db, _ := e.attachedRelation(tableName); db != nil || isSystemTable(tableName) {
+		// 系统表按当前状态生成，附加的数据库中的表从附加的数据库读取
+		var rows []*storage.Row
+		schema, rows, err = e.loadRelation(tableName)
 		if err != nil {
-			return "", err
+			return nil, nil, nil, err
 		}
-		if used {
-			// 成功使用索引
-			filteredRows = indexRows
-		} else {
-			// 回退到全表扫描
-			rows, err := tableStorage.GetAllRows()
-			if err != nil {
-				return "", err
+		visibleRows = rows
+		if stmt.Where != nil {
+			if visibleRows, err = e.filterRows(rows, stmt.Where.Expr, schema); err != nil {
+				return nil, nil, nil, err
 			}
-			filteredRows, err = e.filterRows(rows, stmt.Where.Expr, schema)
-			if err != nil {
-				return "", err
+		}
+	} else if view, err := e.catalog.GetView(tableName); err == nil {
+		// 展开视图
+		var rows []*storage.Row
+		schema, rows, err = e.viewRows(view)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		visibleRows = rows
+		if stmt.Where != nil {
+			if visibleRows, err = e.filterRows(rows, stmt.Where.Expr, schema); err != nil {
+				return nil, nil, nil, err
 			}
 		}
 	} else {
-		// 没有 WHERE 条件，全表扫描
-		rows, err := tableStorage.GetAllRows()
+		// 获取读锁
+		txID := e.getCurrentTxID()
+		lockManager := e.txManager.GetLockManager()
+		if err := lockManager.AcquireReadLock(tableName, transaction.TransactionID(txID)); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to acquire read lock: %w", err)
+		}
+
+		// 如果是自动提交模式，在操作完成后释放锁
+		if e.currentTx == nil {
+			defer lockManager.ReleaseLocks(transaction.TransactionID(txID))
+		}
+
+		// 获取表定义
+		schema, err = e.catalog.GetTable(tableName)
 		if err != nil {
-			return "", err
+			return nil, nil, nil, err
+		}
+
+		// 创建表存储
+		tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		// 读取满足 WHERE 条件的行
+		filteredRows, err := e.scanRows(tableName, stmt.Where, schema, tableStorage)
+		if err != nil {
+			return nil, nil, nil, err
 		}
-		filteredRows = rows
-	}
 
-	// 应用可见性过滤（READ COMMITTED隔离）
-	visibleRows := e.filterVisibleRows(filteredRows)
+		// 应用可见性过滤（READ COMMITTED隔离）
+		visibleRows = e.filterVisibleRows(filteredRows)
+	}
 
 	// 选择要显示的列
 	selectedColumns, err := e.getSelectedColumns(stmt.SelectExprs, schema)
 	if err != nil {
-		return "", err
+		return nil, nil, nil, err
 	}
 
-	// 格式化输出
-	return e.formatResult(visibleRows, schema, selectedColumns), nil
+	// 排序
+	if err := e.sortRows(visibleRows, stmt.OrderBy, schema, selectedColumns); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return visibleRows, schema, selectedColumns, nil
+}
+
+// scanRows 读取满足 WHERE 条件的未删除行，能使用索引时使用索引查询
+func (e *Executor) scanRows(tableName string, where *sqlparser.Where, schema *catalog.TableSchema, tableStorage *storage.TableStorage) ([]*storage.Row, error) {
+	if where == nil {
+		// 没有 WHERE 条件，全表扫描
+		return e.readAllRows(schema, tableStorage)
+	}
+
+	// 尝试使用索引查询
+	indexRows, used, err := e.tryIndexScan(tableName, where.Expr, schema, tableStorage)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		// 成功使用索引
+		return indexRows, nil
+	}
+
+	// 回退到全表扫描（分区表只扫描可能包含匹配行的分区）
+	rows, err := e.readAllRows(schema, e.prunePartitions(schema, where.Expr, tableStorage))
+	if err != nil {
+		return nil, err
+	}
+	return e.filterRows(rows, where.Expr, schema)
 }
 
 // filterVisibleRows 过滤可见的行（READ COMMITTED隔离级别）
@@ -133,39 +190,50 @@ func (e *Executor) isRowVisible(row *storage.Row, currentTxID uint64) bool {
 	return isCommitted
 }
 
-// getSelectedColumns 获取要显示的列索引
-func (e *Executor) getSelectedColumns(selectExprs sqlparser.SelectExprs, schema *catalog.TableSchema) ([]int, error) {
+// selectItem SELECT 列表中的一项
+type selectItem struct {
+	name     string         // 输出列名
+	colIndex int            // 列下标（-1 表示表达式）
+	expr     sqlparser.Expr // 表达式（colIndex 为 -1 时使用）
+}
+
+// getSelectedColumns 获取要显示的列
+func (e *Executor) getSelectedColumns(selectExprs sqlparser.SelectExprs, schema *catalog.TableSchema) ([]selectItem, error) {
 	// 检查是否是 SELECT *
 	if len(selectExprs) == 1 {
 		if _, ok := selectExprs[0].(*sqlparser.StarExpr); ok {
 			// SELECT * - 返回所有列
-			result := make([]int, len(schema.Columns))
-			for i := range result {
-				result[i] = i
+			result := make([]selectItem, len(schema.Columns))
+			for i, col := range schema.Columns {
+				result[i] = selectItem{name: col.Name, colIndex: i}
 			}
 			return result, nil
 		}
 	}
 
-	// 解析指定的列
-	result := make([]int, 0)
+	// 解析指定的列和表达式
+	result := make([]selectItem, 0)
 	for _, expr := range selectExprs {
 		aliasedExpr, ok := expr.(*sqlparser.AliasedExpr)
 		if !ok {
 			return nil, fmt.Errorf("unsupported select expression")
 		}
 
-		colName, ok := aliasedExpr.Expr.(*sqlparser.ColName)
-		if !ok {
-			return nil, fmt.Errorf("unsupported select expression")
+		item := selectItem{colIndex: -1, expr: aliasedExpr.Expr}
+		if colName, ok := aliasedExpr.Expr.(*sqlparser.ColName); ok {
+			item.colIndex = schema.GetColumnIndex(colName.Name.String())
+			if item.colIndex == -1 {
+				return nil, fmt.Errorf("column not found: %s", colName.Name.String())
+			}
+			item.name = colName.Name.String()
+		} else {
+			item.name = parser.String(aliasedExpr.Expr)
 		}
-
-		colIndex := schema.GetColumnIndex(colName.Name.String())
-		if colIndex == -1 {
-			return nil, fmt.Errorf("column not found: %s", colName.Name.String())
+		if !aliasedExpr.As.IsEmpty() {
+			item.name = aliasedExpr.As.String()
 		}
 
-		result = append(result, colIndex)
+		result = append(result, item)
 	}
 
 	return result, nil
@@ -193,6 +261,12 @@ func (e *Executor) evaluateCondition(row *storage.Row, expr sqlparser.Expr, sche
 	switch expr := expr.(type) {
 	case *sqlparser.ComparisonExpr:
 		return e.evalComparison(row, expr, schema)
+	case *sqlparser.IsExpr:
+		value, err := e.evalRowExpr(row, expr.Expr, schema)
+		if err != nil {
+			return false, err
+		}
+		return evalIsNull(value, expr.Operator)
 	case *sqlparser.AndExpr:
 		left, err := e.evaluateCondition(row, expr.Left, schema)
 		if err != nil {
@@ -213,40 +287,83 @@ func (e *Executor) evaluateCondition(row *storage.Row, expr sqlparser.Expr, sche
 			return false, err
 		}
 		return left || right, nil
+	case *sqlparser.ParenExpr:
+		return e.evaluateCondition(row, expr.Expr, schema)
 	default:
-		return false, fmt.Errorf("unsupported condition type: %T", expr)
+		// 其他表达式（TRUE / FALSE、BOOLEAN 列、CAST 等）按值计算，NULL 按不满足条件处理
+		value, err := e.evalRowExpr(row, expr, schema)
+		if err != nil {
+			return false, err
+		}
+		if value.IsNull() {
+			return false, nil
+		}
+		if value.Type != types.TypeBoolean {
+			return false, fmt.Errorf("argument of WHERE must be type BOOLEAN, not type %s", value.Type)
+		}
+		return value.AsBoolean()
 	}
 }
 
 // evalComparison 计算比较表达式
 func (e *Executor) evalComparison(row *storage.Row, expr *sqlparser.ComparisonExpr, schema *catalog.TableSchema) (bool, error) {
-	// 获取左值（列）
-	leftCol, ok := expr.Left.(*sqlparser.ColName)
-	if !ok {
-		return false, fmt.Errorf("left side of comparison must be a column")
+	leftValue, err := e.evalRowExpr(row, expr.Left, schema)
+	if err != nil {
+		return false, err
 	}
 
-	colIndex := schema.GetColumnIndex(leftCol.Name.String())
-	if colIndex == -1 {
-		return false, fmt.Errorf("column not found: %s", leftCol.Name.String())
+	rightValue, err := e.evalRowExpr(row, expr.Right, schema)
+	if err != nil {
+		return false, err
 	}
 
-	leftValue := row.Values[colIndex]
+	// 枚举列与字符串比较时，把字符串按枚举类型转换为序号
+	if leftValue.Type == types.TypeEnum && rightValue.Type == types.TypeText {
+		if column, ok := exprColumn(expr.Left, schema); ok {
+			if rightValue, err = e.enumFromLabel(column.TypeName, rightValue); err != nil {
+				return false, err
+			}
+		}
+	} else if rightValue.Type == types.TypeEnum && leftValue.Type == types.TypeText {
+		if column, ok := exprColumn(expr.Right, schema); ok {
+			if leftValue, err = e.enumFromLabel(column.TypeName, leftValue); err != nil {
+				return false, err
+			}
+		}
+	}
 
-	// 获取右值
-	rightValue, err := e.evalExpr(expr.Right, leftValue.Type)
+	collation, err := comparisonCollation(expr.Left, expr.Right, schema)
 	if err != nil {
 		return false, err
 	}
 
 	// 执行比较
-	return e.compareValues(leftValue, rightValue, expr.Operator)
+	return e.compareValues(leftValue, rightValue, expr.Operator, collation)
 }
 
-// compareValues 比较两个值
-func (e *Executor) compareValues(left, right types.Value, operator string) (bool, error) {
-	if left.Type != right.Type {
-		return false, fmt.Errorf("type mismatch in comparison")
+// evalIsNull 计算 IS [NOT] NULL
+func evalIsNull(value types.Value, operator string) (bool, error) {
+	switch operator {
+	case sqlparser.IsNullStr:
+		return value.IsNull(), nil
+	case sqlparser.IsNotNullStr:
+		return !value.IsNull(), nil
+	default:
+		return false, fmt.Errorf("unsupported operator: %s", operator)
+	}
+}
+
+// compareValues 比较两个值，TEXT 按 collation 比较
+// 与 NULL 比较的结果是未知，按不满足条件处理
+func (e *Executor) compareValues(left, right types.Value, operator string, collation types.Collation) (bool, error) {
+	if left.IsNull() || right.IsNull() {
+		return false, nil
+	}
+
+	// 按隐式转换规则统一类型
+	left, right, err := types.CoerceForComparison(left, right)
+	if err != nil {
+		return false, err
 	}
 
 	switch left.Type {
@@ -258,7 +375,7 @@ func (e *Executor) compareValues(left, right types.Value, operator string) (bool
 	case types.TypeText:
 		leftText, _ := left.AsText()
 		rightText, _ := right.AsText()
-		return e.compareStrings(leftText, rightText, operator), nil
+		return e.compareStrings(leftText, rightText, operator, collation), nil
 
 	case types.TypeBoolean:
 		leftBool, _ := left.AsBoolean()
@@ -275,6 +392,17 @@ func (e *Executor) compareValues(left, right types.Value, operator string) (bool
 		rightDate, _ := right.AsDate()
 		return e.compareDates(leftDate.Unix(), rightDate.Unix(), operator), nil
 
+	case types.TypeUUID:
+		leftUUID, _ := left.AsUUID()
+		rightUUID, _ := right.AsUUID()
+		return e.compareInts(int64(leftUUID.Compare(rightUUID)), 0, operator), nil
+
+	case types.TypeEnum:
+		// 枚举按声明顺序（即序号）比较
+		leftEnum, _ := left.AsEnum()
+		rightEnum, _ := right.AsEnum()
+		return e.compareInts(int64(leftEnum), int64(rightEnum), operator), nil
+
 	default:
 		return false, fmt.Errorf("unsupported type for comparison: %s", left.Type)
 	}
@@ -299,23 +427,8 @@ func (e *Executor) compareInts(left, right int64, operator string) bool {
 	}
 }
 
-func (e *Executor) compareStrings(left, right, operator string) bool {
-	switch operator {
-	case "=":
-		return left == right
-	case "!=", "<>":
-		return left != right
-	case "<":
-		return left < right
-	case "<=":
-		return left <= right
-	case ">":
-		return left > right
-	case ">=":
-		return left >= right
-	default:
-		return false
-	}
+func (e *Executor) compareStrings(left, right, operator string, collation types.Collation) bool {
+	return e.compareInts(int64(collation.Compare(left, right)), 0, operator)
 }
 
 func (e *Executor) compareBools(left, right bool, operator string) bool {
@@ -353,32 +466,45 @@ func (e *Executor) compareDates(left, right int64, operator string) bool {
 }
 
 // formatResult 格式化查询结果
-func (e *Executor) formatResult(rows []*storage.Row, schema *catalog.TableSchema, selectedColumns []int) string {
-	var result strings.Builder
-
+func (e *Executor) formatResult(rows []*storage.Row, schema *catalog.TableSchema, selectedColumns []selectItem) (string, error) {
 	// 表头
 	headers := make([]string, len(selectedColumns))
-	for i, colIdx := range selectedColumns {
-		headers[i] = schema.Columns[colIdx].Name
+	for i, item := range selectedColumns {
+		headers[i] = item.name
 	}
-	result.WriteString(strings.Join(headers, "\t"))
-	result.WriteString("\n")
-	result.WriteString(strings.Repeat("-", len(headers)*15))
-	result.WriteString("\n")
 
 	// 数据行
+	data := make([][]string, 0, len(rows))
 	for _, row := range rows {
 		values := make([]string, len(selectedColumns))
-		for i, colIdx := range selectedColumns {
-			values[i] = row.Values[colIdx].String()
+		for i, item := range selectedColumns {
+			if item.colIndex != -1 {
+				values[i] = e.formatValue(schema.Columns[item.colIndex], row.Values[item.colIndex])
+				continue
+			}
+			value, err := e.evalRowExpr(row, item.expr, schema)
+			if err != nil {
+				return "", err
+			}
+			values[i] = value.String()
 		}
-		result.WriteString(strings.Join(values, "\t"))
-		result.WriteString("\n")
+		data = append(data, values)
 	}
 
-	result.WriteString(fmt.Sprintf("\n%d row(s) returned", len(rows)))
+	return formatRows(headers, data), nil
+}
 
-	return result.String()
+// formatValue 格式化列值（枚举列输出标签）
+func (e *Executor) formatValue(column catalog.Column, value types.Value) string {
+	if value.Type == types.TypeEnum {
+		if enumType, err := e.catalog.GetEnumType(column.TypeName); err == nil {
+			ordinal, _ := value.AsEnum()
+			if label, ok := enumType.Label(ordinal); ok {
+				return label
+			}
+		}
+	}
+	return value.String()
 }
 
 // tryIndexScan 尝试使用索引扫描
@@ -391,8 +517,8 @@ func (e *Executor) tryIndexScan(tableName string, whereExpr sqlparser.Expr, sche
 		return nil, false, nil
 	}
 
-	// 获取列名
-	colName, ok := compExpr.Left.(*sqlparser.ColName)
+	// 获取列名（可以带 COLLATE）
+	colName, ok := unwrapCollate(compExpr.Left).(*sqlparser.ColName)
 	if !ok {
 		return nil, false, nil
 	}
@@ -400,23 +526,37 @@ func (e *Executor) tryIndexScan(tableName string, whereExpr sqlparser.Expr, sche
 	columnName := colName.Name.String()
 	operator := compExpr.Operator
 
-	// 检查该列是否有索引
-	idx := e.indexManager.GetIndexByColumn(tableName, columnName)
+	colIndex := schema.GetColumnIndex(columnName)
+	if colIndex == -1 {
+		return nil, false, fmt.Errorf("column not found: %s", columnName)
+	}
+
+	// 检查该列是否有排序规则一致的索引
+	collation, err := comparisonCollation(compExpr.Left, compExpr.Right, schema)
+	if err != nil {
+		return nil, false, err
+	}
+	idx := e.findIndex(tableName, columnName, collation)
 	if idx == nil {
 		// 没有索引
 		return nil, false, nil
 	}
 
 	// 获取比较值
-	colIndex := schema.GetColumnIndex(columnName)
-	if colIndex == -1 {
-		return nil, false, fmt.Errorf("column not found: %s", columnName)
-	}
 
-	colType := schema.Columns[colIndex].Type
-	value, err := e.evalExpr(compExpr.Right, colType)
+	value, err := e.evalColumnExpr(compExpr.Right, schema.Columns[colIndex])
 	if err != nil {
-		return nil, false, err
+		// 右值不是可以转换为列类型的常量，回退到全表扫描
+		return nil, false, nil
+	}
+	if value.IsNull() {
+		// NULL 不在索引中，与 NULL 比较也不会匹配任何行
+		return []*storage.Row{}, true, nil
+	}
+
+	// 统计信息表明条件匹配表中大部分行时使用全表扫描
+	if selectivity, ok := e.estimateSelectivity(tableName, schema.Columns[colIndex], operator, value); ok && selectivity > indexScanMaxSelectivity {
+		return nil, false, nil
 	}
 
 	// 使用索引查询
@@ -440,10 +580,27 @@ func (e *Executor) tryIndexScan(tableName string, whereExpr sqlparser.Expr, sche
 	if err != nil {
 		return nil, false, err
 	}
+	if err := e.fillVirtualColumns(schema, rows); err != nil {
+		return nil, false, err
+	}
 
 	return rows, true, nil
 }
 
+// findIndex 查找列上可用于指定排序规则比较的索引
+// 只有 TEXT 列的排序规则会影响索引顺序，其余类型的索引总是可用
+func (e *Executor) findIndex(tableName, columnName string, collation types.Collation) *index.Index {
+	for _, idx := range e.indexManager.GetIndexesByTable(tableName) {
+		if idx.ColumnName != columnName {
+			continue
+		}
+		if idx.ColumnType != types.TypeText || idx.Collation.String() == collation.String() {
+			return idx
+		}
+	}
+	return nil
+}
+
 // getRowsByIDs 根据 RowID 列表获取行数据
 func (e *Executor) getRowsByIDs(tableStorage *storage.TableStorage, rowIDs []storage.RowID) ([]*storage.Row, error) {
 	rows := make([]*storage.Row, 0, len(rowIDs))
@@ -0,0 +1,22 @@
+package executor
+
+import "testing"
+
+// TRUE / FALSE 和 BOOLEAN 列可以直接作为 WHERE 条件，其他类型报错
+func TestBooleanExpressionAsCondition(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE flags (id INT PRIMARY KEY, b BOOLEAN, n INT)",
+		"INSERT INTO flags VALUES (1, TRUE, 5), (2, FALSE, 6), (3, NULL, 7)",
+	)
+
+	expectRows(t, e, "SELECT id FROM flags WHERE TRUE", "1", "2", "3")
+	expectRows(t, e, "SELECT id FROM flags WHERE FALSE")
+	expectRows(t, e, "SELECT id FROM flags WHERE b", "1")
+	expectRows(t, e, "SELECT id FROM flags WHERE (id = 2) OR b", "1", "2")
+	expectRows(t, e, "SELECT id FROM flags WHERE CAST(n - 5 AS BOOLEAN)", "2", "3")
+	mustFail(t, e, "SELECT id FROM flags WHERE n", "argument of WHERE must be type BOOLEAN, not type INT")
+
+	mustExec(t, e, "UPDATE flags SET n = 0 WHERE b", "DELETE FROM flags WHERE FALSE")
+	expectRows(t, e, "SELECT id, n FROM flags ORDER BY id", "1\t0", "2\t6", "3\t7")
+}
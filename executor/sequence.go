@@ -0,0 +1,241 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/types"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// serialTypes SERIAL 类型对应的整数类型
+var serialTypes = map[string]string{
+	"SMALLSERIAL": "SMALLINT",
+	"SERIAL":      "INT",
+	"BIGSERIAL":   "BIGINT",
+}
+
+// executeCreateSequence 执行 CREATE SEQUENCE
+// 语法: CREATE SEQUENCE [IF NOT EXISTS] name [INCREMENT BY n] [MINVALUE n] [MAXVALUE n] [START WITH n] [CYCLE]
+func (e *Executor) executeCreateSequence(sql string) (string, error) {
+	stmt, err := parser.ParseCreateSequence(sql)
+	if err != nil {
+		return "", err
+	}
+	// 序列的 DDL 不记录在事务日志中，无法回滚
+	if e.currentTx != nil {
+		return "", fmt.Errorf("CREATE SEQUENCE cannot run inside a transaction")
+	}
+
+	name, err := e.newRelationName(stmt.Name)
+	if err != nil {
+		return "", err
+	}
+	if err := checkRelationName(name); err != nil {
+		return "", err
+	}
+	if stmt.IfNotExists {
+		if _, err := e.catalog.GetSequence(name); err == nil {
+			return fmt.Sprintf("Sequence '%s' already exists, skipped", name), nil
+		}
+	}
+
+	increment := int64(1)
+	if stmt.Increment != nil {
+		increment = *stmt.Increment
+	}
+	seq, err := catalog.NewSequence(name, increment, stmt.MinValue, stmt.MaxValue, stmt.Start, stmt.Cycle)
+	if err != nil {
+		return "", err
+	}
+
+	if err := e.catalog.CreateSequence(seq); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Sequence '%s' created successfully", name), nil
+}
+
+// executeDropSequence 执行 DROP SEQUENCE
+// 语法: DROP SEQUENCE [IF EXISTS] name
+func (e *Executor) executeDropSequence(sql string) (string, error) {
+	stmt, err := parser.ParseDropSequence(sql)
+	if err != nil {
+		return "", err
+	}
+	if e.currentTx != nil {
+		return "", fmt.Errorf("DROP SEQUENCE cannot run inside a transaction")
+	}
+
+	name, err := e.resolveRelation(stmt.Name)
+	if err != nil {
+		return "", err
+	}
+	if stmt.IfExists {
+		if _, err := e.catalog.GetSequence(name); err != nil {
+			return fmt.Sprintf("Sequence '%s' does not exist, skipped", name), nil
+		}
+	}
+
+	if err := e.catalog.DropSequence(name); err != nil {
+		return "", err
+	}
+	delete(e.sequenceValues, name)
+
+	return fmt.Sprintf("Sequence '%s' dropped successfully", name), nil
+}
+
+// columnSequenceName 返回 SERIAL / AUTO_INCREMENT 列所属的序列名
+func columnSequenceName(tableName, columnName string) string {
+	return fmt.Sprintf("%s_%s_seq", tableName, columnName)
+}
+
+// createColumnSequence 为 SERIAL / AUTO_INCREMENT 列创建所属的序列，删除表时序列一起删除
+func (e *Executor) createColumnSequence(tableName string, column catalog.Column) error {
+	_, maxValue := column.IntRange()
+	seq, err := catalog.NewSequence(columnSequenceName(tableName, column.Name), 1, nil, &maxValue, nil, false)
+	if err != nil {
+		return err
+	}
+	seq.OwnedBy = tableName + "." + column.Name
+	return e.catalog.CreateSequence(seq)
+}
+
+// advanceAutoIncrement 插入的行中自增列的最大值超过所属序列的当前值时推进序列，之后自动生成的值不会与显式插入的值重复
+func (e *Executor) advanceAutoIncrement(schema *catalog.TableSchema, rows []*storage.Row) error {
+	for i, column := range schema.Columns {
+		if !e.catalog.IsAutoIncrement(schema, column) {
+			continue
+		}
+		maxValue, found := int64(0), false
+		for _, row := range rows {
+			if row.Values[i].IsNull() {
+				continue
+			}
+			if value, err := row.Values[i].AsInt(); err == nil && (!found || value > maxValue) {
+				maxValue, found = value, true
+			}
+		}
+		if !found {
+			continue
+		}
+		if err := e.catalog.AdvanceAutoIncrement(schema, column, maxValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evalSequenceFunc 计算序列函数 nextval、currval 和 setval
+func (e *Executor) evalSequenceFunc(row *storage.Row, expr *sqlparser.FuncExpr, schema *catalog.TableSchema) (types.Value, error) {
+	funcName := expr.Name.Lowered()
+
+	args := make([]types.Value, len(expr.Exprs))
+	for i, arg := range expr.Exprs {
+		aliased, ok := arg.(*sqlparser.AliasedExpr)
+		if !ok {
+			return types.Value{}, fmt.Errorf("invalid argument to %s()", funcName)
+		}
+		value, err := e.evalRowExpr(row, aliased.Expr, schema)
+		if err != nil {
+			return types.Value{}, err
+		}
+		args[i] = value
+	}
+
+	minArgs, maxArgs := 1, 1
+	if funcName == "setval" {
+		minArgs, maxArgs = 2, 3
+	}
+	if len(args) < minArgs || len(args) > maxArgs {
+		return types.Value{}, fmt.Errorf("wrong number of arguments to %s()", funcName)
+	}
+	if args[0].IsNull() {
+		return types.NewNullValue(), nil
+	}
+	seqName, err := args[0].AsText()
+	if err != nil {
+		return types.Value{}, fmt.Errorf("%s(): sequence name must be text", funcName)
+	}
+	if seqName, err = e.resolveRelation(seqName); err != nil {
+		return types.Value{}, err
+	}
+
+	switch funcName {
+	case "nextval":
+		value, err := e.catalog.NextValue(seqName)
+		if err != nil {
+			return types.Value{}, err
+		}
+		if e.sequenceValues == nil {
+			e.sequenceValues = make(map[string]int64)
+		}
+		e.sequenceValues[seqName] = value
+		return types.NewIntValue(value), nil
+
+	case "currval":
+		if _, err := e.catalog.GetSequence(seqName); err != nil {
+			return types.Value{}, err
+		}
+		value, ok := e.sequenceValues[seqName]
+		if !ok {
+			return types.Value{}, fmt.Errorf("currval of sequence %s is not yet defined in this session", seqName)
+		}
+		return types.NewIntValue(value), nil
+
+	default: // setval
+		value, err := types.ImplicitCast(args[1], types.TypeInt)
+		if err != nil {
+			return types.Value{}, err
+		}
+		if value.IsNull() {
+			return types.NewNullValue(), nil
+		}
+		isCalled := true
+		if len(args) == 3 {
+			flag, err := types.ImplicitCast(args[2], types.TypeBoolean)
+			if err != nil {
+				return types.Value{}, err
+			}
+			if !flag.IsNull() {
+				isCalled, _ = flag.AsBoolean()
+			}
+		}
+		intVal, _ := value.AsInt()
+		if err := e.catalog.SetValue(seqName, intVal, isCalled); err != nil {
+			return types.Value{}, err
+		}
+		return value, nil
+	}
+}
+
+// hasSequenceCall 判断表达式中是否调用了会修改序列的函数（这样的默认值不能在建表时试算）
+func hasSequenceCall(expr sqlparser.Expr) bool {
+	found := false
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if funcExpr, ok := node.(*sqlparser.FuncExpr); ok {
+			switch funcExpr.Name.Lowered() {
+			case "nextval", "setval":
+				found = true
+				return false, nil
+			}
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
+// isCreateSequence 检查是否是 CREATE SEQUENCE 语句
+func isCreateSequence(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "CREATE SEQUENCE")
+}
+
+// isDropSequence 检查是否是 DROP SEQUENCE 语句
+func isDropSequence(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "DROP SEQUENCE")
+}
@@ -0,0 +1,209 @@
+package executor
+
+import (
+	"fmt"
+	"godb/types"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// executeDescribe 执行 DESCRIBE
+// 语法: DESCRIBE table_name / DESC table_name
+func (e *Executor) executeDescribe(sql string) (string, error) {
+	pattern := `(?i)^\s*DESC(?:RIBE)?\s+(\w+(?:\.\w+)?)\s*;?\s*$`
+	re := regexp.MustCompile(pattern)
+	matches := re.FindStringSubmatch(sql)
+
+	if len(matches) != 2 {
+		return "", fmt.Errorf("invalid DESCRIBE syntax, expected: DESCRIBE table_name")
+	}
+
+	tableName, err := e.resolveRelation(matches[1])
+	if err != nil {
+		return "", err
+	}
+
+	// 视图和系统表的列取自查询结果
+	schema, _, err := e.loadRelation(tableName)
+	if err != nil {
+		return "", err
+	}
+
+	uniqueColumns := make(map[string]bool)
+	for _, info := range e.catalog.GetIndexesByTable(schema.Name) {
+		if info.Unique {
+			uniqueColumns[info.ColumnName] = true
+		}
+	}
+
+	rows := make([][]string, 0, len(schema.Columns))
+	for _, col := range schema.Columns {
+		collation := ""
+		if col.Type == types.TypeText {
+			collation = col.Collation.String()
+		}
+		key := ""
+		if col.Name == schema.PrimaryKey {
+			key = "PRI"
+		} else if uniqueColumns[col.Name] {
+			key = "UNI"
+		}
+		null := "YES"
+		if col.NotNull {
+			null = "NO"
+		}
+		defaultValue := "NULL"
+		if col.Default != "" {
+			defaultValue = col.Default
+		}
+		if col.Generated != "" {
+			defaultValue = col.GeneratedClause()
+		}
+		rows = append(rows, []string{col.Name, col.DeclaredType(), collation, null, key, defaultValue})
+	}
+
+	return formatRows([]string{"Field", "Type", "Collation", "Null", "Key", "Default"}, rows), nil
+}
+
+// executeShow 执行 SHOW 语句
+// 语法: SHOW TABLES
+//
+//	SHOW {INDEX | INDEXES | KEYS} {FROM | IN} table_name
+//	SHOW CREATE {TABLE | VIEW | MATERIALIZED VIEW} name
+//	SHOW search_path
+func (e *Executor) executeShow(sql string) (string, error) {
+	if regexp.MustCompile(`(?i)^\s*SHOW\s+TABLES\s*;?\s*$`).MatchString(sql) {
+		return e.showTables(), nil
+	}
+	if regexp.MustCompile(`(?i)^\s*SHOW\s+search_path\s*;?\s*$`).MatchString(sql) {
+		return e.showSearchPath(), nil
+	}
+
+	if matches := regexp.MustCompile(`(?i)^\s*SHOW\s+(?:INDEX|INDEXES|KEYS)\s+(?:FROM|IN)\s+(\w+(?:\.\w+)?)\s*;?\s*$`).FindStringSubmatch(sql); matches != nil {
+		tableName, err := e.resolveRelation(matches[1])
+		if err != nil {
+			return "", err
+		}
+		return e.showIndexes(tableName)
+	}
+
+	if matches := regexp.MustCompile(`(?i)^\s*SHOW\s+CREATE\s+(TABLE|VIEW|MATERIALIZED\s+VIEW)\s+(\w+(?:\.\w+)?)\s*;?\s*$`).FindStringSubmatch(sql); matches != nil {
+		name, err := e.resolveRelation(matches[2])
+		if err != nil {
+			return "", err
+		}
+		return e.showCreate(strings.Join(strings.Fields(strings.ToUpper(matches[1])), " "), name)
+	}
+
+	return "", fmt.Errorf("invalid SHOW syntax, expected: SHOW TABLES, SHOW INDEXES FROM table_name, SHOW CREATE TABLE table_name or SHOW search_path")
+}
+
+// showTables 列出所有表、视图和物化视图
+func (e *Executor) showTables() string {
+	rows := make([][]string, 0)
+	for _, name := range sortedTables(e.catalog) {
+		tableType := "TABLE"
+		if schema, err := e.catalog.GetTable(name); err == nil && schema.View != nil {
+			tableType = "MATERIALIZED VIEW"
+		}
+		rows = append(rows, []string{name, tableType})
+	}
+	for _, name := range e.catalog.ListViews() {
+		rows = append(rows, []string{name, "VIEW"})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+	return formatRows([]string{"Table", "Type"}, rows)
+}
+
+// showIndexes 列出表的索引
+func (e *Executor) showIndexes(tableName string) (string, error) {
+	schema, err := e.catalog.GetTable(tableName)
+	if err != nil {
+		return "", err
+	}
+
+	indexes := e.catalog.GetIndexesByTable(tableName)
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+
+	rows := make([][]string, 0, len(indexes))
+	for _, info := range indexes {
+		collation := ""
+		if info.ColumnType == types.TypeText {
+			collation = info.Collation.String()
+		}
+		key := ""
+		if info.Name == schema.PrimaryKeyIndex {
+			key = "PRI"
+		} else if info.Unique {
+			key = "UNI"
+		}
+		entries := ""
+		if idx, err := e.indexManager.GetIndex(info.Name); err == nil {
+			if count, err := idx.GetCount(); err == nil {
+				entries = fmt.Sprintf("%d", count)
+			}
+		}
+		rows = append(rows, []string{info.Name, info.ColumnName, collation, fmt.Sprintf("%t", info.Unique), key, entries})
+	}
+
+	return formatRows([]string{"Index", "Column", "Collation", "Unique", "Key", "Entries"}, rows), nil
+}
+
+// showCreate 输出重新生成的创建语句，kind 为 SHOW CREATE 之后声明的对象类型
+func (e *Executor) showCreate(kind, name string) (string, error) {
+	switch kind {
+	case "VIEW":
+		if _, err := e.catalog.GetView(name); err != nil {
+			return "", err
+		}
+	case "MATERIALIZED VIEW":
+		if _, err := e.getMaterializedView(name); err != nil {
+			return "", err
+		}
+	default:
+		if _, err := e.catalog.GetTable(name); err != nil {
+			if _, viewErr := e.catalog.GetView(name); viewErr != nil {
+				return "", err
+			}
+		}
+	}
+
+	ddl, err := e.catalog.ShowCreate(name)
+	if err != nil {
+		return "", err
+	}
+	return formatRows([]string{"Table", "Create Table"}, [][]string{{name, ddl}}), nil
+}
+
+// formatRows 按查询结果的格式输出表头和数据行
+func formatRows(headers []string, rows [][]string) string {
+	var result strings.Builder
+
+	result.WriteString(strings.Join(headers, "\t"))
+	result.WriteString("\n")
+	result.WriteString(strings.Repeat("-", len(headers)*15))
+	result.WriteString("\n")
+
+	for _, row := range rows {
+		result.WriteString(strings.Join(row, "\t"))
+		result.WriteString("\n")
+	}
+
+	result.WriteString(fmt.Sprintf("\n%d row(s) returned", len(rows)))
+
+	return result.String()
+}
+
+// isShow 检查是否是 SHOW 语句
+func isShow(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "SHOW ")
+}
+
+// isDescribe 检查是否是 DESCRIBE 语句
+func isDescribe(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "DESCRIBE ") || strings.HasPrefix(sql, "DESC ")
+}
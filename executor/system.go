@@ -0,0 +1,387 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/storage"
+	"godb/types"
+	"sort"
+	"strings"
+)
+
+// 只读的系统表，查询时根据元数据、索引管理器、事务管理器和锁管理器的当前状态生成
+const (
+	sysTables       = "godb_tables"
+	sysColumns      = "godb_columns"
+	sysIndexes      = "godb_indexes"
+	sysTransactions = "godb_transactions"
+	sysLocks        = "godb_locks"
+	sysStats        = "godb_stats"
+	sysPartitions   = "godb_partitions"
+)
+
+// isSystemTable 判断是否是系统表名
+func isSystemTable(name string) bool {
+	switch name {
+	case sysTables, sysColumns, sysIndexes, sysTransactions, sysLocks, sysStats, sysPartitions:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkRelationName 检查新建或重命名的表、视图和序列没有使用系统表名
+func checkRelationName(name string) error {
+	if isSystemTable(name) {
+		return fmt.Errorf("%s is a system table name and cannot be used", name)
+	}
+	return nil
+}
+
+// systemTableRows 生成系统表的表定义和行
+func (e *Executor) systemTableRows(name string) (*catalog.TableSchema, []*storage.Row, error) {
+	switch name {
+	case sysTables:
+		return e.systemTables()
+	case sysColumns:
+		return e.systemColumns()
+	case sysIndexes:
+		return e.systemIndexes()
+	case sysTransactions:
+		return e.systemTransactions()
+	case sysLocks:
+		return e.systemLocks()
+	case sysStats:
+		return e.systemStats()
+	case sysPartitions:
+		return e.systemPartitions()
+	default:
+		return nil, nil, fmt.Errorf("table not found: %s", name)
+	}
+}
+
+// systemSchema 创建系统表的表定义
+func systemSchema(name string, columns ...catalog.Column) *catalog.TableSchema {
+	return &catalog.TableSchema{Name: name, Columns: columns}
+}
+
+// sysColumn 创建系统表的列
+func sysColumn(name string, dataType types.DataType) catalog.Column {
+	return catalog.Column{Name: name, Type: dataType, TypeName: dataType.String()}
+}
+
+// textOrNull 空字符串转换为 NULL
+func textOrNull(s string) types.Value {
+	if s == "" {
+		return types.NewNullValue()
+	}
+	return types.NewTextValue(s)
+}
+
+// systemTables 生成 godb_tables：所有表、视图和物化视图
+func (e *Executor) systemTables() (*catalog.TableSchema, []*storage.Row, error) {
+	schema := systemSchema(sysTables,
+		sysColumn("table_name", types.TypeText),
+		sysColumn("table_type", types.TypeText),
+		sysColumn("column_count", types.TypeInt),
+		sysColumn("row_count", types.TypeInt),
+		sysColumn("primary_key", types.TypeText),
+		sysColumn("first_page_id", types.TypeInt),
+		sysColumn("definition", types.TypeText),
+	)
+
+	rows := make([]*storage.Row, 0)
+	for _, name := range sortedTables(e.catalog) {
+		table, err := e.catalog.GetTable(name)
+		if err != nil {
+			continue
+		}
+		tableStorage, err := catalog.CreateTableStorage(e.pagerFor(table), table)
+		if err != nil {
+			return nil, nil, err
+		}
+		tableRows, err := tableStorage.GetAllRows()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tableType, definition := "TABLE", ""
+		if table.View != nil {
+			tableType, definition = "MATERIALIZED VIEW", table.View.Query
+		} else if table.Temporary {
+			tableType = "TEMPORARY TABLE"
+		}
+		rows = append(rows, &storage.Row{Values: []types.Value{
+			types.NewTextValue(table.Name),
+			types.NewTextValue(tableType),
+			types.NewIntValue(int64(len(table.Columns))),
+			types.NewIntValue(int64(len(e.filterVisibleRows(tableRows)))),
+			textOrNull(table.PrimaryKey),
+			types.NewIntValue(int64(table.FirstPageID)),
+			textOrNull(definition),
+		}})
+	}
+
+	// 视图没有存储，列数和行数为 NULL
+	for _, name := range e.catalog.ListViews() {
+		view, err := e.catalog.GetView(name)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, &storage.Row{Values: []types.Value{
+			types.NewTextValue(view.Name),
+			types.NewTextValue("VIEW"),
+			types.NewNullValue(),
+			types.NewNullValue(),
+			types.NewNullValue(),
+			types.NewNullValue(),
+			types.NewTextValue(view.Query),
+		}})
+	}
+
+	return schema, rows, nil
+}
+
+// systemColumns 生成 godb_columns：表和物化视图的列（视图的列在查询时才能确定，不列出）
+func (e *Executor) systemColumns() (*catalog.TableSchema, []*storage.Row, error) {
+	schema := systemSchema(sysColumns,
+		sysColumn("table_name", types.TypeText),
+		sysColumn("column_name", types.TypeText),
+		sysColumn("ordinal_position", types.TypeInt),
+		sysColumn("data_type", types.TypeText),
+		sysColumn("collation", types.TypeText),
+		sysColumn("is_nullable", types.TypeBoolean),
+		sysColumn("column_default", types.TypeText),
+		sysColumn("is_primary_key", types.TypeBoolean),
+	)
+
+	rows := make([]*storage.Row, 0)
+	for _, name := range sortedTables(e.catalog) {
+		table, err := e.catalog.GetTable(name)
+		if err != nil {
+			continue
+		}
+		for i, column := range table.Columns {
+			collation := types.NewNullValue()
+			if column.Type == types.TypeText {
+				collation = types.NewTextValue(column.Collation.String())
+			}
+			rows = append(rows, &storage.Row{Values: []types.Value{
+				types.NewTextValue(table.Name),
+				types.NewTextValue(column.Name),
+				types.NewIntValue(int64(i + 1)),
+				types.NewTextValue(column.DeclaredType()),
+				collation,
+				types.NewBooleanValue(!column.NotNull),
+				textOrNull(column.Default),
+				types.NewBooleanValue(column.Name == table.PrimaryKey),
+			}})
+		}
+	}
+
+	return schema, rows, nil
+}
+
+// systemIndexes 生成 godb_indexes：所有索引及其 B+ 树的条目数、页数和根节点页 ID
+func (e *Executor) systemIndexes() (*catalog.TableSchema, []*storage.Row, error) {
+	schema := systemSchema(sysIndexes,
+		sysColumn("index_name", types.TypeText),
+		sysColumn("table_name", types.TypeText),
+		sysColumn("column_name", types.TypeText),
+		sysColumn("data_type", types.TypeText),
+		sysColumn("collation", types.TypeText),
+		sysColumn("is_unique", types.TypeBoolean),
+		sysColumn("is_primary", types.TypeBoolean),
+		sysColumn("entries", types.TypeInt),
+		sysColumn("page_count", types.TypeInt),
+		sysColumn("root_page_id", types.TypeInt),
+	)
+
+	names := e.catalog.ListIndexes()
+	sort.Strings(names)
+
+	rows := make([]*storage.Row, 0, len(names))
+	for _, name := range names {
+		info, err := e.catalog.GetIndex(name)
+		if err != nil {
+			continue
+		}
+		isPrimary := false
+		if table, err := e.catalog.GetTable(info.TableName); err == nil {
+			isPrimary = table.PrimaryKeyIndex == info.Name
+		}
+		collation := types.NewNullValue()
+		if info.ColumnType == types.TypeText {
+			collation = types.NewTextValue(info.Collation.String())
+		}
+		entries, pageCount, rootPageID := types.NewNullValue(), types.NewNullValue(), types.NewNullValue()
+		if idx, err := e.indexManager.GetIndex(name); err == nil {
+			if count, err := idx.GetCount(); err == nil {
+				entries = types.NewIntValue(int64(count))
+			}
+			if pageIDs, err := idx.PageIDs(); err == nil {
+				pageCount = types.NewIntValue(int64(len(pageIDs)))
+			}
+			rootPageID = types.NewIntValue(int64(idx.RootPageID()))
+		}
+		rows = append(rows, &storage.Row{Values: []types.Value{
+			types.NewTextValue(info.Name),
+			types.NewTextValue(info.TableName),
+			types.NewTextValue(info.ColumnName),
+			types.NewTextValue(info.ColumnType.String()),
+			collation,
+			types.NewBooleanValue(info.Unique),
+			types.NewBooleanValue(isPrimary),
+			entries,
+			pageCount,
+			rootPageID,
+		}})
+	}
+
+	return schema, rows, nil
+}
+
+// systemTransactions 生成 godb_transactions：活跃的事务
+func (e *Executor) systemTransactions() (*catalog.TableSchema, []*storage.Row, error) {
+	schema := systemSchema(sysTransactions,
+		sysColumn("tx_id", types.TypeInt),
+		sysColumn("status", types.TypeText),
+		sysColumn("start_time", types.TypeText),
+		sysColumn("operations", types.TypeInt),
+		sysColumn("is_current", types.TypeBoolean),
+	)
+
+	txIDs := e.txManager.GetActiveTransactions()
+	sort.Slice(txIDs, func(i, j int) bool { return txIDs[i] < txIDs[j] })
+
+	rows := make([]*storage.Row, 0, len(txIDs))
+	for _, txID := range txIDs {
+		tx, err := e.txManager.GetTransaction(txID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, &storage.Row{Values: []types.Value{
+			types.NewIntValue(int64(tx.ID)),
+			types.NewTextValue(tx.Status.String()),
+			types.NewTextValue(tx.StartTime.Format("2006-01-02 15:04:05")),
+			types.NewIntValue(int64(len(tx.GetOperations()))),
+			types.NewBooleanValue(e.currentTx != nil && e.currentTx.ID == tx.ID),
+		}})
+	}
+
+	return schema, rows, nil
+}
+
+// systemLocks 生成 godb_locks：事务持有的表锁（tx_id 为 0 表示自动提交的语句）
+func (e *Executor) systemLocks() (*catalog.TableSchema, []*storage.Row, error) {
+	schema := systemSchema(sysLocks,
+		sysColumn("table_name", types.TypeText),
+		sysColumn("tx_id", types.TypeInt),
+		sysColumn("lock_type", types.TypeText),
+	)
+
+	locks := e.txManager.GetLockManager().ListLocks()
+	rows := make([]*storage.Row, 0, len(locks))
+	for _, lock := range locks {
+		rows = append(rows, &storage.Row{Values: []types.Value{
+			types.NewTextValue(lock.Table),
+			types.NewIntValue(int64(lock.TxID)),
+			types.NewTextValue(lock.Type.String()),
+		}})
+	}
+
+	return schema, rows, nil
+}
+
+// systemStats 生成 godb_stats：ANALYZE 收集的各列统计信息
+func (e *Executor) systemStats() (*catalog.TableSchema, []*storage.Row, error) {
+	schema := systemSchema(sysStats,
+		sysColumn("table_name", types.TypeText),
+		sysColumn("column_name", types.TypeText),
+		sysColumn("row_count", types.TypeInt),
+		sysColumn("sample_size", types.TypeInt),
+		sysColumn("null_frac", types.TypeFloat),
+		sysColumn("n_distinct", types.TypeInt),
+		sysColumn("histogram_bounds", types.TypeText),
+		sysColumn("analyzed_at", types.TypeText),
+	)
+
+	rows := make([]*storage.Row, 0)
+	for _, stats := range e.catalog.ListTableStats() {
+		for _, column := range stats.Columns {
+			histogram := types.NewNullValue()
+			if len(column.Histogram) > 0 {
+				histogram = types.NewTextValue("{" + strings.Join(column.Histogram, ", ") + "}")
+			}
+			rows = append(rows, &storage.Row{Values: []types.Value{
+				types.NewTextValue(stats.Table),
+				types.NewTextValue(column.Column),
+				types.NewIntValue(stats.RowCount),
+				types.NewIntValue(stats.SampleSize),
+				types.NewFloatValue(column.NullFrac),
+				types.NewIntValue(column.Distinct),
+				histogram,
+				types.NewTextValue(stats.AnalyzedAt.Format("2006-01-02 15:04:05")),
+			}})
+		}
+	}
+
+	return schema, rows, nil
+}
+
+// systemPartitions 生成 godb_partitions：分区表的各个分区
+func (e *Executor) systemPartitions() (*catalog.TableSchema, []*storage.Row, error) {
+	schema := systemSchema(sysPartitions,
+		sysColumn("table_name", types.TypeText),
+		sysColumn("partition_name", types.TypeText),
+		sysColumn("partition_method", types.TypeText),
+		sysColumn("partition_key", types.TypeText),
+		sysColumn("partition_bound", types.TypeText),
+		sysColumn("row_count", types.TypeInt),
+		sysColumn("page_count", types.TypeInt),
+		sysColumn("first_page_id", types.TypeInt),
+	)
+
+	rows := make([]*storage.Row, 0)
+	for _, name := range sortedTables(e.catalog) {
+		table, err := e.catalog.GetTable(name)
+		if err != nil || table.Partitioning == nil {
+			continue
+		}
+		tableStorage, err := catalog.CreateTableStorage(e.pagerFor(table), table)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, column := partitionKey(table)
+		for i, partition := range table.Partitioning.Partitions {
+			partitionStorage := tableStorage.Prune([]int{i})
+			partitionRows, err := partitionStorage.GetAllRows()
+			if err != nil {
+				return nil, nil, err
+			}
+			pageIDs, err := partitionStorage.GetPageIDs()
+			if err != nil {
+				return nil, nil, err
+			}
+			rows = append(rows, &storage.Row{Values: []types.Value{
+				types.NewTextValue(table.Name),
+				types.NewTextValue(partition.Name),
+				types.NewTextValue(table.Partitioning.Method),
+				types.NewTextValue(table.Partitioning.Column),
+				textOrNull(table.Partitioning.Bound(partition, column)),
+				types.NewIntValue(int64(len(e.filterVisibleRows(partitionRows)))),
+				types.NewIntValue(int64(len(pageIDs))),
+				types.NewIntValue(int64(partition.FirstPageID)),
+			}})
+		}
+	}
+
+	return schema, rows, nil
+}
+
+// sortedTables 返回按名字排序的表名
+func sortedTables(c *catalog.Catalog) []string {
+	names := c.ListTables()
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/storage"
+	"os"
+)
+
+// pagerFor 返回表的行所在的页管理器：临时表的行保存在本会话私有的临时文件中
+func (e *Executor) pagerFor(schema *catalog.TableSchema) *storage.Pager {
+	if schema.Temporary && e.tempPager != nil {
+		return e.tempPager
+	}
+	return e.pager
+}
+
+// ensureTempPager 第一次创建临时表时在系统临时目录中创建临时表使用的文件
+func (e *Executor) ensureTempPager() error {
+	if e.tempPager != nil {
+		return nil
+	}
+	file, err := os.CreateTemp("", "godb_temp_*.db")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	name := file.Name()
+	file.Close()
+
+	pager, err := storage.NewPager(name)
+	if err != nil {
+		os.Remove(name)
+		return err
+	}
+	e.tempPager = pager
+	e.txManager.SetTempPager(pager)
+	return nil
+}
+
+// applyOnCommit 事务提交后执行临时表的 ON COMMIT 动作：DELETE ROWS 清空表，DROP 删除表
+func (e *Executor) applyOnCommit() error {
+	for _, tableName := range e.catalog.ListTemporaryTables() {
+		schema, err := e.catalog.GetTable(tableName)
+		if err != nil {
+			continue
+		}
+		switch schema.OnCommit {
+		case "DELETE ROWS":
+			err = e.replaceTableRows(schema, schema.Clone(), e.catalog.GetIndexesByTable(tableName), nil)
+		case "DROP":
+			err = e.dropTable(tableName)
+		}
+		if err != nil {
+			return fmt.Errorf("ON COMMIT action of temporary table %s failed: %w", tableName, err)
+		}
+	}
+	return nil
+}
+
+// Close 结束会话：回滚未提交的事务，删除所有临时表和临时表使用的文件，关闭附加的数据库
+func (e *Executor) Close() error {
+	if e.currentTx != nil {
+		if _, err := e.executeRollback(); err != nil {
+			return err
+		}
+	}
+	for _, tableName := range e.catalog.ListTemporaryTables() {
+		if err := e.dropTable(tableName); err != nil {
+			return err
+		}
+	}
+
+	// 关闭附加的数据库文件
+	for alias, db := range e.attached {
+		delete(e.attached, alias)
+		if err := db.pager.Close(); err != nil {
+			return fmt.Errorf("failed to close database %s: %w", alias, err)
+		}
+	}
+
+	if e.tempPager == nil {
+		return nil
+	}
+	name := e.tempPager.FileName()
+	e.txManager.SetTempPager(nil)
+	err := e.tempPager.Close()
+	e.tempPager = nil
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+	return err
+}
@@ -2,6 +2,9 @@ package executor
 
 import (
 	"fmt"
+	"godb/catalog"
+	"godb/index"
+	"godb/transaction"
 	"strings"
 )
 
@@ -17,6 +20,9 @@ func (e *Executor) executeBegin() (string, error) {
 	}
 
 	e.currentTx = tx
+	e.resetConstraintModes()
+	// 事务中的 DDL 在提交时才写入元数据文件
+	e.catalog.DeferSave()
 	return fmt.Sprintf("Transaction %d started", tx.ID), nil
 }
 
@@ -27,11 +33,29 @@ func (e *Executor) executeCommit() (string, error) {
 	}
 
 	txID := e.currentTx.ID
+
+	// 检查延迟到提交时的外键，不满足时回滚整个事务
+	if err := e.checkDeferredForeignKeys(nil); err != nil {
+		if _, rollbackErr := e.executeRollback(); rollbackErr != nil {
+			return "", rollbackErr
+		}
+		return "", fmt.Errorf("transaction %d rolled back: %w", txID, err)
+	}
+
 	if err := e.txManager.Commit(txID); err != nil {
 		return "", err
 	}
 
 	e.currentTx = nil
+	e.resetConstraintModes()
+	if err := e.catalog.EndDeferSave(); err != nil {
+		return "", err
+	}
+
+	// 临时表的 ON COMMIT 动作在事务提交后以自动提交方式执行
+	if err := e.applyOnCommit(); err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("Transaction %d committed", txID), nil
 }
 
@@ -42,14 +66,146 @@ func (e *Executor) executeRollback() (string, error) {
 	}
 
 	txID := e.currentTx.ID
-	if err := e.txManager.Rollback(txID); err != nil {
+
+	// 事务管理器逆序回滚表数据和 DDL，索引条目由执行器在每个数据操作回滚之后撤销
+	if err := e.txManager.Rollback(txID, e.rollbackIndexEntries); err != nil {
 		return "", err
 	}
 
 	e.currentTx = nil
+	e.resetConstraintModes()
+	if err := e.catalog.EndDeferSave(); err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("Transaction %d rolled back", txID), nil
 }
 
+// rollbackIndexEntries 撤销一个数据操作对索引的修改
+func (e *Executor) rollbackIndexEntries(op *transaction.Operation) {
+	schema, err := e.catalog.GetTable(op.TableName)
+	if err != nil {
+		// 表已被删除，索引也随之删除
+		return
+	}
+	columnNames := make([]string, len(schema.Columns))
+	for j, col := range schema.Columns {
+		columnNames[j] = col.Name
+	}
+
+	switch op.Type {
+	case transaction.OpInsert:
+		e.indexManager.DeleteEntry(op.TableName, op.NewData, columnNames)
+	case transaction.OpUpdate:
+		e.indexManager.DeleteEntry(op.TableName, op.NewData, columnNames)
+		e.indexManager.InsertEntry(op.TableName, op.OldData, columnNames)
+	case transaction.OpDelete:
+		e.indexManager.InsertEntry(op.TableName, op.OldData, columnNames)
+	}
+}
+
+// lockDDL 事务中的 DDL 对表加写锁并保持到事务结束，提交之前其他事务不能访问该表
+func (e *Executor) lockDDL(tableName string) error {
+	if e.currentTx == nil {
+		return nil
+	}
+	if err := e.txManager.GetLockManager().AcquireWriteLock(tableName, e.currentTx.ID); err != nil {
+		return fmt.Errorf("failed to acquire write lock: %w", err)
+	}
+	return nil
+}
+
+// logCreateTable 记录事务中创建的表，回滚时删除表和索引并释放表和索引的页
+func (e *Executor) logCreateTable(tableName string) {
+	if e.currentTx == nil {
+		return
+	}
+	e.currentTx.AddOperation(&transaction.Operation{
+		Type:      transaction.OpCreateTable,
+		TableName: tableName,
+		Undo: func() error {
+			schema, err := e.catalog.GetTable(tableName)
+			if err != nil {
+				return err
+			}
+			tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+			if err != nil {
+				return err
+			}
+			pageIDs, err := tableStorage.GetPageIDs()
+			if err != nil {
+				return err
+			}
+			if err := e.catalog.DropTable(tableName); err != nil {
+				return err
+			}
+			freeIndexes(e.indexManager.DropIndexesByTable(tableName))
+			e.pagerFor(schema).FreePages(pageIDs)
+			return nil
+		},
+	})
+}
+
+// logDropTable 记录事务中删除的表，回滚时恢复元数据和内存中的索引（表的页在删除时不释放，数据保持不变）
+func (e *Executor) logDropTable(def *catalog.TableDefinition, indexes []*index.Index) {
+	if e.currentTx == nil {
+		return
+	}
+	e.currentTx.AddOperation(&transaction.Operation{
+		Type:      transaction.OpDropTable,
+		TableName: def.Schema.Name,
+		Undo: func() error {
+			if err := e.catalog.RestoreTable(def); err != nil {
+				return err
+			}
+			e.indexManager.RestoreIndexes(indexes)
+			return nil
+		},
+	})
+}
+
+// logCreateIndex 记录事务中创建的索引，回滚时删除并释放索引的页
+func (e *Executor) logCreateIndex(tableName, indexName string) {
+	if e.currentTx == nil {
+		return
+	}
+	e.currentTx.AddOperation(&transaction.Operation{
+		Type:      transaction.OpCreateIndex,
+		TableName: tableName,
+		Undo: func() error {
+			idx, err := e.indexManager.GetIndex(indexName)
+			if err != nil {
+				return err
+			}
+			if err := e.catalog.DropIndex(indexName); err != nil {
+				return err
+			}
+			if err := e.indexManager.DropIndex(indexName); err != nil {
+				return err
+			}
+			idx.Free()
+			return nil
+		},
+	})
+}
+
+// logDropIndex 记录事务中删除的索引，回滚时恢复元数据和内存中的索引
+func (e *Executor) logDropIndex(info *catalog.IndexInfo, idx *index.Index) {
+	if e.currentTx == nil {
+		return
+	}
+	e.currentTx.AddOperation(&transaction.Operation{
+		Type:      transaction.OpDropIndex,
+		TableName: info.TableName,
+		Undo: func() error {
+			if err := e.catalog.RestoreIndex(info); err != nil {
+				return err
+			}
+			e.indexManager.RestoreIndexes([]*index.Index{idx})
+			return nil
+		},
+	})
+}
+
 // isTransactionCommand 检查是否是事务命令
 func isTransactionCommand(sql string) bool {
 	sqlUpper := strings.ToUpper(strings.TrimSpace(sql))
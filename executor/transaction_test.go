@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"godb/catalog"
+	"path/filepath"
+	"testing"
+)
+
+// 不能回滚的视图、枚举类型和序列 DDL 不能在事务中执行
+func TestNonTransactionalDDLRejectedInTransaction(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE items (id INT PRIMARY KEY)",
+		"CREATE VIEW item_ids AS SELECT id FROM items",
+		"CREATE TYPE mood AS ENUM ('ok')",
+		"CREATE SEQUENCE counter",
+		"BEGIN",
+	)
+
+	mustFail(t, e, "CREATE VIEW v AS SELECT id FROM items", "CREATE VIEW cannot run inside a transaction")
+	mustFail(t, e, "DROP VIEW item_ids", "DROP VIEW cannot run inside a transaction")
+	mustFail(t, e, "CREATE TYPE e1 AS ENUM ('a')", "CREATE TYPE cannot run inside a transaction")
+	mustFail(t, e, "ALTER TYPE mood ADD VALUE 'bad'", "ALTER TYPE cannot run inside a transaction")
+	mustFail(t, e, "DROP TYPE mood", "DROP TYPE cannot run inside a transaction")
+	mustFail(t, e, "CREATE SEQUENCE s1", "CREATE SEQUENCE cannot run inside a transaction")
+	mustFail(t, e, "DROP SEQUENCE counter", "DROP SEQUENCE cannot run inside a transaction")
+
+	// 物化视图与表一样可以回滚
+	mustExec(t, e,
+		"CREATE MATERIALIZED VIEW item_copy AS SELECT id FROM items",
+		"ROLLBACK",
+	)
+	mustFail(t, e, "SELECT * FROM item_copy", "not found")
+	expectRows(t, e, "SELECT table_name FROM godb_tables ORDER BY table_name", "item_ids", "items")
+}
+
+// 事务中的 DDL 在提交之前不写入元数据文件
+func TestTransactionDDLSavedOnCommit(t *testing.T) {
+	e, dbFile := newTestExecutor(t)
+	metaFile := filepath.Join(filepath.Dir(dbFile), "test_meta.json")
+	mustExec(t, e,
+		"CREATE TABLE keep (id INT PRIMARY KEY)",
+		"INSERT INTO keep VALUES (1)",
+		"CREATE SEQUENCE counter",
+		"BEGIN",
+		"DROP TABLE keep",
+		"CREATE TABLE added (id INT)",
+		"SELECT nextval('counter')",
+	)
+
+	// 模拟进程在提交之前退出：重新读取元数据文件
+	saved, err := catalog.NewCatalog(metaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saved.GetTable("keep"); err != nil {
+		t.Fatalf("uncommitted DROP TABLE was saved: %v", err)
+	}
+	if _, err := saved.GetTable("added"); err == nil {
+		t.Fatal("uncommitted CREATE TABLE was saved")
+	}
+	// 序列不受事务影响，预留的值立即写入
+	if seq, err := saved.GetSequence("counter"); err != nil || !seq.IsCalled {
+		t.Fatalf("sequence reservation was not saved: %v", err)
+	}
+
+	mustExec(t, e, "COMMIT")
+	if saved, err = catalog.NewCatalog(metaFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saved.GetTable("keep"); err == nil {
+		t.Fatal("committed DROP TABLE was not saved")
+	}
+	if _, err := saved.GetTable("added"); err != nil {
+		t.Fatalf("committed CREATE TABLE was not saved: %v", err)
+	}
+}
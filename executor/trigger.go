@@ -0,0 +1,279 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/types"
+	"strconv"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// maxTriggerDepth 触发器嵌套的最大深度（触发器中的语句可能再次触发触发器）
+const maxTriggerDepth = 16
+
+// executeCreateTrigger 执行 CREATE TRIGGER
+// 语法: CREATE TRIGGER name { BEFORE | AFTER } { INSERT | UPDATE | DELETE } ON table FOR EACH ROW
+//
+//	{ statement | BEGIN statement; [statement; ...] END }
+//
+// 触发器体中的语句只能是 INSERT、UPDATE 或 DELETE，可以用 NEW.column 和 OLD.column 引用行的值
+func (e *Executor) executeCreateTrigger(sql string) (string, error) {
+	stmt, err := parser.ParseCreateTrigger(sql)
+	if err != nil {
+		return "", err
+	}
+	if e.currentTx != nil {
+		return "", fmt.Errorf("CREATE TRIGGER cannot run inside a transaction")
+	}
+
+	tableName, err := e.resolveRelation(stmt.Table)
+	if err != nil {
+		return "", err
+	}
+	schema, err := e.getWritableTable(tableName)
+	if err != nil {
+		return "", err
+	}
+
+	trigger := &catalog.Trigger{
+		Name:       schemaQualified(tableName, stmt.Name),
+		Table:      tableName,
+		Timing:     stmt.Timing,
+		Event:      stmt.Event,
+		Body:       stmt.Body,
+		SearchPath: append([]string(nil), e.getSearchPath()...),
+	}
+
+	// 检查触发器体中的语句和引用的列（行的值用 NULL 代替）
+	for _, body := range trigger.Body {
+		text, err := parser.ReplaceRowReferences(body, func(row, column string) (string, error) {
+			if err := checkRowReference(trigger.Event, row); err != nil {
+				return "", err
+			}
+			if schema.GetColumnIndex(column) == -1 {
+				return "", fmt.Errorf("column not found: %s.%s", row, column)
+			}
+			return "NULL", nil
+		})
+		if err != nil {
+			return "", err
+		}
+		parsed, err := parser.Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("invalid trigger statement %q: %w", body, err)
+		}
+		switch parsed.(type) {
+		case *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
+		default:
+			return "", fmt.Errorf("trigger body only supports INSERT, UPDATE and DELETE statements: %s", body)
+		}
+	}
+
+	if err := e.catalog.CreateTrigger(trigger); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Trigger '%s' created successfully on %s", trigger.Name, tableName), nil
+}
+
+// executeDropTrigger 执行 DROP TRIGGER
+// 语法: DROP TRIGGER [IF EXISTS] name
+func (e *Executor) executeDropTrigger(sql string) (string, error) {
+	stmt, err := parser.ParseDropTrigger(sql)
+	if err != nil {
+		return "", err
+	}
+	if e.currentTx != nil {
+		return "", fmt.Errorf("DROP TRIGGER cannot run inside a transaction")
+	}
+
+	name, err := e.resolveName(stmt.Name, func(qualified string) bool {
+		_, err := e.catalog.GetTrigger(qualified)
+		return err == nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if stmt.IfExists {
+		if _, err := e.catalog.GetTrigger(name); err != nil {
+			return fmt.Sprintf("Trigger '%s' does not exist, skipped", name), nil
+		}
+	}
+
+	if err := e.catalog.DropTrigger(name); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Trigger '%s' dropped successfully", name), nil
+}
+
+// checkRowReference 检查触发器事件中是否可以引用 NEW 或 OLD
+func checkRowReference(event, row string) error {
+	if row == "NEW" && event == "DELETE" {
+		return fmt.Errorf("NEW is not available in DELETE triggers")
+	}
+	if row == "OLD" && event == "INSERT" {
+		return fmt.Errorf("OLD is not available in INSERT triggers")
+	}
+	return nil
+}
+
+// executeDML 执行 INSERT、UPDATE 或 DELETE，语句失败时撤销它已经做的修改（包括触发器中的语句做的修改）
+// 自动提交模式下表上有触发器时，语句和触发器在一个隐式事务中执行
+func (e *Executor) executeDML(table sqlparser.TableName, execute func() (string, error)) (string, error) {
+	if e.currentTx == nil {
+		tableName, err := e.resolveTableName(table)
+		if err != nil || !e.catalog.HasTriggers(tableName) {
+			return execute()
+		}
+
+		if _, err := e.executeBegin(); err != nil {
+			return "", err
+		}
+		result, err := execute()
+		if err != nil {
+			if _, rollbackErr := e.executeRollback(); rollbackErr != nil {
+				return "", rollbackErr
+			}
+			return "", err
+		}
+		if _, err := e.executeCommit(); err != nil {
+			return "", err
+		}
+		return result, nil
+	}
+
+	mark := len(e.currentTx.GetOperations())
+	result, err := execute()
+	if err != nil {
+		if rollbackErr := e.txManager.RollbackTo(e.currentTx.ID, mark, e.rollbackIndexEntries); rollbackErr != nil {
+			return "", rollbackErr
+		}
+		return "", err
+	}
+	return result, nil
+}
+
+// dmlTable 返回 UPDATE 或 DELETE 修改的表名
+func dmlTable(tableExprs sqlparser.TableExprs) sqlparser.TableName {
+	if len(tableExprs) > 0 {
+		if aliased, ok := tableExprs[0].(*sqlparser.AliasedTableExpr); ok {
+			if table, ok := aliased.Expr.(sqlparser.TableName); ok {
+				return table
+			}
+		}
+	}
+	return sqlparser.TableName{}
+}
+
+// fireTriggers 对每一行执行表上在 timing 和 event 时触发的触发器
+// oldRows 和 newRows 中相同下标的是同一行修改前后的值（INSERT 没有 oldRows，DELETE 没有 newRows）
+func (e *Executor) fireTriggers(schema *catalog.TableSchema, timing, event string, oldRows, newRows []*storage.Row) error {
+	triggers := e.catalog.GetTriggers(schema.Name, timing, event)
+	if len(triggers) == 0 {
+		return nil
+	}
+	if e.triggerDepth >= maxTriggerDepth {
+		return fmt.Errorf("trigger nesting depth exceeds %d", maxTriggerDepth)
+	}
+	e.triggerDepth++
+	defer func() { e.triggerDepth-- }()
+
+	for i := 0; i < max(len(oldRows), len(newRows)); i++ {
+		var oldRow, newRow *storage.Row
+		if oldRows != nil {
+			oldRow = oldRows[i]
+		}
+		if newRows != nil {
+			newRow = newRows[i]
+		}
+		for _, trigger := range triggers {
+			if err := e.runTrigger(trigger, schema, oldRow, newRow); err != nil {
+				// 嵌套的触发器失败时只在最外层标明触发器
+				if e.triggerDepth > 1 {
+					return err
+				}
+				return fmt.Errorf("trigger %s: %w", trigger.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// runTrigger 对一行执行触发器体中的语句，语句按创建触发器时的 search_path 解析名字
+func (e *Executor) runTrigger(trigger *catalog.Trigger, schema *catalog.TableSchema, oldRow, newRow *storage.Row) error {
+	path := trigger.SearchPath
+	if len(path) == 0 {
+		path = []string{catalog.DefaultSchema}
+	}
+
+	return e.withSearchPath(path, func() error {
+		for _, body := range trigger.Body {
+			sql, err := parser.ReplaceRowReferences(body, func(ref, column string) (string, error) {
+				row := newRow
+				if ref == "OLD" {
+					row = oldRow
+				}
+				colIndex := schema.GetColumnIndex(column)
+				if row == nil || colIndex == -1 {
+					return "", fmt.Errorf("invalid reference %s.%s", ref, column)
+				}
+				return e.valueLiteral(schema.Columns[colIndex], row.Values[colIndex]), nil
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := e.Execute(sql); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// valueLiteral 把列值转换为可以代入 SQL 语句的字面量
+func (e *Executor) valueLiteral(column catalog.Column, value types.Value) string {
+	switch value.Type {
+	case types.TypeNull:
+		return "NULL"
+	case types.TypeInt, types.TypeBoolean:
+		return value.String()
+	case types.TypeFloat:
+		// 整数值的浮点数也写成带小数点的浮点数字面量，避免被解析为整数（5.0 / 2 仍为 2.5）
+		f, _ := value.AsFloat()
+		text := strconv.FormatFloat(f, 'f', -1, 64)
+		if !strings.ContainsAny(text, ".eE") {
+			text += ".0"
+		}
+		return text
+	case types.TypeDate:
+		t, _ := value.AsDate()
+		if t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 {
+			return quoteLiteral(t.Format("2006-01-02 15:04:05"))
+		}
+		return quoteLiteral(t.Format("2006-01-02"))
+	default:
+		// 文本、UUID 和枚举（标签）
+		return quoteLiteral(e.formatValue(column, value))
+	}
+}
+
+// quoteLiteral 生成字符串字面量（sqlparser 按 MySQL 的规则处理反斜杠转义）
+func quoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// isCreateTrigger 检查是否是 CREATE TRIGGER 语句
+func isCreateTrigger(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "CREATE TRIGGER")
+}
+
+// isDropTrigger 检查是否是 DROP TRIGGER 语句
+func isDropTrigger(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "DROP TRIGGER")
+}
@@ -0,0 +1,20 @@
+package executor
+
+import "testing"
+
+// NEW 中整数值和很大的 FLOAT 值代入为浮点数字面量
+func TestTriggerFloatLiterals(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE readings (id INT, f FLOAT)",
+		"CREATE TABLE halves (id INT, half FLOAT)",
+		"CREATE TRIGGER readings_half AFTER INSERT ON readings FOR EACH ROW INSERT INTO halves VALUES (NEW.id, NEW.f / 2)",
+		"INSERT INTO readings VALUES (1, 5.0)",
+		"INSERT INTO readings VALUES (2, 1e20)",
+		"INSERT INTO readings VALUES (3, -7.0)",
+	)
+
+	expectRows(t, e, "SELECT half FROM halves WHERE id = 1", "2.500000")
+	expectRows(t, e, "SELECT half FROM halves WHERE id = 2", "50000000000000000000.000000")
+	expectRows(t, e, "SELECT half FROM halves WHERE id = 3", "-3.500000")
+}
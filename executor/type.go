@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"fmt"
+	"godb/parser"
+	"strings"
+)
+
+// executeCreateType 执行 CREATE TYPE
+// 语法: CREATE TYPE type_name AS ENUM ('label1', 'label2', ...)
+func (e *Executor) executeCreateType(sql string) (string, error) {
+	stmt, err := parser.ParseCreateType(sql)
+	if err != nil {
+		return "", err
+	}
+	// 枚举类型的 DDL 不记录在事务日志中，无法回滚
+	if e.currentTx != nil {
+		return "", fmt.Errorf("CREATE TYPE cannot run inside a transaction")
+	}
+
+	if err := e.catalog.CreateEnumType(stmt.Name, stmt.Labels); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Type '%s' created successfully", strings.ToLower(stmt.Name)), nil
+}
+
+// executeAlterType 执行 ALTER TYPE
+// 语法: ALTER TYPE type_name ADD VALUE [IF NOT EXISTS] 'label'
+func (e *Executor) executeAlterType(sql string) (string, error) {
+	stmt, err := parser.ParseAlterType(sql)
+	if err != nil {
+		return "", err
+	}
+	if e.currentTx != nil {
+		return "", fmt.Errorf("ALTER TYPE cannot run inside a transaction")
+	}
+
+	if err := e.catalog.AddEnumLabel(stmt.Name, stmt.Label, stmt.IfNotExists); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Type '%s' altered successfully", strings.ToLower(stmt.Name)), nil
+}
+
+// executeDropType 执行 DROP TYPE
+// 语法: DROP TYPE [IF EXISTS] type_name
+func (e *Executor) executeDropType(sql string) (string, error) {
+	stmt, err := parser.ParseDropType(sql)
+	if err != nil {
+		return "", err
+	}
+	if e.currentTx != nil {
+		return "", fmt.Errorf("DROP TYPE cannot run inside a transaction")
+	}
+
+	if stmt.IfExists {
+		if _, err := e.catalog.GetEnumType(stmt.Name); err != nil {
+			return fmt.Sprintf("Type '%s' does not exist, skipped", strings.ToLower(stmt.Name)), nil
+		}
+	}
+
+	if err := e.catalog.DropEnumType(stmt.Name); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Type '%s' dropped successfully", strings.ToLower(stmt.Name)), nil
+}
+
+// isCreateType 检查是否是 CREATE TYPE 语句
+func isCreateType(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "CREATE TYPE")
+}
+
+// isAlterType 检查是否是 ALTER TYPE 语句
+func isAlterType(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "ALTER TYPE")
+}
+
+// isDropType 检查是否是 DROP TYPE 语句
+func isDropType(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "DROP TYPE")
+}
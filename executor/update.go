@@ -13,10 +13,13 @@ import (
 // executeUpdate 执行 UPDATE 语句
 func (e *Executor) executeUpdate(stmt *sqlparser.Update) (string, error) {
 	// 获取表名
-	tableName := stmt.TableExprs[0].(*sqlparser.AliasedTableExpr).Expr.(sqlparser.TableName).Name.String()
+	tableName, err := e.resolveTableName(stmt.TableExprs[0].(*sqlparser.AliasedTableExpr).Expr.(sqlparser.TableName))
+	if err != nil {
+		return "", err
+	}
 
 	// 获取表定义
-	schema, err := e.catalog.GetTable(tableName)
+	schema, err := e.getWritableTable(tableName)
 	if err != nil {
 		return "", err
 	}
@@ -28,123 +31,95 @@ func (e *Executor) executeUpdate(stmt *sqlparser.Update) (string, error) {
 		return "", fmt.Errorf("failed to acquire write lock: %w", err)
 	}
 
-	// 创建表存储
-	tableStorage, err := catalog.CreateTableStorage(e.pager, schema)
-	if err != nil {
-		return "", err
+	// 如果是自动提交模式，在操作完成后释放锁（出错时也要释放）
+	if e.currentTx == nil {
+		defer lockManager.ReleaseLocks(transaction.TransactionID(txID))
 	}
 
-	// 读取所有行（包括已删除的，但我们只处理未删除的）
-	allRows, err := tableStorage.GetAllRowsWithDeleted(true)
+	// 创建表存储
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
 	if err != nil {
 		return "", err
 	}
 
 	// 解析 SET 子句
-	updates := make(map[string]interface{})
+	updates := make(map[int]types.Value)
 	for _, expr := range stmt.Exprs {
 		colName := expr.Name.Name.String()
 
-		// 获取列类型
-		colType, err := schema.GetColumnType(colName)
-		if err != nil {
+		// 获取列定义
+		colIndex := schema.GetColumnIndex(colName)
+		if colIndex == -1 {
+			return "", fmt.Errorf("column not found: %s", colName)
+		}
+		column := schema.Columns[colIndex]
+		if err := checkGeneratedAssignment(column, expr.Expr); err != nil {
 			return "", err
 		}
+		if column.Generated != "" {
+			// SET col = DEFAULT 重新计算生成列，与不赋值相同
+			continue
+		}
 
 		// 计算新值
-		value, err := e.evalExpr(expr.Expr, colType)
+		value, err := e.evalAssignExpr(expr.Expr, column)
 		if err != nil {
 			return "", fmt.Errorf("failed to evaluate value for column %s: %w", colName, err)
 		}
+		if err := column.Validate(value); err != nil {
+			return "", err
+		}
 
-		updates[colName] = value
+		updates[colIndex] = value
 	}
 
-	// 过滤并更新行
-	updateCount := 0
-	for _, row := range allRows {
-		// 跳过已删除的行
-		if row.Deleted {
-			continue
-		}
+	// 读取满足 WHERE 条件的行（主键等值条件使用索引）
+	matchedRows, err := e.scanRows(tableName, stmt.Where, schema, tableStorage)
+	if err != nil {
+		return "", err
+	}
 
-		// 应用 WHERE 条件
-		match := true
-		if stmt.Where != nil {
-			match, err = e.evaluateCondition(row, stmt.Where.Expr, schema)
-			if err != nil {
-				return "", err
-			}
+	// 先构造所有新行，检查约束后再统一写入，避免部分更新
+	ws := &writeSet{}
+	tw, err := e.writesFor(ws, tableName)
+	if err != nil {
+		return "", err
+	}
+	changes := make([]*rowUpdate, 0, len(matchedRows))
+	newRows := make([]*storage.Row, 0, len(matchedRows))
+	for _, row := range matchedRows {
+		// 创建新行（复制原行的值）
+		newRow := copyRow(row)
+		newRow.TxID = txID // 设置事务ID
+
+		// 应用更新
+		for colIndex, value := range updates {
+			newRow.Values[colIndex] = value
 		}
-
-		if match {
-			// 删除旧行的索引条目
-			columnNames := make([]string, len(schema.Columns))
-			for i, col := range schema.Columns {
-				columnNames[i] = col.Name
-			}
-			if err := e.indexManager.DeleteEntry(tableName, row, columnNames); err != nil {
-				return "", fmt.Errorf("failed to delete old index entry: %w", err)
-			}
-
-			// 创建新行（复制原行的值）
-			newRow := &storage.Row{
-				TxID:   txID, // 设置事务ID
-				Values: make([]types.Value, len(row.Values)),
-			}
-			copy(newRow.Values, row.Values)
-
-			// 应用更新
-			for colName, value := range updates {
-				colIndex := schema.GetColumnIndex(colName)
-				if colIndex == -1 {
-					return "", fmt.Errorf("column not found: %s", colName)
-				}
-				newRow.Values[colIndex] = value.(types.Value)
-			}
-
-			// 保存旧行数据（用于回滚）
-			oldRowCopy := &storage.Row{
-				ID:      row.ID,
-				Deleted: row.Deleted,
-				TxID:    row.TxID,
-				Values:  make([]types.Value, len(row.Values)),
-			}
-			copy(oldRowCopy.Values, row.Values)
-
-			// 执行更新（标记旧行删除 + 插入新行）
-			if err := tableStorage.UpdateRow(row.ID, newRow); err != nil {
-				return "", fmt.Errorf("failed to update row: %w", err)
-			}
-
-			// 为新行添加索引条目
-			if err := e.indexManager.InsertEntry(tableName, newRow, columnNames); err != nil {
-				return "", fmt.Errorf("failed to insert new index entry: %w", err)
-			}
-
-			// 记录操作到事务日志（用于回滚）
-			if e.currentTx != nil {
-				op := &transaction.Operation{
-					Type:      transaction.OpUpdate,
-					TableName: tableName,
-					RowID:     row.ID,
-					OldData:   oldRowCopy,
-					NewData:   newRow,
-				}
-				e.currentTx.AddOperation(op)
-			}
-
-			updateCount++
+		if err := e.computeGeneratedColumns(schema, newRow); err != nil {
+			return "", err
 		}
+
+		tw.update(row, newRow)
+		changes = append(changes, &rowUpdate{oldRow: row, newRow: newRow})
+		newRows = append(newRows, newRow)
 	}
 
-	// 如果是自动提交模式，立即释放锁和刷新
-	if e.currentTx == nil {
-		lockManager.ReleaseLocks(transaction.TransactionID(txID))
-		if err := e.pager.FlushAll(); err != nil {
-			return "", fmt.Errorf("failed to flush pages: %w", err)
-		}
+	if err := e.fireTriggers(schema, "BEFORE", "UPDATE", matchedRows, newRows); err != nil {
+		return "", err
+	}
+
+	// 外键的 ON UPDATE 动作
+	if err := e.cascadeUpdates(ws, tw, changes, 0); err != nil {
+		return "", err
+	}
+
+	if err := e.applyWriteSet(ws); err != nil {
+		return "", err
+	}
+	if err := e.fireTriggers(schema, "AFTER", "UPDATE", matchedRows, newRows); err != nil {
+		return "", err
 	}
 
-	return fmt.Sprintf("%d row(s) updated", updateCount), nil
+	return fmt.Sprintf("%d row(s) updated", len(matchedRows)), nil
 }
@@ -0,0 +1,345 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/parser"
+	"godb/storage"
+	"godb/types"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// executeCreateView 执行 CREATE [MATERIALIZED] VIEW
+// 语法: CREATE [OR REPLACE] VIEW name [(column, ...)] AS SELECT ...
+//
+//	CREATE MATERIALIZED VIEW [IF NOT EXISTS] name [(column, ...)] AS SELECT ...
+func (e *Executor) executeCreateView(sql string) (string, error) {
+	stmt, err := parser.ParseCreateView(sql)
+	if err != nil {
+		return "", err
+	}
+	// 视图的 DDL 不记录在事务日志中，无法回滚（物化视图与表一样可以回滚）
+	if !stmt.Materialized && e.currentTx != nil {
+		return "", fmt.Errorf("CREATE VIEW cannot run inside a transaction")
+	}
+
+	name, err := e.newRelationName(stmt.Name)
+	if err != nil {
+		return "", err
+	}
+	if err := checkRelationName(name); err != nil {
+		return "", err
+	}
+	if stmt.IfNotExists {
+		if _, err := e.catalog.GetTable(name); err == nil {
+			return fmt.Sprintf("Materialized view '%s' already exists, skipped", name), nil
+		}
+	}
+
+	query, err := parseViewQuery(stmt.Query)
+	if err != nil {
+		return "", err
+	}
+	depends, err := e.queryDependencies(query)
+	if err != nil {
+		return "", err
+	}
+	// 视图保存在元数据中，不能依赖会话结束时删除的临时表
+	for _, dependency := range depends {
+		if catalog.IsTemporaryName(dependency) {
+			return "", fmt.Errorf("view %s cannot depend on temporary table %s", name, dependency)
+		}
+	}
+
+	view := &catalog.View{
+		Name:       name,
+		Query:      stmt.Query,
+		Columns:    stmt.Columns,
+		Depends:    depends,
+		SearchPath: append([]string(nil), e.getSearchPath()...),
+	}
+
+	// 执行一次查询，检查引用的表和列是否存在，并得到视图的列
+	schema, rows, err := e.queryRelation(query)
+	if err != nil {
+		return "", err
+	}
+	if len(view.Columns) > 0 && len(view.Columns) != len(schema.Columns) {
+		return "", fmt.Errorf("CREATE VIEW specifies %d column names, but the query returns %d columns", len(view.Columns), len(schema.Columns))
+	}
+	if err := renameViewColumns(schema, view.Columns); err != nil {
+		return "", err
+	}
+
+	if stmt.Materialized {
+		if err := e.createMaterializedView(view, schema, rows); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Materialized view '%s' created successfully with %d row(s)", name, len(rows)), nil
+	}
+
+	if err := e.catalog.CreateView(view, stmt.OrReplace); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("View '%s' created successfully", name), nil
+}
+
+// executeDropView 执行 DROP [MATERIALIZED] VIEW
+// 语法: DROP [MATERIALIZED] VIEW [IF EXISTS] name
+func (e *Executor) executeDropView(sql string) (string, error) {
+	stmt, err := parser.ParseDropView(sql)
+	if err != nil {
+		return "", err
+	}
+	if !stmt.Materialized && e.currentTx != nil {
+		return "", fmt.Errorf("DROP VIEW cannot run inside a transaction")
+	}
+
+	name, err := e.resolveRelation(stmt.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if stmt.Materialized {
+		if stmt.IfExists {
+			if _, err := e.getMaterializedView(name); err != nil {
+				return fmt.Sprintf("Materialized view '%s' does not exist, skipped", name), nil
+			}
+		}
+		if err := e.dropMaterializedView(name); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Materialized view '%s' dropped successfully", name), nil
+	}
+
+	if stmt.IfExists {
+		if _, err := e.catalog.GetView(name); err != nil {
+			return fmt.Sprintf("View '%s' does not exist, skipped", name), nil
+		}
+	}
+
+	if err := e.catalog.DropView(name); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("View '%s' dropped successfully", name), nil
+}
+
+// loadRelation 读取表、视图、系统表或附加的数据库中的表的定义和所有行（视图展开为查询结果）
+func (e *Executor) loadRelation(name string) (*catalog.TableSchema, []*storage.Row, error) {
+	if isSystemTable(name) {
+		return e.systemTableRows(name)
+	}
+	if db, relation := e.attachedRelation(name); db != nil {
+		return db.loadRelation(relation)
+	}
+	if view, err := e.catalog.GetView(name); err == nil {
+		return e.viewRows(view)
+	}
+
+	schema, err := e.catalog.GetTable(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := e.readAllRows(schema, tableStorage)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schema, rows, nil
+}
+
+// viewRows 展开视图：执行视图的查询，返回结果的表定义和行
+func (e *Executor) viewRows(view *catalog.View) (*catalog.TableSchema, []*storage.Row, error) {
+	schema, rows, err := e.runViewQuery(view)
+	if err != nil {
+		return nil, nil, fmt.Errorf("view %s: %w", view.Name, err)
+	}
+	if len(view.Columns) > 0 && len(view.Columns) != len(schema.Columns) {
+		return nil, nil, fmt.Errorf("view %s: query returns %d columns, but the view has %d", view.Name, len(schema.Columns), len(view.Columns))
+	}
+	if err := renameViewColumns(schema, view.Columns); err != nil {
+		return nil, nil, err
+	}
+	schema.Name = view.Name
+	return schema, rows, nil
+}
+
+// runViewQuery 执行视图的查询，查询中的名字按创建视图时的 search_path 解析
+func (e *Executor) runViewQuery(view *catalog.View) (*catalog.TableSchema, []*storage.Row, error) {
+	query, err := parseViewQuery(view.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := view.SearchPath
+	if len(path) == 0 {
+		path = []string{catalog.DefaultSchema}
+	}
+	var schema *catalog.TableSchema
+	var rows []*storage.Row
+	err = e.withSearchPath(path, func() error {
+		schema, rows, err = e.queryRelation(query)
+		return err
+	})
+	return schema, rows, err
+}
+
+// queryRelation 执行 SELECT，把结果作为一张临时的表返回（列类型取自源表的列，表达式列取自计算结果）
+func (e *Executor) queryRelation(stmt *sqlparser.Select) (*catalog.TableSchema, []*storage.Row, error) {
+	if len(stmt.From) == 1 {
+		if _, isJoin := stmt.From[0].(*sqlparser.JoinTableExpr); isJoin {
+			return e.joinRelation(stmt)
+		}
+	}
+
+	rows, schema, selectedColumns, err := e.selectRows(stmt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &catalog.TableSchema{Columns: make([]catalog.Column, len(selectedColumns))}
+	resultRows := make([]*storage.Row, len(rows))
+	for i := range rows {
+		resultRows[i] = &storage.Row{Values: make([]types.Value, len(selectedColumns))}
+	}
+
+	for i, item := range selectedColumns {
+		if item.colIndex != -1 {
+			result.Columns[i] = resultColumn(schema.Columns[item.colIndex], item.name)
+			for j, row := range rows {
+				resultRows[j].Values[i] = row.Values[item.colIndex]
+			}
+			continue
+		}
+
+		// 表达式列的类型取第一个非 NULL 的结果，全部为 NULL 时按 TEXT 处理
+		column := catalog.Column{Name: item.name, Type: types.TypeText}
+		typed := false
+		for j, row := range rows {
+			value, err := e.evalRowExpr(row, item.expr, schema)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !typed && !value.IsNull() {
+				column.Type = value.Type
+				typed = true
+			}
+			resultRows[j].Values[i] = value
+		}
+		result.Columns[i] = column
+	}
+
+	return result, resultRows, nil
+}
+
+// joinRelation 执行 JOIN 查询，把结果作为一张临时的表返回
+func (e *Executor) joinRelation(stmt *sqlparser.Select) (*catalog.TableSchema, []*storage.Row, error) {
+	joinedRows, selectedColumns, ctx, err := e.joinRows(stmt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &catalog.TableSchema{Columns: make([]catalog.Column, len(selectedColumns))}
+	for i := range selectedColumns {
+		col := &selectedColumns[i]
+		result.Columns[i] = resultColumn(*ctx.column(col), col.columnName)
+	}
+
+	rows := make([]*storage.Row, len(joinedRows))
+	for i, joinedRow := range joinedRows {
+		values := make([]types.Value, len(selectedColumns))
+		for j := range selectedColumns {
+			col := &selectedColumns[j]
+			// 外连接中不存在的一侧为 NULL
+			values[j] = types.NewNullValue()
+			if source := joinedSource(joinedRow, col); source != nil {
+				values[j] = source.Values[col.colIndex]
+			}
+		}
+		rows[i] = &storage.Row{Values: values}
+	}
+
+	return result, rows, nil
+}
+
+// resultColumn 由源表的列得到查询结果的列（保留类型和排序规则，不保留约束）
+func resultColumn(column catalog.Column, name string) catalog.Column {
+	column.Name = name
+	column.NotNull = false
+	column.Default = ""
+	column.Generated = ""
+	column.Stored = false
+	return column
+}
+
+// renameViewColumns 按 CREATE VIEW 声明的列名重命名结果的列，并检查列名不重复
+func renameViewColumns(schema *catalog.TableSchema, columns []string) error {
+	seen := make(map[string]bool, len(schema.Columns))
+	for i := range schema.Columns {
+		if len(columns) > 0 {
+			schema.Columns[i].Name = columns[i]
+		}
+		name := schema.Columns[i].Name
+		if seen[name] {
+			return fmt.Errorf("column %s specified more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// parseViewQuery 解析视图的查询，必须是 SELECT 语句
+func parseViewQuery(query string) (*sqlparser.Select, error) {
+	stmt, err := parser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return nil, fmt.Errorf("view query must be a SELECT statement")
+	}
+	return sel, nil
+}
+
+// queryDependencies 返回查询中引用的表和视图（按 search_path 解析后的名字）
+func (e *Executor) queryDependencies(stmt *sqlparser.Select) ([]string, error) {
+	deps := make([]string, 0)
+	seen := make(map[string]bool)
+	err := sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if tableName, ok := node.(sqlparser.TableName); ok {
+			if tableName.Name.IsEmpty() || (tableName.Qualifier.IsEmpty() && tableName.Name.String() == "dual") {
+				return true, nil
+			}
+			name, err := e.resolveTableName(tableName)
+			if err != nil {
+				return false, err
+			}
+			if !seen[name] {
+				seen[name] = true
+				deps = append(deps, name)
+			}
+		}
+		return true, nil
+	}, stmt.From)
+	return deps, err
+}
+
+// isCreateView 检查是否是 CREATE [OR REPLACE | MATERIALIZED] VIEW 语句
+func isCreateView(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "CREATE VIEW") || strings.HasPrefix(sql, "CREATE OR REPLACE VIEW") ||
+		strings.HasPrefix(sql, "CREATE MATERIALIZED VIEW")
+}
+
+// isDropView 检查是否是 DROP [MATERIALIZED] VIEW 语句
+func isDropView(sql string) bool {
+	sql = strings.TrimSpace(strings.ToUpper(sql))
+	return strings.HasPrefix(sql, "DROP VIEW") || strings.HasPrefix(sql, "DROP MATERIALIZED VIEW")
+}
@@ -0,0 +1,302 @@
+package executor
+
+import (
+	"fmt"
+	"godb/catalog"
+	"godb/storage"
+	"godb/transaction"
+	"godb/types"
+)
+
+// rowUpdate 一行的更新（旧行标记删除，插入新行）
+type rowUpdate struct {
+	oldRow *storage.Row
+	newRow *storage.Row
+}
+
+// tableWrites 一条语句对一张表的修改
+type tableWrites struct {
+	schema       *catalog.TableSchema
+	tableStorage *storage.TableStorage
+	inserts      []*storage.Row
+	updates      []*rowUpdate
+	deletes      []*storage.Row
+	removed      map[storage.RowID]*rowUpdate // 被删除（nil）或被更新（对应的更新）的旧行
+}
+
+// writeSet 一条语句的所有修改，包括外键级联产生的对其他表的修改
+// 先收集全部修改并检查约束，检查通过后再统一写入，避免部分写入
+type writeSet struct {
+	tables []*tableWrites // 按首次修改的顺序
+}
+
+// find 返回表的修改，表没有被修改时返回 nil
+func (ws *writeSet) find(tableName string) *tableWrites {
+	for _, tw := range ws.tables {
+		if tw.schema.Name == tableName {
+			return tw
+		}
+	}
+	return nil
+}
+
+// getWritableTable 获取可以用 INSERT、UPDATE 和 DELETE 修改的表（系统表和物化视图不能直接修改）
+func (e *Executor) getWritableTable(tableName string) (*catalog.TableSchema, error) {
+	if isSystemTable(tableName) {
+		return nil, fmt.Errorf("cannot change system table %s", tableName)
+	}
+	if db, _ := e.attachedRelation(tableName); db != nil {
+		return nil, fmt.Errorf("cannot change %s: attached databases are read-only", tableName)
+	}
+	schema, err := e.catalog.GetTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if schema.View != nil {
+		return nil, fmt.Errorf("cannot change materialized view %s", tableName)
+	}
+	return schema, nil
+}
+
+// writesFor 返回表的修改，第一次修改该表时获取表的写锁
+func (e *Executor) writesFor(ws *writeSet, tableName string) (*tableWrites, error) {
+	if tw := ws.find(tableName); tw != nil {
+		return tw, nil
+	}
+
+	schema, err := e.getWritableTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	txID := e.getCurrentTxID()
+	if err := e.txManager.GetLockManager().AcquireWriteLock(tableName, transaction.TransactionID(txID)); err != nil {
+		return nil, fmt.Errorf("failed to acquire write lock: %w", err)
+	}
+
+	tableStorage, err := catalog.CreateTableStorage(e.pagerFor(schema), schema)
+	if err != nil {
+		return nil, err
+	}
+
+	tw := &tableWrites{
+		schema:       schema,
+		tableStorage: tableStorage,
+		removed:      make(map[storage.RowID]*rowUpdate),
+	}
+	ws.tables = append(ws.tables, tw)
+	return tw, nil
+}
+
+// insert 插入新行
+func (tw *tableWrites) insert(row *storage.Row) {
+	tw.inserts = append(tw.inserts, row)
+}
+
+// update 把已有的行更新为 newRow，同一行多次更新时以最后一次为准
+// 行已经被删除时返回 false
+func (tw *tableWrites) update(oldRow, newRow *storage.Row) bool {
+	if u, ok := tw.removed[oldRow.ID]; ok {
+		if u == nil {
+			return false
+		}
+		u.newRow = newRow
+		return true
+	}
+
+	u := &rowUpdate{oldRow: oldRow, newRow: newRow}
+	tw.updates = append(tw.updates, u)
+	tw.removed[oldRow.ID] = u
+	return true
+}
+
+// delete 删除已有的行，行已经被删除时返回 false
+func (tw *tableWrites) delete(row *storage.Row) bool {
+	if u, ok := tw.removed[row.ID]; ok {
+		if u == nil {
+			return false
+		}
+		// 已计划更新的行改为删除
+		for i, other := range tw.updates {
+			if other == u {
+				tw.updates = append(tw.updates[:i], tw.updates[i+1:]...)
+				break
+			}
+		}
+	}
+
+	tw.deletes = append(tw.deletes, row)
+	tw.removed[row.ID] = nil
+	return true
+}
+
+// current 返回已有的行在本条语句修改后的值（已删除的行返回 nil）
+func (tw *tableWrites) current(row *storage.Row) *storage.Row {
+	if u, ok := tw.removed[row.ID]; ok {
+		if u == nil {
+			return nil
+		}
+		return u.newRow
+	}
+	return row
+}
+
+// newRows 返回本条语句写入的新行（插入的行和更新后的行）
+func (tw *tableWrites) newRows() []*storage.Row {
+	rows := make([]*storage.Row, 0, len(tw.inserts)+len(tw.updates))
+	rows = append(rows, tw.inserts...)
+	for _, u := range tw.updates {
+		rows = append(rows, u.newRow)
+	}
+	return rows
+}
+
+// applyWriteSet 检查约束后写入所有修改
+func (e *Executor) applyWriteSet(ws *writeSet) error {
+	// 检查 CHECK 约束
+	for _, tw := range ws.tables {
+		if err := e.checkConstraints(tw.schema, tw.newRows()); err != nil {
+			return err
+		}
+	}
+
+	// 检查外键
+	if err := e.checkForeignKeys(ws); err != nil {
+		return err
+	}
+
+	// 检查主键和唯一索引，以及写入的键能否放入索引
+	for _, tw := range ws.tables {
+		if err := e.checkIndexKeys(tw.schema, tw.newRows()); err != nil {
+			return err
+		}
+		replaced := make(map[storage.RowID]bool, len(tw.removed))
+		for rowID := range tw.removed {
+			replaced[rowID] = true
+		}
+		if err := e.checkUniqueKeys(tw.schema, tw.tableStorage, tw.newRows(), replaced); err != nil {
+			return err
+		}
+	}
+
+	for _, tw := range ws.tables {
+		if err := e.applyTableWrites(tw); err != nil {
+			return err
+		}
+	}
+
+	// 如果是自动提交模式，立即刷新
+	if e.currentTx == nil {
+		if err := e.pager.FlushAll(); err != nil {
+			return fmt.Errorf("failed to flush pages: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// applyTableWrites 写入一张表的修改并维护索引和事务日志
+func (e *Executor) applyTableWrites(tw *tableWrites) error {
+	tableName := tw.schema.Name
+	columnNames := make([]string, len(tw.schema.Columns))
+	for i, col := range tw.schema.Columns {
+		columnNames[i] = col.Name
+	}
+
+	// 删除行
+	for _, row := range tw.deletes {
+		// 删除索引条目
+		if err := e.indexManager.DeleteEntry(tableName, row, columnNames); err != nil {
+			return fmt.Errorf("failed to delete index entry: %w", err)
+		}
+
+		// 标记行为删除
+		if err := tw.tableStorage.MarkRowDeleted(row.ID); err != nil {
+			return fmt.Errorf("failed to delete row: %w", err)
+		}
+
+		// 记录操作到事务日志（用于回滚）
+		if e.currentTx != nil {
+			e.currentTx.AddOperation(&transaction.Operation{
+				Type:      transaction.OpDelete,
+				TableName: tableName,
+				RowID:     row.ID,
+				OldData:   copyRow(row),
+			})
+		}
+	}
+
+	// 先删除所有旧行的索引条目，避免行之间交换唯一键时冲突
+	for _, u := range tw.updates {
+		if err := e.indexManager.DeleteEntry(tableName, u.oldRow, columnNames); err != nil {
+			return fmt.Errorf("failed to delete old index entry: %w", err)
+		}
+	}
+
+	// 更新行
+	for _, u := range tw.updates {
+		// 保存旧行数据（用于回滚）
+		oldRowCopy := copyRow(u.oldRow)
+
+		// 执行更新（标记旧行删除 + 插入新行）
+		stored := storedRow(tw.schema, u.newRow)
+		if err := tw.tableStorage.UpdateRow(u.oldRow.ID, stored); err != nil {
+			return fmt.Errorf("failed to update row: %w", err)
+		}
+		u.newRow.ID = stored.ID
+
+		// 为新行添加索引条目
+		if err := e.indexManager.InsertEntry(tableName, u.newRow, columnNames); err != nil {
+			return fmt.Errorf("failed to insert new index entry: %w", err)
+		}
+
+		// 记录操作到事务日志（用于回滚）
+		if e.currentTx != nil {
+			e.currentTx.AddOperation(&transaction.Operation{
+				Type:      transaction.OpUpdate,
+				TableName: tableName,
+				RowID:     u.oldRow.ID,
+				OldData:   oldRowCopy,
+				NewData:   u.newRow,
+			})
+		}
+	}
+
+	// 插入行
+	for _, row := range tw.inserts {
+		stored := storedRow(tw.schema, row)
+		if err := tw.tableStorage.InsertRow(stored); err != nil {
+			return fmt.Errorf("failed to insert row: %w", err)
+		}
+		row.ID = stored.ID
+
+		// 更新所有相关索引
+		if err := e.indexManager.InsertEntry(tableName, row, columnNames); err != nil {
+			return fmt.Errorf("failed to update index: %w", err)
+		}
+
+		// 记录操作到事务日志（用于回滚）
+		if e.currentTx != nil {
+			e.currentTx.AddOperation(&transaction.Operation{
+				Type:      transaction.OpInsert,
+				TableName: tableName,
+				RowID:     row.ID,
+				NewData:   row,
+			})
+		}
+	}
+
+	return nil
+}
+
+// copyRow 复制一行（用于回滚和构造更新后的行）
+func copyRow(row *storage.Row) *storage.Row {
+	rowCopy := &storage.Row{
+		ID:      row.ID,
+		Deleted: row.Deleted,
+		TxID:    row.TxID,
+		Values:  make([]types.Value, len(row.Values)),
+	}
+	copy(rowCopy.Values, row.Values)
+	return rowCopy
+}
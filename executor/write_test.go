@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+// 索引键过长时整条语句在写入之前失败，表和索引都不变
+func TestIndexKeyTooLongLeavesTableUnchanged(t *testing.T) {
+	e, _ := newTestExecutor(t)
+	mustExec(t, e,
+		"CREATE TABLE docs (id INT PRIMARY KEY, title TEXT)",
+		"CREATE INDEX docs_title ON docs (title)",
+		"INSERT INTO docs VALUES (1, 'short')",
+	)
+
+	long := strings.Repeat("x", 1000)
+	mustFail(t, e, "INSERT INTO docs VALUES (2, '"+long+"')", "index key too long")
+	mustFail(t, e, "UPDATE docs SET title = '"+long+"' WHERE id = 1", "index key too long")
+
+	expectRows(t, e, "SELECT * FROM docs", "1\tshort")
+	expectRows(t, e, "SELECT id FROM docs WHERE id = 2")
+	expectRows(t, e, "SELECT index_name, entries FROM godb_indexes",
+		"docs_pkey\t1",
+		"docs_title\t1",
+	)
+	expectRows(t, e, "SELECT id FROM docs WHERE title = 'short'", "1")
+}
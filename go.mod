@@ -3,6 +3,6 @@ module godb
 go 1.23.1
 
 require (
-	github.com/google/btree v1.1.3 // indirect
-	github.com/xwb1989/sqlparser v0.0.0-20180606152119-120387863bf2 // indirect
+	github.com/xwb1989/sqlparser v0.0.0-20180606152119-120387863bf2
+	golang.org/x/text v0.21.0
 )
@@ -0,0 +1,557 @@
+package index
+
+import (
+	"encoding/binary"
+	"fmt"
+	"godb/storage"
+	"godb/types"
+)
+
+// 索引保存为 Pager 中的 B+ 树，每个节点占一页：
+//   - 叶子节点（PageTypeIndexLeaf）按顺序保存条目（键值 + 行 ID），NextPage 指向右边的叶子节点
+//   - 内部节点（PageTypeIndexInternal）保存 n 个分隔条目和 n+1 个子节点的页 ID，
+//     子树 i 中的条目都小于分隔条目 i，子树 i+1 中的条目都大于或等于分隔条目 i
+//
+// 条目按（键值, 行 ID）排序，相同键值的多个条目也有确定的位置。
+// 根节点的页 ID 在索引的整个生命周期中不变（根节点分裂和合并时移动根节点的内容），元数据中只保存根节点页 ID
+
+const (
+	nodeCapacity = storage.PageSize - storage.HeaderSize // 节点数据区的大小
+	maxEntrySize = nodeCapacity/6 - 4                    // 条目的最大大小，保证分裂和重新分配后的两半都能放入一页
+	minNodeSize  = nodeCapacity / 2                      // 删除后小于该大小的节点与兄弟节点合并或重新分配
+	rowIDSize    = 6                                     // 行 ID 的大小（页 ID 4 字节 + 行索引 2 字节）
+)
+
+// entry 索引条目
+type entry struct {
+	key   types.Value
+	rowID storage.RowID
+}
+
+// node 解码后的 B+ 树节点
+type node struct {
+	page     *storage.Page
+	leaf     bool
+	entries  []entry  // 叶子节点的条目，或内部节点的分隔条目
+	children []uint32 // 内部节点的子节点页 ID
+	next     uint32   // 叶子节点右边的叶子节点（0 表示没有）
+}
+
+// btree 保存在页中的 B+ 树
+type btree struct {
+	pager     *storage.Pager
+	root      uint32          // 根节点页 ID
+	collation types.Collation // TEXT 键的排序规则
+}
+
+// newBTree 分配根节点页，创建空的 B+ 树
+func newBTree(pager *storage.Pager, collation types.Collation) (*btree, error) {
+	page, err := pager.AllocatePage(storage.PageTypeIndexLeaf)
+	if err != nil {
+		return nil, err
+	}
+	t := &btree{pager: pager, root: page.ID, collation: collation}
+	if err := t.writeNode(&node{page: page, leaf: true}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// compare 比较两个条目：先按排序规则比较键值，键值相等时比较行 ID
+func (t *btree) compare(a, b entry) int {
+	if cmp := types.Compare(a.key, b.key, t.collation); cmp != 0 {
+		return cmp
+	}
+	if a.rowID.PageID != b.rowID.PageID {
+		if a.rowID.PageID < b.rowID.PageID {
+			return -1
+		}
+		return 1
+	}
+	if a.rowID.RowIndex != b.rowID.RowIndex {
+		if a.rowID.RowIndex < b.rowID.RowIndex {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// search 返回第一个大于或等于 target 的条目在 entries 中的位置
+func (t *btree) search(entries []entry, target entry) int {
+	low, high := 0, len(entries)
+	for low < high {
+		mid := (low + high) / 2
+		if t.compare(entries[mid], target) < 0 {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
+
+// childFor 返回内部节点中可能包含 target 的子节点序号
+func (t *btree) childFor(n *node, target entry) int {
+	i := t.search(n.entries, target)
+	if i < len(n.entries) && t.compare(n.entries[i], target) == 0 {
+		i++
+	}
+	return i
+}
+
+// insert 插入条目（条目已存在时不变）
+func (t *btree) insert(e entry) error {
+	if err := checkEntrySize(e); err != nil {
+		return err
+	}
+
+	separator, right, err := t.insertInto(t.root, e)
+	if err != nil || right == 0 {
+		return err
+	}
+	return t.growRoot(separator, right)
+}
+
+// growRoot 根节点分裂后把根节点（分裂后的左半部分）移到新页，根节点变为有两个子节点的内部节点
+func (t *btree) growRoot(separator entry, right uint32) error {
+	root, err := t.readNode(t.root)
+	if err != nil {
+		return err
+	}
+	page, err := t.pager.AllocatePage(pageTypeOf(root))
+	if err != nil {
+		return err
+	}
+	left := &node{page: page, leaf: root.leaf, entries: root.entries, children: root.children, next: root.next}
+	if err := t.writeNode(left); err != nil {
+		return err
+	}
+	return t.writeNode(&node{page: root.page, entries: []entry{separator}, children: []uint32{page.ID, right}})
+}
+
+// insertInto 把条目插入以 pageID 为根的子树
+// 节点分裂时返回新的右节点的页 ID 和父节点中需要加入的分隔条目，没有分裂时返回的页 ID 为 0
+func (t *btree) insertInto(pageID uint32, e entry) (entry, uint32, error) {
+	n, err := t.readNode(pageID)
+	if err != nil {
+		return entry{}, 0, err
+	}
+
+	if n.leaf {
+		i := t.search(n.entries, e)
+		if i < len(n.entries) && t.compare(n.entries[i], e) == 0 {
+			return entry{}, 0, nil
+		}
+		n.entries = insertEntry(n.entries, i, e)
+	} else {
+		i := t.childFor(n, e)
+		separator, right, err := t.insertInto(n.children[i], e)
+		if err != nil || right == 0 {
+			return entry{}, 0, err
+		}
+		n.entries = insertEntry(n.entries, i, separator)
+		n.children = insertChild(n.children, i+1, right)
+	}
+	return t.store(n)
+}
+
+// store 写入修改后的节点，超出页大小时分裂（返回值与 insertInto 相同）
+func (t *btree) store(n *node) (entry, uint32, error) {
+	if n.size() <= nodeCapacity {
+		return entry{}, 0, t.writeNode(n)
+	}
+	return t.split(n)
+}
+
+// split 把超出页大小的节点按大小分成两半，右半部分写入新分配的页
+func (t *btree) split(n *node) (entry, uint32, error) {
+	page, err := t.pager.AllocatePage(pageTypeOf(n))
+	if err != nil {
+		return entry{}, 0, err
+	}
+	right := &node{page: page, leaf: n.leaf}
+
+	var separator entry
+	if n.leaf {
+		m := splitPoint(n.entries, 0, 1, len(n.entries)-1)
+		right.entries = append([]entry(nil), n.entries[m:]...)
+		right.next = n.next
+		n.entries = n.entries[:m]
+		n.next = page.ID
+		separator = right.entries[0]
+	} else {
+		// 中间的分隔条目移到父节点
+		m := splitPoint(n.entries, 4, 1, len(n.entries)-2)
+		separator = n.entries[m]
+		right.entries = append([]entry(nil), n.entries[m+1:]...)
+		right.children = append([]uint32(nil), n.children[m+1:]...)
+		n.entries = n.entries[:m]
+		n.children = n.children[:m+1]
+	}
+
+	if err := t.writeNode(right); err != nil {
+		return entry{}, 0, err
+	}
+	if err := t.writeNode(n); err != nil {
+		return entry{}, 0, err
+	}
+	return separator, page.ID, nil
+}
+
+// delete 删除条目，返回条目是否存在
+func (t *btree) delete(e entry) (bool, error) {
+	found, separator, right, err := t.deleteFrom(t.root, e)
+	if err != nil || !found {
+		return found, err
+	}
+	if right != 0 {
+		return true, t.growRoot(separator, right)
+	}
+
+	// 根节点只剩一个子节点时，把子节点的内容移到根节点，树的高度减一
+	root, err := t.readNode(t.root)
+	if err != nil {
+		return true, err
+	}
+	if root.leaf || len(root.entries) > 0 {
+		return true, nil
+	}
+	child, err := t.readNode(root.children[0])
+	if err != nil {
+		return true, err
+	}
+	if err := t.writeNode(&node{page: root.page, leaf: child.leaf, entries: child.entries, children: child.children, next: child.next}); err != nil {
+		return true, err
+	}
+	t.pager.FreePages([]uint32{child.page.ID})
+	return true, nil
+}
+
+// deleteFrom 从以 pageID 为根的子树中删除条目，子节点删除后过小时与兄弟节点合并或重新分配
+// 重新分配换上的分隔条目可能更长，节点因此超出页大小时分裂（返回值与 insertInto 相同）
+func (t *btree) deleteFrom(pageID uint32, e entry) (bool, entry, uint32, error) {
+	n, err := t.readNode(pageID)
+	if err != nil {
+		return false, entry{}, 0, err
+	}
+
+	if n.leaf {
+		i := t.search(n.entries, e)
+		if i == len(n.entries) || t.compare(n.entries[i], e) != 0 {
+			return false, entry{}, 0, nil
+		}
+		n.entries = append(n.entries[:i], n.entries[i+1:]...)
+		return true, entry{}, 0, t.writeNode(n)
+	}
+
+	i := t.childFor(n, e)
+	found, separator, right, err := t.deleteFrom(n.children[i], e)
+	if err != nil || !found {
+		return found, entry{}, 0, err
+	}
+	if right != 0 {
+		n.entries = insertEntry(n.entries, i, separator)
+		n.children = insertChild(n.children, i+1, right)
+	} else if changed, err := t.rebalance(n, i); err != nil || !changed {
+		return true, entry{}, 0, err
+	}
+	separator, right, err = t.store(n)
+	return true, separator, right, err
+}
+
+// rebalance 子节点 i 小于 minNodeSize 时与相邻的兄弟节点合并（合并后能放入一页）或在两者之间重新分配条目
+// 返回父节点是否被修改（由调用者写入）
+func (t *btree) rebalance(parent *node, i int) (bool, error) {
+	child, err := t.readNode(parent.children[i])
+	if err != nil {
+		return false, err
+	}
+	if child.size() >= minNodeSize {
+		return false, nil
+	}
+
+	// 总是处理相邻的一对节点（左边 i，右边 i+1）
+	if i == len(parent.children)-1 {
+		i--
+	}
+	left, err := t.readNode(parent.children[i])
+	if err != nil {
+		return false, err
+	}
+	right, err := t.readNode(parent.children[i+1])
+	if err != nil {
+		return false, err
+	}
+
+	// 合并后的条目：内部节点的分隔条目下移到合并的节点中
+	entries := append([]entry(nil), left.entries...)
+	var children []uint32
+	if !left.leaf {
+		entries = append(entries, parent.entries[i])
+		children = append(append([]uint32(nil), left.children...), right.children...)
+	}
+	entries = append(entries, right.entries...)
+	merged := &node{page: left.page, leaf: left.leaf, entries: entries, children: children, next: right.next}
+
+	if merged.size() <= nodeCapacity {
+		if err := t.writeNode(merged); err != nil {
+			return false, err
+		}
+		parent.entries = append(parent.entries[:i], parent.entries[i+1:]...)
+		parent.children = append(parent.children[:i+1], parent.children[i+2:]...)
+		t.pager.FreePages([]uint32{right.page.ID})
+		return true, nil
+	}
+
+	// 放不进一页：按大小重新分成两半
+	if left.leaf {
+		m := splitPoint(entries, 0, 1, len(entries)-1)
+		left.entries, right.entries = entries[:m], append([]entry(nil), entries[m:]...)
+		parent.entries[i] = right.entries[0]
+	} else {
+		m := splitPoint(entries, 4, 1, len(entries)-2)
+		left.entries, right.entries = entries[:m], append([]entry(nil), entries[m+1:]...)
+		left.children, right.children = children[:m+1], append([]uint32(nil), children[m+1:]...)
+		parent.entries[i] = entries[m]
+	}
+	if err := t.writeNode(left); err != nil {
+		return false, err
+	}
+	if err := t.writeNode(right); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ascend 从第一个大于或等于 from 的条目开始按顺序遍历条目，fn 返回 false 时停止
+// from 为 nil 时从最小的条目开始
+func (t *btree) ascend(from *entry, fn func(e entry) bool) error {
+	n, err := t.readNode(t.root)
+	if err != nil {
+		return err
+	}
+	for !n.leaf {
+		i := 0
+		if from != nil {
+			i = t.childFor(n, *from)
+		}
+		if n, err = t.readNode(n.children[i]); err != nil {
+			return err
+		}
+	}
+
+	i := 0
+	if from != nil {
+		i = t.search(n.entries, *from)
+	}
+	for {
+		for ; i < len(n.entries); i++ {
+			if !fn(n.entries[i]) {
+				return nil
+			}
+		}
+		if n.next == 0 {
+			return nil
+		}
+		if n, err = t.readNode(n.next); err != nil {
+			return err
+		}
+		i = 0
+	}
+}
+
+// count 返回条目数（遍历所有叶子节点的页头，不解码条目）
+func (t *btree) count() (int, error) {
+	pageID := t.root
+	for {
+		page, err := t.pager.GetPage(pageID)
+		if err != nil {
+			return 0, err
+		}
+		if page.Type == storage.PageTypeIndexLeaf {
+			break
+		}
+		pageID = binary.LittleEndian.Uint32(page.Data[0:4])
+	}
+
+	count := 0
+	for pageID != 0 {
+		page, err := t.pager.GetPage(pageID)
+		if err != nil {
+			return 0, err
+		}
+		count += int(page.RowCount)
+		pageID = page.NextPage
+	}
+	return count, nil
+}
+
+// pageIDs 返回 B+ 树所有节点的页 ID
+func (t *btree) pageIDs() ([]uint32, error) {
+	pageIDs := make([]uint32, 0)
+	pending := []uint32{t.root}
+	for len(pending) > 0 {
+		pageID := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		n, err := t.readNode(pageID)
+		if err != nil {
+			return nil, err
+		}
+		pageIDs = append(pageIDs, pageID)
+		pending = append(pending, n.children...)
+	}
+	return pageIDs, nil
+}
+
+// readNode 读取并解码节点
+func (t *btree) readNode(pageID uint32) (*node, error) {
+	page, err := t.pager.GetPage(pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &node{page: page}
+	switch page.Type {
+	case storage.PageTypeIndexLeaf:
+		n.leaf = true
+		n.next = page.NextPage
+	case storage.PageTypeIndexInternal:
+	default:
+		return nil, fmt.Errorf("page %d is not an index page", pageID)
+	}
+
+	data := page.Data
+	offset := 0
+	if !n.leaf {
+		n.children = append(n.children, binary.LittleEndian.Uint32(data[0:4]))
+		offset = 4
+	}
+	n.entries = make([]entry, 0, page.RowCount)
+	for i := uint16(0); i < page.RowCount; i++ {
+		key, bytesRead, err := types.Deserialize(data[offset:])
+		if err != nil {
+			return nil, fmt.Errorf("corrupted index page %d: %w", pageID, err)
+		}
+		offset += bytesRead
+		if offset+rowIDSize > len(data) {
+			return nil, fmt.Errorf("corrupted index page %d", pageID)
+		}
+		rowID := storage.RowID{
+			PageID:   binary.LittleEndian.Uint32(data[offset : offset+4]),
+			RowIndex: binary.LittleEndian.Uint16(data[offset+4 : offset+6]),
+		}
+		offset += rowIDSize
+		n.entries = append(n.entries, entry{key: key, rowID: rowID})
+
+		if !n.leaf {
+			n.children = append(n.children, binary.LittleEndian.Uint32(data[offset:offset+4]))
+			offset += 4
+		}
+	}
+	return n, nil
+}
+
+// writeNode 编码节点写入所在的页并刷新到磁盘
+func (t *btree) writeNode(n *node) error {
+	page := n.page
+	page.Type = pageTypeOf(n)
+	page.RowCount = uint16(len(n.entries))
+	page.NextPage = n.next
+
+	data := make([]byte, 0, nodeCapacity)
+	if !n.leaf {
+		data = binary.LittleEndian.AppendUint32(data, n.children[0])
+	}
+	for i, e := range n.entries {
+		key, err := e.key.Serialize()
+		if err != nil {
+			return err
+		}
+		data = append(data, key...)
+		data = binary.LittleEndian.AppendUint32(data, e.rowID.PageID)
+		data = binary.LittleEndian.AppendUint16(data, e.rowID.RowIndex)
+		if !n.leaf {
+			data = binary.LittleEndian.AppendUint32(data, n.children[i+1])
+		}
+	}
+	if len(data) > nodeCapacity {
+		return fmt.Errorf("index node too large for page %d", page.ID)
+	}
+	clear(page.Data)
+	copy(page.Data, data)
+
+	return t.pager.FlushPage(page.ID)
+}
+
+// size 返回节点编码后的大小
+func (n *node) size() int {
+	size := 0
+	if !n.leaf {
+		size = 4 + 4*len(n.entries)
+	}
+	for _, e := range n.entries {
+		size += entrySize(e)
+	}
+	return size
+}
+
+// checkEntrySize 检查条目能否放入 B+ 树的节点
+func checkEntrySize(e entry) error {
+	if size := entrySize(e); size > maxEntrySize {
+		return fmt.Errorf("index key too long: %d bytes (maximum %d)", size, maxEntrySize)
+	}
+	return nil
+}
+
+// entrySize 返回条目编码后的大小
+func entrySize(e entry) int {
+	data, err := e.key.Serialize()
+	if err != nil {
+		return 0
+	}
+	return len(data) + rowIDSize
+}
+
+// splitPoint 返回按大小把条目分成两半的位置：前 m 个条目和其余条目中较大的一半尽量小
+// （每个条目额外占用 extra 字节），结果限制在 [low, high] 中
+func splitPoint(entries []entry, extra, low, high int) int {
+	total := 0
+	for _, e := range entries {
+		total += entrySize(e) + extra
+	}
+	m, size := 0, 0
+	for m < len(entries) && size < total/2 {
+		size += entrySize(entries[m]) + extra
+		m++
+	}
+	// 越过一半的条目放在较小的一半中
+	if m > 0 && total-size+entrySize(entries[m-1])+extra < size {
+		m--
+	}
+	return max(low, min(m, high))
+}
+
+// pageTypeOf 返回节点的页类型
+func pageTypeOf(n *node) storage.PageType {
+	if n.leaf {
+		return storage.PageTypeIndexLeaf
+	}
+	return storage.PageTypeIndexInternal
+}
+
+// insertEntry 在位置 i 插入条目
+func insertEntry(entries []entry, i int, e entry) []entry {
+	entries = append(entries, entry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = e
+	return entries
+}
+
+// insertChild 在位置 i 插入子节点页 ID
+func insertChild(children []uint32, i int, child uint32) []uint32 {
+	children = append(children, 0)
+	copy(children[i+1:], children[i:])
+	children[i] = child
+	return children
+}
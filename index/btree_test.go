@@ -0,0 +1,140 @@
+package index
+
+import (
+	"fmt"
+	"godb/storage"
+	"godb/types"
+	"math/rand"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// 随机插入和删除变长 TEXT 键，重新打开后按顺序遍历和计数都与参照集合一致
+func TestBTreeRandomOperations(t *testing.T) {
+	tests := []struct {
+		name       string
+		seed       int64
+		ops        int
+		minKeyLen  int
+		maxKeyLen  int
+		deleteRate float64 // 每次操作是删除的概率
+		collation  types.Collation
+	}{
+		{name: "short keys", seed: 1, ops: 4000, minKeyLen: 1, maxKeyLen: 8, deleteRate: 0.3, collation: types.CollationBinary},
+		{name: "long keys", seed: 2, ops: 2000, minKeyLen: 100, maxKeyLen: 600, deleteRate: 0.3, collation: types.CollationBinary},
+		{name: "mixed lengths heavy deletes", seed: 3, ops: 5000, minKeyLen: 1, maxKeyLen: 600, deleteRate: 0.55, collation: types.CollationBinary},
+		{name: "nocase duplicates", seed: 4, ops: 3000, minKeyLen: 1, maxKeyLen: 3, deleteRate: 0.4, collation: types.CollationNoCase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(tt.seed))
+			dbFile := filepath.Join(t.TempDir(), "test.db")
+			pager, err := storage.NewPager(dbFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 与数据库中一样，索引不占用第 0 页
+			if _, err := pager.AllocatePage(storage.PageTypeTable); err != nil {
+				t.Fatal(err)
+			}
+
+			idx, err := NewIndex(pager, "idx", "t", "c", types.TypeText, tt.collation, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reference := make([]entry, 0)
+			for i := 0; i < tt.ops; i++ {
+				if len(reference) > 0 && rng.Float64() < tt.deleteRate {
+					j := rng.Intn(len(reference))
+					e := reference[j]
+					if err := idx.Delete(e.key, e.rowID); err != nil {
+						t.Fatalf("delete %d: %v", i, err)
+					}
+					reference = append(reference[:j], reference[j+1:]...)
+					continue
+				}
+				e := entry{
+					key:   types.NewTextValue(randomKey(rng, tt.minKeyLen, tt.maxKeyLen)),
+					rowID: storage.RowID{PageID: uint32(i / 100), RowIndex: uint16(i % 100)},
+				}
+				if err := idx.Insert(e.key, e.rowID); err != nil {
+					t.Fatalf("insert %d: %v", i, err)
+				}
+				reference = append(reference, e)
+			}
+			checkTree(t, idx, reference)
+
+			// 关闭后重新打开
+			root := idx.RootPageID()
+			if err := pager.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if pager, err = storage.NewPager(dbFile); err != nil {
+				t.Fatal(err)
+			}
+			defer pager.Close()
+			idx = OpenIndex(pager, root, "idx", "t", "c", types.TypeText, tt.collation, false)
+			checkTree(t, idx, reference)
+
+			// 删除剩下的所有条目
+			rng.Shuffle(len(reference), func(i, j int) { reference[i], reference[j] = reference[j], reference[i] })
+			for _, e := range reference {
+				if err := idx.Delete(e.key, e.rowID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			checkTree(t, idx, nil)
+		})
+	}
+}
+
+// randomKey 生成长度在 [minLen, maxLen] 中的键，字母表很小，短键会有很多重复
+func randomKey(rng *rand.Rand, minLen, maxLen int) string {
+	const alphabet = "abcABC"
+	var b strings.Builder
+	n := minLen + rng.Intn(maxLen-minLen+1)
+	for i := 0; i < n; i++ {
+		b.WriteByte(alphabet[rng.Intn(len(alphabet))])
+	}
+	return b.String()
+}
+
+// checkTree 检查按顺序遍历得到的条目和条目数与参照集合一致
+func checkTree(t *testing.T, idx *Index, reference []entry) {
+	t.Helper()
+	want := append([]entry(nil), reference...)
+	sort.Slice(want, func(i, j int) bool { return idx.tree.compare(want[i], want[j]) < 0 })
+
+	got := make([]entry, 0, len(want))
+	if err := idx.tree.ascend(nil, func(e entry) bool {
+		got = append(got, e)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ascend returned %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if describe(got[i]) != describe(want[i]) {
+			t.Fatalf("entry %d: got %s, want %s", i, describe(got[i]), describe(want[i]))
+		}
+	}
+
+	count, err := idx.GetCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != len(want) {
+		t.Fatalf("count = %d, want %d", count, len(want))
+	}
+}
+
+// describe 返回条目的可比较描述
+func describe(e entry) string {
+	return fmt.Sprintf("%s@%d:%d", e.key.String(), e.rowID.PageID, e.rowID.RowIndex)
+}
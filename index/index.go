@@ -1,101 +1,111 @@
 package index
 
 import (
+	"errors"
 	"fmt"
 	"godb/storage"
 	"godb/types"
 	"sync"
-
-	"github.com/google/btree"
 )
 
-// IndexEntry B-Tree 索引条目
-type IndexEntry struct {
-	Key   types.Value    // 索引键值
-	RowID storage.RowID  // 行 ID
-}
+// ErrDuplicateKey 唯一索引中插入了已存在的键
+var ErrDuplicateKey = errors.New("duplicate key")
 
-// Less 实现 btree.Item 接口
-func (e IndexEntry) Less(than btree.Item) bool {
-	other := than.(IndexEntry)
-
-	// 比较键值
-	switch e.Key.Type {
-	case types.TypeInt:
-		leftInt, _ := e.Key.AsInt()
-		rightInt, _ := other.Key.AsInt()
-		if leftInt != rightInt {
-			return leftInt < rightInt
-		}
-	case types.TypeText:
-		leftText, _ := e.Key.AsText()
-		rightText, _ := other.Key.AsText()
-		if leftText != rightText {
-			return leftText < rightText
-		}
-	case types.TypeFloat:
-		leftFloat, _ := e.Key.AsFloat()
-		rightFloat, _ := other.Key.AsFloat()
-		if leftFloat != rightFloat {
-			return leftFloat < rightFloat
-		}
-	case types.TypeDate:
-		leftDate, _ := e.Key.AsDate()
-		rightDate, _ := other.Key.AsDate()
-		if !leftDate.Equal(rightDate) {
-			return leftDate.Before(rightDate)
-		}
-	case types.TypeBoolean:
-		leftBool, _ := e.Key.AsBoolean()
-		rightBool, _ := other.Key.AsBoolean()
-		if leftBool != rightBool {
-			return !leftBool && rightBool
-		}
-	}
-
-	// 如果键值相等，比较 RowID（确保唯一性）
-	if e.RowID.PageID != other.RowID.PageID {
-		return e.RowID.PageID < other.RowID.PageID
-	}
-	return e.RowID.RowIndex < other.RowID.RowIndex
-}
-
-// Index B-Tree 索引
+// Index B+ 树索引，节点保存在表所在的 Pager 中
 type Index struct {
-	Name       string            // 索引名称
-	TableName  string            // 表名
-	ColumnName string            // 列名
-	ColumnType types.DataType    // 列类型
-	tree       *btree.BTree      // B-Tree
+	Name       string          // 索引名称
+	TableName  string          // 表名
+	ColumnName string          // 列名
+	ColumnType types.DataType  // 列类型
+	Collation  types.Collation // TEXT 列的排序规则
+	Unique     bool            // 是否为唯一索引
+	tree       *btree          // B+ 树
 	mu         sync.RWMutex
 }
 
-// NewIndex 创建新索引
-func NewIndex(name, tableName, columnName string, columnType types.DataType) *Index {
+// NewIndex 创建新索引，在 pager 中分配空的 B+ 树
+func NewIndex(pager *storage.Pager, name, tableName, columnName string, columnType types.DataType, collation types.Collation, unique bool) (*Index, error) {
+	tree, err := newBTree(pager, collation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate index %s: %w", name, err)
+	}
 	return &Index{
 		Name:       name,
 		TableName:  tableName,
 		ColumnName: columnName,
 		ColumnType: columnType,
-		tree:       btree.New(32), // 度数为 32
+		Collation:  collation,
+		Unique:     unique,
+		tree:       tree,
+	}, nil
+}
+
+// OpenIndex 打开已保存在 pager 中、根节点为 rootPageID 的索引
+func OpenIndex(pager *storage.Pager, rootPageID uint32, name, tableName, columnName string, columnType types.DataType, collation types.Collation, unique bool) *Index {
+	return &Index{
+		Name:       name,
+		TableName:  tableName,
+		ColumnName: columnName,
+		ColumnType: columnType,
+		Collation:  collation,
+		Unique:     unique,
+		tree:       &btree{pager: pager, root: rootPageID, collation: collation},
 	}
 }
 
-// Insert 插入索引条目
+// RootPageID 返回 B+ 树根节点的页 ID（保存在元数据中）
+func (idx *Index) RootPageID() uint32 {
+	return idx.tree.root
+}
+
+// Insert 插入索引条目，NULL 键不进入索引
 func (idx *Index) Insert(key types.Value, rowID storage.RowID) error {
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
+	if key.IsNull() {
+		return nil
+	}
+
 	if key.Type != idx.ColumnType {
 		return fmt.Errorf("key type mismatch: expected %s, got %s", idx.ColumnType, key.Type)
 	}
 
-	entry := IndexEntry{
-		Key:   key,
-		RowID: rowID,
+	// 唯一索引：同一个键只能对应一行
+	if idx.Unique {
+		duplicate := false
+		err := idx.tree.ascend(&entry{key: key}, func(other entry) bool {
+			if !idx.valuesEqual(other.key, key) {
+				return false
+			}
+			if other.rowID != rowID {
+				duplicate = true
+				return false
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		if duplicate {
+			return fmt.Errorf("%w: %s in unique index %s", ErrDuplicateKey, key.String(), idx.Name)
+		}
+	}
+
+	if err := idx.tree.insert(entry{key: key, rowID: rowID}); err != nil {
+		return fmt.Errorf("index %s: %w", idx.Name, err)
 	}
+	return nil
+}
 
-	idx.tree.ReplaceOrInsert(entry)
+// CheckKey 检查键能否写入索引（写入表之前检查，避免行已写入而索引条目写入失败）
+func (idx *Index) CheckKey(key types.Value) error {
+	if key.IsNull() {
+		return nil
+	}
+	if err := checkEntrySize(entry{key: key}); err != nil {
+		return fmt.Errorf("index %s: %w", idx.Name, err)
+	}
 	return nil
 }
 
@@ -104,13 +114,12 @@ func (idx *Index) Delete(key types.Value, rowID storage.RowID) error {
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
-	entry := IndexEntry{
-		Key:   key,
-		RowID: rowID,
+	if key.IsNull() || key.Type != idx.ColumnType {
+		return nil
 	}
 
-	idx.tree.Delete(entry)
-	return nil
+	_, err := idx.tree.delete(entry{key: key, rowID: rowID})
+	return err
 }
 
 // Search 等值查询
@@ -124,24 +133,19 @@ func (idx *Index) Search(key types.Value) ([]storage.RowID, error) {
 
 	result := make([]storage.RowID, 0)
 
-	// 创建查找的最小条目
-	searchEntry := IndexEntry{
-		Key:   key,
-		RowID: storage.RowID{PageID: 0, RowIndex: 0},
-	}
-
-	// 使用 AscendGreaterOrEqual 查找所有匹配的条目
-	idx.tree.AscendGreaterOrEqual(searchEntry, func(item btree.Item) bool {
-		entry := item.(IndexEntry)
-
+	// 从键值相等的最小条目开始查找所有匹配的条目
+	err := idx.tree.ascend(&entry{key: key}, func(e entry) bool {
 		// 检查键是否相等
-		if !valuesEqual(entry.Key, key) {
+		if !idx.valuesEqual(e.key, key) {
 			return false // 停止迭代
 		}
 
-		result = append(result, entry.RowID)
+		result = append(result, e.rowID)
 		return true // 继续迭代
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
@@ -157,19 +161,15 @@ func (idx *Index) RangeSearch(operator string, key types.Value) ([]storage.RowID
 	}
 
 	result := make([]storage.RowID, 0)
+	searchEntry := &entry{key: key}
 
-	searchEntry := IndexEntry{
-		Key:   key,
-		RowID: storage.RowID{PageID: 0, RowIndex: 0},
-	}
-
+	var err error
 	switch operator {
 	case "<":
 		// 从最小值开始，到 key 之前
-		idx.tree.Ascend(func(item btree.Item) bool {
-			entry := item.(IndexEntry)
-			if compareValues(entry.Key, key) < 0 {
-				result = append(result, entry.RowID)
+		err = idx.tree.ascend(nil, func(e entry) bool {
+			if idx.compareValues(e.key, key) < 0 {
+				result = append(result, e.rowID)
 				return true
 			}
 			return false
@@ -177,11 +177,9 @@ func (idx *Index) RangeSearch(operator string, key types.Value) ([]storage.RowID
 
 	case "<=":
 		// 从最小值开始，到 key（包含）
-		idx.tree.Ascend(func(item btree.Item) bool {
-			entry := item.(IndexEntry)
-			cmp := compareValues(entry.Key, key)
-			if cmp < 0 || cmp == 0 {
-				result = append(result, entry.RowID)
+		err = idx.tree.ascend(nil, func(e entry) bool {
+			if idx.compareValues(e.key, key) <= 0 {
+				result = append(result, e.rowID)
 				return true
 			}
 			return false
@@ -189,99 +187,60 @@ func (idx *Index) RangeSearch(operator string, key types.Value) ([]storage.RowID
 
 	case ">":
 		// 从 key 之后开始，到最大值
-		idx.tree.AscendGreaterOrEqual(searchEntry, func(item btree.Item) bool {
-			entry := item.(IndexEntry)
-			if compareValues(entry.Key, key) > 0 {
-				result = append(result, entry.RowID)
+		err = idx.tree.ascend(searchEntry, func(e entry) bool {
+			if idx.compareValues(e.key, key) > 0 {
+				result = append(result, e.rowID)
 			}
 			return true
 		})
 
 	case ">=":
 		// 从 key（包含）开始，到最大值
-		idx.tree.AscendGreaterOrEqual(searchEntry, func(item btree.Item) bool {
-			entry := item.(IndexEntry)
-			result = append(result, entry.RowID)
+		err = idx.tree.ascend(searchEntry, func(e entry) bool {
+			result = append(result, e.rowID)
 			return true
 		})
 
 	default:
 		return nil, fmt.Errorf("unsupported operator: %s", operator)
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
 
 // GetCount 获取索引条目数量
-func (idx *Index) GetCount() int {
+func (idx *Index) GetCount() (int, error) {
 	idx.mu.RLock()
 	defer idx.mu.RUnlock()
-	return idx.tree.Len()
+	return idx.tree.count()
 }
 
-// valuesEqual 判断两个值是否相等
-func valuesEqual(v1, v2 types.Value) bool {
-	return compareValues(v1, v2) == 0
+// PageIDs 返回索引占用的所有页 ID
+func (idx *Index) PageIDs() ([]uint32, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.tree.pageIDs()
 }
 
-// compareValues 比较两个值
-// 返回：-1 (v1 < v2), 0 (v1 == v2), 1 (v1 > v2)
-func compareValues(v1, v2 types.Value) int {
-	if v1.Type != v2.Type {
-		return 0
+// Free 释放索引占用的页（撤销或放弃新建的索引时使用），之后不能再使用该索引
+func (idx *Index) Free() {
+	pageIDs, err := idx.PageIDs()
+	if err != nil {
+		return
 	}
+	idx.tree.pager.FreePages(pageIDs)
+}
 
-	switch v1.Type {
-	case types.TypeInt:
-		left, _ := v1.AsInt()
-		right, _ := v2.AsInt()
-		if left < right {
-			return -1
-		} else if left > right {
-			return 1
-		}
-		return 0
-
-	case types.TypeText:
-		left, _ := v1.AsText()
-		right, _ := v2.AsText()
-		if left < right {
-			return -1
-		} else if left > right {
-			return 1
-		}
-		return 0
-
-	case types.TypeFloat:
-		left, _ := v1.AsFloat()
-		right, _ := v2.AsFloat()
-		if left < right {
-			return -1
-		} else if left > right {
-			return 1
-		}
-		return 0
-
-	case types.TypeDate:
-		left, _ := v1.AsDate()
-		right, _ := v2.AsDate()
-		if left.Before(right) {
-			return -1
-		} else if left.After(right) {
-			return 1
-		}
-		return 0
-
-	case types.TypeBoolean:
-		left, _ := v1.AsBoolean()
-		right, _ := v2.AsBoolean()
-		if !left && right {
-			return -1
-		} else if left && !right {
-			return 1
-		}
-		return 0
-	}
+// valuesEqual 判断两个值是否相等
+func (idx *Index) valuesEqual(v1, v2 types.Value) bool {
+	return idx.compareValues(v1, v2) == 0
+}
 
-	return 0
+// compareValues 按索引的排序规则比较两个值
+// 返回：-1 (v1 < v2), 0 (v1 == v2), 1 (v1 > v2)
+func (idx *Index) compareValues(v1, v2 types.Value) int {
+	return types.Compare(v1, v2, idx.Collation)
 }
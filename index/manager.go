@@ -20,22 +20,38 @@ func NewIndexManager() *IndexManager {
 	}
 }
 
-// CreateIndex 创建索引
-func (im *IndexManager) CreateIndex(name, tableName, columnName string, columnType types.DataType) error {
+// CreateIndex 创建索引，在 pager 中分配空的 B+ 树
+func (im *IndexManager) CreateIndex(pager *storage.Pager, name, tableName, columnName string, columnType types.DataType, collation types.Collation, unique bool) (*Index, error) {
 	im.mu.Lock()
 	defer im.mu.Unlock()
 
 	if _, exists := im.indexes[name]; exists {
-		return fmt.Errorf("index already exists: %s", name)
+		return nil, fmt.Errorf("index already exists: %s", name)
 	}
 
-	idx := NewIndex(name, tableName, columnName, columnType)
+	idx, err := NewIndex(pager, name, tableName, columnName, columnType, collation, unique)
+	if err != nil {
+		return nil, err
+	}
 	im.indexes[name] = idx
 
+	return idx, nil
+}
+
+// OpenIndex 加入已保存在 pager 中、根节点为 rootPageID 的索引（启动时使用，不读取表数据）
+func (im *IndexManager) OpenIndex(pager *storage.Pager, rootPageID uint32, name, tableName, columnName string, columnType types.DataType, collation types.Collation, unique bool) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if _, exists := im.indexes[name]; exists {
+		return fmt.Errorf("index already exists: %s", name)
+	}
+
+	im.indexes[name] = OpenIndex(pager, rootPageID, name, tableName, columnName, columnType, collation, unique)
 	return nil
 }
 
-// DropIndex 删除索引
+// DropIndex 删除索引（索引的页不释放，回滚事务中的删除时索引保持不变）
 func (im *IndexManager) DropIndex(name string) error {
 	im.mu.Lock()
 	defer im.mu.Unlock()
@@ -48,6 +64,70 @@ func (im *IndexManager) DropIndex(name string) error {
 	return nil
 }
 
+// DropIndexesByTable 删除表的所有索引，返回删除的索引
+func (im *IndexManager) DropIndexesByTable(tableName string) []*Index {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	dropped := make([]*Index, 0)
+	for name, idx := range im.indexes {
+		if idx.TableName == tableName {
+			delete(im.indexes, name)
+			dropped = append(dropped, idx)
+		}
+	}
+	return dropped
+}
+
+// ReplaceTableIndexes 用新构建的索引替换表的所有索引（ALTER TABLE 重写表之后使用）
+func (im *IndexManager) ReplaceTableIndexes(tableName string, indexes []*Index) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	for name, idx := range im.indexes {
+		if idx.TableName == tableName {
+			delete(im.indexes, name)
+		}
+	}
+	for _, idx := range indexes {
+		im.indexes[idx.Name] = idx
+	}
+}
+
+// RestoreIndexes 重新加入之前删除的索引（回滚事务中的 DROP TABLE 和 DROP INDEX）
+func (im *IndexManager) RestoreIndexes(indexes []*Index) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	for _, idx := range indexes {
+		im.indexes[idx.Name] = idx
+	}
+}
+
+// RenameTable 更新表重命名后索引中的表名
+func (im *IndexManager) RenameTable(oldName, newName string) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	for _, idx := range im.indexes {
+		if idx.TableName == oldName {
+			idx.TableName = newName
+		}
+	}
+}
+
+// RenameColumn 更新列重命名后索引中的列名
+func (im *IndexManager) RenameColumn(tableName, oldName, newName string) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	for _, idx := range im.indexes {
+		if idx.TableName == tableName && idx.ColumnName == oldName {
+			idx.ColumnName = newName
+		}
+	}
+}
+
 // GetIndex 获取索引
 func (im *IndexManager) GetIndex(name string) (*Index, error) {
 	im.mu.RLock()
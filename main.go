@@ -30,29 +30,37 @@ func main() {
 		fmt.Printf("Failed to load catalog: %v\n", err)
 		os.Exit(1)
 	}
+	defer catalogMgr.ReleaseSequenceReservations()
 
 	// 创建索引管理器
 	indexMgr := index.NewIndexManager()
 
-	// 从 catalog 重建索引
-	if err := rebuildIndexes(catalogMgr, indexMgr, pager); err != nil {
-		fmt.Printf("Failed to rebuild indexes: %v\n", err)
-		os.Exit(1)
-	}
-
 	// 创建事务管理器
 	txMgr := transaction.NewTransactionManager(pager, catalogMgr)
 
 	// 创建执行器
 	exec := executor.NewExecutor(catalogMgr, pager, indexMgr, txMgr)
+	// 会话结束时删除临时表
+	defer func() {
+		if err := exec.Close(); err != nil {
+			fmt.Printf("Failed to close session: %v\n", err)
+		}
+	}()
+
+	// 打开 catalog 中记录的索引
+	if err := loadIndexes(catalogMgr, indexMgr, pager, exec); err != nil {
+		fmt.Printf("Failed to load indexes: %v\n", err)
+		os.Exit(1)
+	}
 
 	// 启动 REPL
 	r := repl.NewREPL(exec, os.Stdin)
 	r.Start()
 }
 
-// rebuildIndexes 从 catalog 重建所有索引
-func rebuildIndexes(catalogMgr *catalog.Catalog, indexMgr *index.IndexManager, pager *storage.Pager) error {
+// loadIndexes 打开 catalog 中记录的所有索引：B+ 树已保存在数据文件中的索引直接从根节点页打开，不读取表数据
+// 没有根节点页 ID 的索引（旧版本的元数据或构建时中断）从表数据构建一次（VIRTUAL 生成列的值由执行器计算）
+func loadIndexes(catalogMgr *catalog.Catalog, indexMgr *index.IndexManager, pager *storage.Pager, exec *executor.Executor) error {
 	// 获取所有索引信息
 	indexNames := catalogMgr.ListIndexes()
 
@@ -62,8 +70,16 @@ func rebuildIndexes(catalogMgr *catalog.Catalog, indexMgr *index.IndexManager, p
 			return fmt.Errorf("failed to get index %s: %w", indexName, err)
 		}
 
+		if indexInfo.RootPageID != 0 {
+			if err := indexMgr.OpenIndex(pager, indexInfo.RootPageID, indexInfo.Name, indexInfo.TableName, indexInfo.ColumnName, indexInfo.ColumnType, indexInfo.Collation, indexInfo.Unique); err != nil {
+				return fmt.Errorf("failed to open index %s: %w", indexName, err)
+			}
+			continue
+		}
+
 		// 在索引管理器中创建索引
-		if err := indexMgr.CreateIndex(indexInfo.Name, indexInfo.TableName, indexInfo.ColumnName, indexInfo.ColumnType); err != nil {
+		idx, err := indexMgr.CreateIndex(pager, indexInfo.Name, indexInfo.TableName, indexInfo.ColumnName, indexInfo.ColumnType, indexInfo.Collation, indexInfo.Unique)
+		if err != nil {
 			return fmt.Errorf("failed to create index %s: %w", indexName, err)
 		}
 
@@ -89,11 +105,8 @@ func rebuildIndexes(catalogMgr *catalog.Catalog, indexMgr *index.IndexManager, p
 		if err != nil {
 			return fmt.Errorf("failed to get rows: %w", err)
 		}
-
-		// 获取索引
-		idx, err := indexMgr.GetIndex(indexInfo.Name)
-		if err != nil {
-			return fmt.Errorf("failed to get index: %w", err)
+		if err := exec.FillVirtualColumns(schema, rows); err != nil {
+			return fmt.Errorf("failed to compute virtual columns: %w", err)
 		}
 
 		// 为每一行插入索引条目
@@ -103,7 +116,12 @@ func rebuildIndexes(catalogMgr *catalog.Catalog, indexMgr *index.IndexManager, p
 			}
 		}
 
-		fmt.Printf("Rebuilt index '%s' with %d entries\n", indexName, len(rows))
+		// 记录根节点页 ID，之后启动时直接打开
+		if err := catalogMgr.SetIndexRootPage(indexName, idx.RootPageID()); err != nil {
+			return fmt.Errorf("failed to save index %s: %w", indexName, err)
+		}
+
+		fmt.Printf("Built index '%s' with %d entries\n", indexName, len(rows))
 	}
 
 	return nil
@@ -0,0 +1,222 @@
+package parser
+
+// AlterTableActionKind ALTER TABLE 子句类型
+type AlterTableActionKind int
+
+const (
+	AlterAddConstraint   AlterTableActionKind = iota // ADD [CONSTRAINT name] ...
+	AlterDropConstraint                              // DROP CONSTRAINT [IF EXISTS] name
+	AlterAddColumn                                   // ADD [COLUMN] [IF NOT EXISTS] column_def
+	AlterDropColumn                                  // DROP [COLUMN] [IF EXISTS] name
+	AlterRenameColumn                                // RENAME [COLUMN] name TO new_name
+	AlterRenameTable                                 // RENAME TO new_name
+	AlterColumnType                                  // ALTER [COLUMN] name [SET DATA] TYPE type [USING expr]
+	AlterAddPartition                                // ADD PARTITION (PARTITION name VALUES ..., ...)
+	AlterDropPartition                               // DROP PARTITION [IF EXISTS] name
+	AlterDetachPartition                             // DETACH PARTITION name
+)
+
+// AlterTableAction ALTER TABLE 中的一个子句
+type AlterTableAction struct {
+	Kind        AlterTableActionKind // 子句类型
+	Constraint  *TableConstraint     // ADD 添加的约束
+	Column      *ColumnDef           // ADD COLUMN 的列定义，ALTER COLUMN TYPE 的新类型（Name 为列名）
+	Name        string               // DROP CONSTRAINT 的约束名，DROP / RENAME COLUMN 的列名
+	NewName     string               // RENAME 的新名字
+	Using       string               // ALTER COLUMN TYPE 的 USING 表达式原文，空表示直接转换
+	IfExists    bool                 // 是否声明了 IF EXISTS
+	IfNotExists bool                 // 是否声明了 IF NOT EXISTS
+	Partitions  []*PartitionDef      // ADD PARTITION 添加的分区
+}
+
+// AlterTableStmt ALTER TABLE 语句
+type AlterTableStmt struct {
+	Table   string              // 表名
+	Actions []*AlterTableAction // 子句，按声明顺序执行
+}
+
+// ParseAlterTable 解析 ALTER TABLE 语句:
+//
+//	ALTER TABLE name action [, action ...]
+//
+// action 为:
+//
+//	ADD [CONSTRAINT name] CHECK (expr) 等表级约束
+//	DROP CONSTRAINT [IF EXISTS] name
+//	ADD [COLUMN] [IF NOT EXISTS] column_def
+//	DROP [COLUMN] [IF EXISTS] name
+//	RENAME [COLUMN] name TO new_name
+//	RENAME TO new_name
+//	ALTER [COLUMN] name [SET DATA] TYPE type [COLLATE collation] [USING expr]
+//	ADD PARTITION (PARTITION name VALUES LESS THAN (...) | VALUES IN (...), ...)
+//	DROP PARTITION [IF EXISTS] name
+//	DETACH PARTITION name
+func ParseAlterTable(sql string) (*AlterTableStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("ALTER", "TABLE"); err != nil {
+		return nil, err
+	}
+
+	stmt := &AlterTableStmt{}
+	stmt.Table, err = p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		action, err := p.parseAlterTableAction()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Actions = append(stmt.Actions, action)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// parseAlterTableAction 解析 ALTER TABLE 的一个子句
+func (p *ddlParser) parseAlterTableAction() (*AlterTableAction, error) {
+	switch {
+	case p.acceptKeywords("ADD", "PARTITION"):
+		// 分区定义是否与表的分区方式一致由执行器检查
+		partitions, err := p.parsePartitionDefs()
+		if err != nil {
+			return nil, err
+		}
+		return &AlterTableAction{Kind: AlterAddPartition, Partitions: partitions}, nil
+
+	case p.acceptKeywords("DROP", "PARTITION"):
+		action := &AlterTableAction{Kind: AlterDropPartition}
+		if p.acceptKeywords("IF", "EXISTS") {
+			action.IfExists = true
+		}
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		action.Name = name
+		return action, nil
+
+	case p.acceptKeywords("DETACH", "PARTITION"):
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		return &AlterTableAction{Kind: AlterDetachPartition, Name: name}, nil
+
+	case p.acceptKeywords("ADD"):
+		if p.isTableConstraint() {
+			constraint, err := p.parseTableConstraint()
+			if err != nil {
+				return nil, err
+			}
+			if constraint == nil {
+				return nil, p.errorf("unsupported table constraint")
+			}
+			return &AlterTableAction{Kind: AlterAddConstraint, Constraint: constraint}, nil
+		}
+
+		action := &AlterTableAction{Kind: AlterAddColumn}
+		p.acceptKeywords("COLUMN")
+		if p.acceptKeywords("IF", "NOT", "EXISTS") {
+			action.IfNotExists = true
+		}
+		col, err := p.parseColumnDef()
+		if err != nil {
+			return nil, err
+		}
+		action.Column = col
+		return action, nil
+
+	case p.acceptKeywords("DROP", "CONSTRAINT"):
+		action := &AlterTableAction{Kind: AlterDropConstraint}
+		if p.acceptKeywords("IF", "EXISTS") {
+			action.IfExists = true
+		}
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		action.Name = name
+		return action, nil
+
+	case p.acceptKeywords("DROP"):
+		action := &AlterTableAction{Kind: AlterDropColumn}
+		p.acceptKeywords("COLUMN")
+		if p.acceptKeywords("IF", "EXISTS") {
+			action.IfExists = true
+		}
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		action.Name = name
+		return action, nil
+
+	case p.acceptKeywords("RENAME", "TO"):
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		return &AlterTableAction{Kind: AlterRenameTable, NewName: name}, nil
+
+	case p.acceptKeywords("RENAME"):
+		p.acceptKeywords("COLUMN")
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeywords("TO"); err != nil {
+			return nil, err
+		}
+		newName, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		return &AlterTableAction{Kind: AlterRenameColumn, Name: name, NewName: newName}, nil
+
+	case p.acceptKeywords("ALTER"):
+		p.acceptKeywords("COLUMN")
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		p.acceptKeywords("SET", "DATA")
+		if err := p.expectKeywords("TYPE"); err != nil {
+			return nil, err
+		}
+
+		action := &AlterTableAction{Kind: AlterColumnType, Column: &ColumnDef{Name: name}}
+		if err := p.parseColumnType(action.Column); err != nil {
+			return nil, err
+		}
+		if p.acceptKeywords("COLLATE") {
+			action.Column.Collation, err = p.parseName()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if p.acceptKeywords("USING") {
+			action.Using, err = p.parseExprTextUntil(false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return action, nil
+
+	default:
+		return nil, p.errorf("expected ADD, DROP, DETACH, RENAME or ALTER")
+	}
+}
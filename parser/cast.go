@@ -0,0 +1,234 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// CastFunc 类型转换改写后使用的内部函数名
+// sqlparser 只认识 MySQL 的 CAST 目标类型（SIGNED、CHAR 等），也不支持 PostgreSQL 的 :: 写法，
+// 所以 CAST(x AS type) 和 x::type 在解析前统一改写为 godb_cast(x, 'type')
+const CastFunc = "godb_cast"
+
+// rewriteCasts 把 CAST(x AS type) 和 x::type 改写为 godb_cast(x, 'type')
+func rewriteCasts(sql string) (string, error) {
+	for {
+		tokens, err := tokenize(sql)
+		if err != nil {
+			// 交给 sqlparser 报告语法错误
+			return sql, nil
+		}
+
+		rewritten, changed, err := rewriteFirstCast(sql, tokens)
+		if err != nil {
+			return "", err
+		}
+		if !changed {
+			return sql, nil
+		}
+		sql = rewritten
+	}
+}
+
+// rewriteFirstCast 改写第一个类型转换，返回是否发生了改写
+func rewriteFirstCast(sql string, tokens []token) (string, bool, error) {
+	for i, tok := range tokens {
+		// CAST(x AS type)
+		if tok.kind == tokIdent && !tok.quoted && strings.EqualFold(tok.text, "CAST") &&
+			i+1 < len(tokens) && tokens[i+1].kind == tokSymbol && tokens[i+1].text == "(" {
+			closeIdx := matchingParen(tokens, i+1)
+			if closeIdx == -1 {
+				return "", false, fmt.Errorf("syntax error at position %d: unbalanced parentheses in CAST", tok.pos)
+			}
+
+			// 找到同层的 AS
+			asIdx := -1
+			depth := 0
+			for j := i + 2; j < closeIdx; j++ {
+				t := tokens[j]
+				if t.kind == tokSymbol && t.text == "(" {
+					depth++
+				} else if t.kind == tokSymbol && t.text == ")" {
+					depth--
+				} else if depth == 0 && t.kind == tokIdent && !t.quoted && strings.EqualFold(t.text, "AS") {
+					asIdx = j
+				}
+			}
+			if asIdx == -1 || asIdx == i+2 || asIdx+1 == closeIdx {
+				return "", false, fmt.Errorf("syntax error at position %d: expected CAST(expr AS type)", tok.pos)
+			}
+
+			operand := sql[tokens[i+2].pos:tokens[asIdx-1].end]
+			typeName := sql[tokens[asIdx+1].pos:tokens[closeIdx-1].end]
+			return replaceCast(sql, tok.pos, tokens[closeIdx].end, operand, typeName), true, nil
+		}
+
+		// x::type
+		if tok.kind == tokSymbol && tok.text == "::" {
+			start := castOperandStart(tokens, i)
+			if start == -1 {
+				return "", false, fmt.Errorf("syntax error at position %d: missing operand before '::'", tok.pos)
+			}
+
+			if i+1 >= len(tokens) || tokens[i+1].kind != tokIdent {
+				return "", false, fmt.Errorf("syntax error at position %d: expected type after '::'", tok.pos)
+			}
+			typeEnd := i + 1
+			// 类型参数，如 ::varchar(10)
+			if typeEnd+1 < len(tokens) && tokens[typeEnd+1].kind == tokSymbol && tokens[typeEnd+1].text == "(" {
+				closeIdx := matchingParen(tokens, typeEnd+1)
+				if closeIdx == -1 {
+					return "", false, fmt.Errorf("syntax error at position %d: unbalanced parentheses", tok.pos)
+				}
+				typeEnd = closeIdx
+			}
+
+			operand := sql[tokens[start].pos:tokens[i-1].end]
+			typeName := sql[tokens[i+1].pos:tokens[typeEnd].end]
+			return replaceCast(sql, tokens[start].pos, tokens[typeEnd].end, operand, typeName), true, nil
+		}
+	}
+
+	return sql, false, nil
+}
+
+// ParseCastType 解析类型转换的目标类型，写法与列定义中的类型相同: type[(n[, m])] [UNSIGNED]
+// 另外接受 MySQL 的 SIGNED [INTEGER]、UNSIGNED [INTEGER] 和 DOUBLE PRECISION
+func ParseCastType(text string) (*ColumnDef, error) {
+	p, err := newDDLParser(text)
+	if err != nil {
+		return nil, err
+	}
+
+	col := &ColumnDef{}
+	if err := p.parseColumnType(col); err != nil {
+		return nil, err
+	}
+	switch col.Type {
+	case "SIGNED", "UNSIGNED":
+		if !p.acceptKeywords("INTEGER") {
+			p.acceptKeywords("INT")
+		}
+	case "DOUBLE":
+		p.acceptKeywords("PRECISION")
+	}
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+	return col, nil
+}
+
+// castOperandStart 找到 :: 左侧操作数的第一个词法单元
+// 操作数可以是字面量、（带限定名的）列名、括号表达式或函数调用
+func castOperandStart(tokens []token, castIdx int) int {
+	if castIdx == 0 {
+		return -1
+	}
+
+	prev := castIdx - 1
+	tok := tokens[prev]
+
+	switch {
+	case tok.kind == tokSymbol && tok.text == ")":
+		openIdx := matchingOpenParen(tokens, prev)
+		if openIdx == -1 {
+			return -1
+		}
+		// 函数调用（括号前是关键字时是括号表达式，如 SELECT (a + 1)::float）
+		if openIdx > 0 && tokens[openIdx-1].kind == tokIdent && (tokens[openIdx-1].quoted || !castKeywords[strings.ToUpper(tokens[openIdx-1].text)]) {
+			return openIdx - 1
+		}
+		return openIdx
+
+	case tok.kind == tokIdent:
+		// 限定列名，如 t.col
+		start := prev
+		for start >= 2 && tokens[start-1].kind == tokSymbol && tokens[start-1].text == "." && tokens[start-2].kind == tokIdent {
+			start -= 2
+		}
+		return start
+
+	case tok.kind == tokString || tok.kind == tokNumber:
+		return prev
+
+	default:
+		return -1
+	}
+}
+
+// castKeywords 可以出现在括号表达式之前、但不是函数名的关键字
+var castKeywords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "WHERE": true, "HAVING": true, "ON": true, "BY": true,
+	"AND": true, "OR": true, "XOR": true, "NOT": true, "IS": true, "IN": true,
+	"LIKE": true, "REGEXP": true, "BETWEEN": true, "DIV": true,
+	"CASE": true, "WHEN": true, "THEN": true, "ELSE": true,
+	"AS": true, "SET": true, "DEFAULT": true, "LIMIT": true, "OFFSET": true,
+}
+
+// matchingParen 返回与 openIdx 处 '(' 匹配的 ')' 下标
+func matchingParen(tokens []token, openIdx int) int {
+	depth := 0
+	for j := openIdx; j < len(tokens); j++ {
+		if tokens[j].kind != tokSymbol {
+			continue
+		}
+		switch tokens[j].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// matchingOpenParen 返回与 closeIdx 处 ')' 匹配的 '(' 下标
+func matchingOpenParen(tokens []token, closeIdx int) int {
+	depth := 0
+	for j := closeIdx; j >= 0; j-- {
+		if tokens[j].kind != tokSymbol {
+			continue
+		}
+		switch tokens[j].text {
+		case ")":
+			depth++
+		case "(":
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// replaceCast 用 godb_cast(operand, 'type') 替换 sql[start:end]
+func replaceCast(sql string, start, end int, operand, typeName string) string {
+	typeName = strings.Join(strings.Fields(typeName), " ")
+	return fmt.Sprintf("%s%s(%s, '%s')%s", sql[:start], CastFunc, operand, typeName, sql[end:])
+}
+
+// String 把语法树节点格式化为 SQL，并把内部的 godb_cast 还原为 CAST(x AS type)
+func String(node sqlparser.SQLNode) string {
+	buf := sqlparser.NewTrackedBuffer(formatNode)
+	buf.Myprintf("%v", node)
+	return buf.String()
+}
+
+// formatNode 自定义节点格式化
+func formatNode(buf *sqlparser.TrackedBuffer, node sqlparser.SQLNode) {
+	if funcExpr, ok := node.(*sqlparser.FuncExpr); ok && funcExpr.Name.Lowered() == CastFunc && len(funcExpr.Exprs) == 2 {
+		if typeExpr, ok := funcExpr.Exprs[1].(*sqlparser.AliasedExpr); ok {
+			if typeVal, ok := typeExpr.Expr.(*sqlparser.SQLVal); ok && typeVal.Type == sqlparser.StrVal {
+				buf.Myprintf("cast(%v as %s)", funcExpr.Exprs[0], string(typeVal.Val))
+				return
+			}
+		}
+	}
+	node.Format(buf)
+}
@@ -0,0 +1,28 @@
+package parser
+
+import "testing"
+
+// 关键字后面的括号是括号表达式，不是函数调用
+func TestRewriteCastsParenthesizedOperand(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT (id + 1)::FLOAT FROM t", "SELECT godb_cast((id + 1), 'FLOAT') FROM t"},
+		{"SELECT id FROM t WHERE (id * 2)::FLOAT > 3.5", "SELECT id FROM t WHERE godb_cast((id * 2), 'FLOAT') > 3.5"},
+		{"SELECT id FROM t WHERE a = 1 AND (b)::INT = 2 OR (c)::INT = 3", "SELECT id FROM t WHERE a = 1 AND godb_cast((b), 'INT') = 2 OR godb_cast((c), 'INT') = 3"},
+		{"SELECT id FROM t WHERE NOT (id)::BOOLEAN", "SELECT id FROM t WHERE NOT godb_cast((id), 'BOOLEAN')"},
+		{"SELECT CASE WHEN (a)::INT = 1 THEN (b)::TEXT ELSE (c)::TEXT END FROM t", "SELECT CASE WHEN godb_cast((a), 'INT') = 1 THEN godb_cast((b), 'TEXT') ELSE godb_cast((c), 'TEXT') END FROM t"},
+		{"SELECT lower(name)::VARCHAR(3) FROM t", "SELECT godb_cast(lower(name), 'VARCHAR(3)') FROM t"},
+		{"SELECT `select`(x)::INT FROM t", "SELECT godb_cast(`select`(x), 'INT') FROM t"},
+	}
+	for _, tt := range tests {
+		got, err := rewriteCasts(tt.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,556 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnDef CREATE TABLE 中的列定义
+type ColumnDef struct {
+	Name       string // 列名
+	Type       string // 类型名（大写）
+	Length     int    // 类型长度修饰，如 VARCHAR(10)，0 表示未声明
+	Unsigned   bool   // 是否声明了 UNSIGNED
+	Collation  string // COLLATE 声明的排序规则，空表示未声明
+	PrimaryKey bool   // 是否声明了列级 PRIMARY KEY
+	Unique     bool   // 是否声明了列级 UNIQUE
+	NotNull    bool   // 是否声明了 NOT NULL
+	Default    string // DEFAULT 表达式的原文，空表示未声明
+
+	AutoIncrement bool // 是否声明了 AUTO_INCREMENT（SERIAL 类型由执行器处理）
+
+	Generated string // GENERATED ALWAYS AS (expr) 的表达式原文（不含外层括号），空表示不是生成列
+	Stored    bool   // 生成列是否声明了 STORED（默认为 VIRTUAL）
+
+	ForeignKey *TableConstraint   // 列级 REFERENCES 声明的外键，nil 表示未声明
+	Checks     []*TableConstraint // 列级 CHECK 约束
+}
+
+// ConstraintKind 表级约束类型
+type ConstraintKind int
+
+const (
+	ConstraintPrimaryKey ConstraintKind = iota // PRIMARY KEY
+	ConstraintUnique                           // UNIQUE
+	ConstraintForeignKey                       // FOREIGN KEY
+	ConstraintCheck                            // CHECK
+)
+
+// TableConstraint 表级约束（列级约束也会转换为表级约束）
+type TableConstraint struct {
+	Name    string         // CONSTRAINT 声明的约束名，可以为空
+	Kind    ConstraintKind // 约束类型
+	Columns []string       // 约束涉及的列
+
+	// 以下字段只用于 FOREIGN KEY
+	RefTable          string   // 被引用的表
+	RefColumns        []string // 被引用的列，为空表示被引用表的主键
+	OnDelete          string   // ON DELETE 动作（大写，如 CASCADE、SET NULL），空表示 NO ACTION
+	OnUpdate          string   // ON UPDATE 动作，空表示 NO ACTION
+	Deferrable        bool     // 是否声明了 DEFERRABLE
+	InitiallyDeferred bool     // 是否声明了 INITIALLY DEFERRED
+
+	Check string // CHECK 约束的表达式原文（不含外层括号）
+}
+
+// CreateTableStmt CREATE TABLE 语句
+type CreateTableStmt struct {
+	Table       string             // 表名
+	IfNotExists bool               // 是否声明了 IF NOT EXISTS
+	Columns     []*ColumnDef       // 列定义
+	Constraints []*TableConstraint // 约束
+
+	Temporary bool   // 是否声明了 TEMPORARY（或 TEMP）
+	OnCommit  string // 临时表的 ON COMMIT 动作：PRESERVE ROWS、DELETE ROWS 或 DROP，空表示未声明
+
+	Partition *PartitionSpec // PARTITION BY 子句，nil 表示不分区
+}
+
+// tableConstraintKeywords 表级约束的起始关键字
+var tableConstraintKeywords = []string{"PRIMARY", "KEY", "INDEX", "UNIQUE", "CONSTRAINT", "FOREIGN", "CHECK", "FULLTEXT"}
+
+// columnOptionKeywords 列选项的起始关键字，用于确定 DEFAULT 表达式的结束位置
+var columnOptionKeywords = []string{
+	"NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "KEY", "COLLATE", "CHECK", "REFERENCES",
+	"CONSTRAINT", "AUTO_INCREMENT", "AUTOINCREMENT", "GENERATED", "AS", "ON", "COMMENT", "CHARACTER", "CHARSET",
+}
+
+// ParseCreateTable 解析 CREATE TABLE 语句
+// sqlparser 只认识 MySQL 的列类型（不支持 BOOLEAN 等），所以 CREATE TABLE 使用自己的解析器
+func ParseCreateTable(sql string) (*CreateTableStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("CREATE"); err != nil {
+		return nil, err
+	}
+	stmt := &CreateTableStmt{}
+	if p.acceptKeywords("TEMPORARY") || p.acceptKeywords("TEMP") {
+		stmt.Temporary = true
+	}
+	if err := p.expectKeywords("TABLE"); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeywords("IF", "NOT", "EXISTS") {
+		stmt.IfNotExists = true
+	}
+
+	stmt.Table, err = p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.isTableConstraint() {
+			constraint, err := p.parseTableConstraint()
+			if err != nil {
+				return nil, err
+			}
+			if constraint != nil {
+				stmt.Constraints = append(stmt.Constraints, constraint)
+			}
+		} else {
+			col, err := p.parseColumnDef()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Columns = append(stmt.Columns, col)
+			stmt.Constraints = append(stmt.Constraints, col.Constraints()...)
+		}
+
+		if p.acceptSymbol(",") {
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	// PARTITION BY { RANGE | LIST | HASH } (col) ...
+	if p.acceptKeywords("PARTITION", "BY") {
+		stmt.Partition, err = p.parsePartitionBy()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// ON COMMIT { PRESERVE ROWS | DELETE ROWS | DROP }
+	if p.acceptKeywords("ON", "COMMIT") {
+		switch {
+		case p.acceptKeywords("PRESERVE", "ROWS"):
+			stmt.OnCommit = "PRESERVE ROWS"
+		case p.acceptKeywords("DELETE", "ROWS"):
+			stmt.OnCommit = "DELETE ROWS"
+		case p.acceptKeywords("DROP"):
+			stmt.OnCommit = "DROP"
+		default:
+			return nil, p.errorf("expected PRESERVE ROWS, DELETE ROWS or DROP")
+		}
+		if !stmt.Temporary {
+			return nil, p.errorf("ON COMMIT can only be used on temporary tables")
+		}
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// Constraints 把列级约束转换为表级约束
+func (col *ColumnDef) Constraints() []*TableConstraint {
+	constraints := make([]*TableConstraint, 0)
+	if col.PrimaryKey {
+		constraints = append(constraints, &TableConstraint{
+			Kind:    ConstraintPrimaryKey,
+			Columns: []string{col.Name},
+		})
+	}
+	if col.Unique {
+		constraints = append(constraints, &TableConstraint{
+			Kind:    ConstraintUnique,
+			Columns: []string{col.Name},
+		})
+	}
+	if col.ForeignKey != nil {
+		col.ForeignKey.Columns = []string{col.Name}
+		constraints = append(constraints, col.ForeignKey)
+	}
+	for _, check := range col.Checks {
+		check.Columns = []string{col.Name}
+		constraints = append(constraints, check)
+	}
+	return constraints
+}
+
+// isTableConstraint 判断当前元素是否是表级约束
+func (p *ddlParser) isTableConstraint() bool {
+	for _, keyword := range tableConstraintKeywords {
+		if p.isKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTableConstraint 解析表级约束:
+//
+//	[CONSTRAINT name] PRIMARY KEY (col, ...)
+//	[CONSTRAINT name] UNIQUE [KEY | INDEX] [name] (col, ...)
+//	[CONSTRAINT name] FOREIGN KEY (col, ...) REFERENCES table [(col, ...)] [actions...]
+//	[CONSTRAINT name] CHECK (expr)
+//
+// 暂不支持的约束返回 nil
+func (p *ddlParser) parseTableConstraint() (*TableConstraint, error) {
+	constraint := &TableConstraint{}
+	if p.acceptKeywords("CONSTRAINT") {
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		constraint.Name = name
+	}
+
+	switch {
+	case p.acceptKeywords("PRIMARY", "KEY"):
+		constraint.Kind = ConstraintPrimaryKey
+	case p.acceptKeywords("UNIQUE"):
+		constraint.Kind = ConstraintUnique
+		if !p.acceptKeywords("KEY") {
+			p.acceptKeywords("INDEX")
+		}
+		// MySQL 写法: UNIQUE KEY name (col)
+		if !p.isSymbol("(") {
+			name, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			if constraint.Name == "" {
+				constraint.Name = name
+			}
+		}
+	case p.acceptKeywords("FOREIGN", "KEY"):
+		constraint.Kind = ConstraintForeignKey
+		// MySQL 写法: FOREIGN KEY name (col)
+		if !p.isSymbol("(") {
+			name, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			if constraint.Name == "" {
+				constraint.Name = name
+			}
+		}
+		columns, err := p.parseColumnList()
+		if err != nil {
+			return nil, err
+		}
+		constraint.Columns = columns
+		if err := p.expectKeywords("REFERENCES"); err != nil {
+			return nil, err
+		}
+		if err := p.parseReferences(constraint); err != nil {
+			return nil, err
+		}
+		return constraint, nil
+	case p.acceptKeywords("CHECK"):
+		constraint.Kind = ConstraintCheck
+		check, err := p.parseParenExprText()
+		if err != nil {
+			return nil, err
+		}
+		constraint.Check = check
+		return constraint, nil
+	default:
+		// 其余表级约束暂不支持，忽略
+		p.skipUntilSeparator()
+		return nil, nil
+	}
+
+	columns, err := p.parseColumnList()
+	if err != nil {
+		return nil, err
+	}
+	constraint.Columns = columns
+
+	return constraint, nil
+}
+
+// parseColumnList 解析括号中的列名列表: (col, ...)
+func (p *ddlParser) parseColumnList() ([]string, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0)
+	for {
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+
+		if p.acceptSymbol(",") {
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return columns, nil
+	}
+}
+
+// parseColumnDef 解析列定义: name type[(n[, m])] [UNSIGNED] [options...]
+func (p *ddlParser) parseColumnDef() (*ColumnDef, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	col := &ColumnDef{Name: name}
+	if err := p.parseColumnType(col); err != nil {
+		return nil, err
+	}
+
+	// 列选项
+	constraintName := "" // CONSTRAINT name 声明的约束名，用于紧随其后的约束
+	for !p.isSymbol(",") && !p.isSymbol(")") && p.peek().kind != tokEOF {
+		switch {
+		case p.acceptKeywords("CONSTRAINT"):
+			constraintName, err = p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case p.acceptKeywords("COLLATE"):
+			col.Collation, err = p.parseName()
+			if err != nil {
+				return nil, err
+			}
+		case p.acceptKeywords("NOT", "NULL"):
+			col.NotNull = true
+		case p.acceptKeywords("NULL"):
+			col.NotNull = false
+		case p.acceptKeywords("DEFAULT"):
+			col.Default, err = p.parseExprText()
+			if err != nil {
+				return nil, err
+			}
+		case p.acceptKeywords("AUTO_INCREMENT"), p.acceptKeywords("AUTOINCREMENT"):
+			col.AutoIncrement = true
+		case p.acceptKeywords("GENERATED", "ALWAYS", "AS"), p.acceptKeywords("AS"):
+			col.Generated, err = p.parseParenExprText()
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case p.acceptKeywords("STORED"):
+				col.Stored = true
+			case p.acceptKeywords("VIRTUAL"):
+				col.Stored = false
+			}
+		case p.acceptKeywords("PRIMARY", "KEY"):
+			col.PrimaryKey = true
+		case p.acceptKeywords("UNIQUE"):
+			p.acceptKeywords("KEY")
+			col.Unique = true
+		case p.acceptKeywords("REFERENCES"):
+			col.ForeignKey = &TableConstraint{Name: constraintName, Kind: ConstraintForeignKey}
+			if err := p.parseReferences(col.ForeignKey); err != nil {
+				return nil, err
+			}
+		case p.acceptKeywords("CHECK"):
+			check, err := p.parseParenExprText()
+			if err != nil {
+				return nil, err
+			}
+			col.Checks = append(col.Checks, &TableConstraint{Name: constraintName, Kind: ConstraintCheck, Check: check})
+		case p.acceptKeywords("CHARACTER", "SET"), p.acceptKeywords("CHARSET"):
+			// 只支持 UTF-8，字符集声明忽略
+			if _, err := p.parseName(); err != nil {
+				return nil, err
+			}
+		default:
+			// 其余列约束暂不支持，忽略
+			p.skipToken()
+		}
+		constraintName = ""
+	}
+
+	return col, nil
+}
+
+// parseColumnType 解析列类型: type[(n[, m])] [UNSIGNED] [ZEROFILL]
+func (p *ddlParser) parseColumnType(col *ColumnDef) error {
+	typeName, err := p.parseIdent()
+	if err != nil {
+		return err
+	}
+	col.Type = strings.ToUpper(typeName)
+
+	// 类型参数，如 VARCHAR(10) 或 DECIMAL(10, 2)
+	if p.acceptSymbol("(") {
+		col.Length, err = p.parseInt()
+		if err != nil {
+			return err
+		}
+		// 长度 0 表示未声明，不能作为声明的长度
+		if col.Length <= 0 {
+			return fmt.Errorf("length for type %s must be at least 1", col.Type)
+		}
+		if p.acceptSymbol(",") {
+			if _, err := p.parseInt(); err != nil {
+				return err
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return err
+		}
+	}
+
+	if p.acceptKeywords("UNSIGNED") {
+		col.Unsigned = true
+	}
+	p.acceptKeywords("ZEROFILL")
+	return nil
+}
+
+// parseReferences 解析 REFERENCES 之后的部分:
+//
+//	table [(col, ...)] [MATCH FULL | PARTIAL | SIMPLE]
+//	[ON DELETE action] [ON UPDATE action]
+//	[[NOT] DEFERRABLE] [INITIALLY DEFERRED | INITIALLY IMMEDIATE]
+//
+// action 为 RESTRICT、CASCADE、SET NULL、SET DEFAULT 或 NO ACTION
+func (p *ddlParser) parseReferences(constraint *TableConstraint) error {
+	refTable, err := p.parseQualifiedName()
+	if err != nil {
+		return err
+	}
+	constraint.RefTable = refTable
+
+	if p.isSymbol("(") {
+		constraint.RefColumns, err = p.parseColumnList()
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		switch {
+		case p.acceptKeywords("MATCH"):
+			// 只支持单列外键，MATCH 选项没有区别
+			if _, err := p.parseIdent(); err != nil {
+				return err
+			}
+		case p.acceptKeywords("ON", "DELETE"):
+			constraint.OnDelete, err = p.parseReferentialAction()
+			if err != nil {
+				return err
+			}
+		case p.acceptKeywords("ON", "UPDATE"):
+			constraint.OnUpdate, err = p.parseReferentialAction()
+			if err != nil {
+				return err
+			}
+		case p.acceptKeywords("NOT", "DEFERRABLE"):
+			constraint.Deferrable = false
+		case p.acceptKeywords("DEFERRABLE"):
+			constraint.Deferrable = true
+		case p.acceptKeywords("INITIALLY", "DEFERRED"):
+			constraint.InitiallyDeferred = true
+		case p.acceptKeywords("INITIALLY", "IMMEDIATE"):
+			constraint.InitiallyDeferred = false
+		default:
+			return nil
+		}
+	}
+}
+
+// parseReferentialAction 解析外键的引用动作
+func (p *ddlParser) parseReferentialAction() (string, error) {
+	switch {
+	case p.acceptKeywords("RESTRICT"):
+		return "RESTRICT", nil
+	case p.acceptKeywords("CASCADE"):
+		return "CASCADE", nil
+	case p.acceptKeywords("SET", "NULL"):
+		return "SET NULL", nil
+	case p.acceptKeywords("SET", "DEFAULT"):
+		return "SET DEFAULT", nil
+	case p.acceptKeywords("NO", "ACTION"):
+		return "NO ACTION", nil
+	default:
+		return "", p.errorf("expected RESTRICT, CASCADE, SET NULL, SET DEFAULT or NO ACTION")
+	}
+}
+
+// parseParenExprText 读取括号中的表达式（如 CHECK (expr)），返回不含外层括号的原文
+func (p *ddlParser) parseParenExprText() (string, error) {
+	if !p.isSymbol("(") {
+		return "", p.errorf("expected '('")
+	}
+	closeIdx := matchingParen(p.tokens, p.pos)
+	if closeIdx == -1 {
+		return "", p.errorf("unbalanced parentheses")
+	}
+	if closeIdx == p.pos+1 {
+		return "", p.errorf("expected expression")
+	}
+
+	text := p.sql[p.tokens[p.pos+1].pos:p.tokens[closeIdx-1].end]
+	p.pos = closeIdx + 1
+	return strings.TrimSpace(text), nil
+}
+
+// parseExprText 读取列选项中的表达式（如 DEFAULT 的值），返回表达式原文
+// 表达式在同层的 ',' 或 ')' 或下一个列选项关键字处结束
+func (p *ddlParser) parseExprText() (string, error) {
+	return p.parseExprTextUntil(true)
+}
+
+// parseExprTextUntil 读取表达式原文，表达式在同层的 ',' 或 ')' 处结束
+// stopAtOptions 为 true 时也在列选项关键字处结束
+func (p *ddlParser) parseExprTextUntil(stopAtOptions bool) (string, error) {
+	if p.isSymbol(",") || p.isSymbol(")") || p.peek().kind == tokEOF {
+		return "", p.errorf("expected expression")
+	}
+
+	start := p.peek().pos
+	end := start
+	for first := true; ; first = false {
+		if !first && (p.isSymbol(",") || p.isSymbol(")") || p.isSymbol(";") || p.peek().kind == tokEOF || (stopAtOptions && p.isColumnOption())) {
+			break
+		}
+		p.skipToken()
+		end = p.tokens[p.pos-1].end
+	}
+	return p.sql[start:end], nil
+}
+
+// isColumnOption 判断当前词法单元是否是列选项的起始关键字
+func (p *ddlParser) isColumnOption() bool {
+	for _, keyword := range columnOptionKeywords {
+		if p.isKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseName 解析标识符或字符串形式的名字（如 COLLATE 'nocase'）
+func (p *ddlParser) parseName() (string, error) {
+	if p.peek().kind == tokString {
+		return p.parseString()
+	}
+	return p.parseIdent()
+}
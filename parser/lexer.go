@@ -0,0 +1,311 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokIdent            // 标识符或关键字
+	tokNumber           // 数字
+	tokString           // 字符串字面量
+	tokSymbol           // 运算符和标点
+)
+
+// token 词法单元
+type token struct {
+	kind   tokenKind
+	text   string // 标识符原文、数字、去掉引号的字符串内容或符号
+	quoted bool   // 标识符是否使用了引号（引号标识符不会被当作关键字）
+	pos    int    // 在原 SQL 中的起始偏移
+	end    int    // 在原 SQL 中的结束偏移
+}
+
+// tokenize 将 SQL 切分为词法单元
+// 只用于 sqlparser 不支持的 DDL 语句，表达式部分仍交给 sqlparser 解析
+func tokenize(sql string) ([]token, error) {
+	tokens := make([]token, 0)
+	i := 0
+
+	for i < len(sql) {
+		ch := sql[i]
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+
+		case ch == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			// 单行注释
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+
+		case isIdentStart(ch):
+			start := i
+			for i < len(sql) && isIdentChar(sql[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: sql[start:i], pos: start, end: i})
+
+		case ch >= '0' && ch <= '9':
+			start := i
+			for i < len(sql) && (sql[i] >= '0' && sql[i] <= '9' || sql[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: sql[start:i], pos: start, end: i})
+
+		case ch == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(sql) {
+				if sql[i] == '\'' {
+					// '' 表示转义的单引号
+					if i+1 < len(sql) && sql[i+1] == '\'' {
+						sb.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					closed = true
+					break
+				}
+				sb.WriteByte(sql[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			tokens = append(tokens, token{kind: tokString, text: sb.String(), pos: start, end: i})
+
+		case ch == '`' || ch == '"':
+			start := i
+			end := strings.IndexByte(sql[i+1:], ch)
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated identifier at position %d", start)
+			}
+			i += end + 2
+			tokens = append(tokens, token{kind: tokIdent, text: sql[start+1 : i-1], quoted: true, pos: start, end: i})
+
+		default:
+			start := i
+			// 双字符运算符
+			if i+1 < len(sql) {
+				two := sql[i : i+2]
+				switch two {
+				case "::", "<=", ">=", "!=", "<>":
+					i += 2
+					tokens = append(tokens, token{kind: tokSymbol, text: two, pos: start, end: i})
+					continue
+				}
+			}
+			i++
+			tokens = append(tokens, token{kind: tokSymbol, text: string(ch), pos: start, end: i})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(sql), end: len(sql)})
+	return tokens, nil
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch >= 0x80
+}
+
+func isIdentChar(ch byte) bool {
+	return isIdentStart(ch) || (ch >= '0' && ch <= '9') || ch == '$'
+}
+
+// ddlParser 基于词法单元的递归下降解析器
+type ddlParser struct {
+	sql    string
+	tokens []token
+	pos    int
+}
+
+// newDDLParser 创建解析器
+func newDDLParser(sql string) (*ddlParser, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	return &ddlParser{sql: sql, tokens: tokens}, nil
+}
+
+// peek 查看当前词法单元
+func (p *ddlParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next 读取并前进到下一个词法单元
+func (p *ddlParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword 判断当前词法单元是否是指定关键字
+func (p *ddlParser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && !tok.quoted && strings.EqualFold(tok.text, keyword)
+}
+
+// acceptKeywords 如果接下来的词法单元依次匹配关键字则消费它们
+func (p *ddlParser) acceptKeywords(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		tok := p.tokens[p.pos+i]
+		if tok.kind != tokIdent || tok.quoted || !strings.EqualFold(tok.text, keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+// expectKeywords 消费指定关键字序列，不匹配时报错
+func (p *ddlParser) expectKeywords(keywords ...string) error {
+	if !p.acceptKeywords(keywords...) {
+		return p.errorf("expected %s", strings.Join(keywords, " "))
+	}
+	return nil
+}
+
+// isSymbol 判断当前词法单元是否是指定符号
+func (p *ddlParser) isSymbol(symbol string) bool {
+	tok := p.peek()
+	return tok.kind == tokSymbol && tok.text == symbol
+}
+
+// acceptSymbol 如果当前词法单元是指定符号则消费它
+func (p *ddlParser) acceptSymbol(symbol string) bool {
+	if p.isSymbol(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expectSymbol 消费指定符号，不匹配时报错
+func (p *ddlParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.errorf("expected '%s'", symbol)
+	}
+	return nil
+}
+
+// parseIdent 解析标识符
+func (p *ddlParser) parseIdent() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokIdent {
+		return "", p.errorf("expected identifier")
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+// parseQualifiedName 解析可以带模式限定的名字: [schema.]name
+func (p *ddlParser) parseQualifiedName() (string, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return "", err
+	}
+	if p.acceptSymbol(".") {
+		base, err := p.parseIdent()
+		if err != nil {
+			return "", err
+		}
+		name += "." + base
+	}
+	return name, nil
+}
+
+// parseString 解析字符串字面量
+func (p *ddlParser) parseString() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokString {
+		return "", p.errorf("expected string literal")
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+// parseInt 解析整数
+func (p *ddlParser) parseInt() (int, error) {
+	tok := p.peek()
+	if tok.kind != tokNumber {
+		return 0, p.errorf("expected number")
+	}
+	var n int
+	if _, err := fmt.Sscanf(tok.text, "%d", &n); err != nil {
+		return 0, p.errorf("invalid number")
+	}
+	p.pos++
+	return n, nil
+}
+
+// skipUntilSeparator 跳过当前元素直到同层的 ',' 或 ')'
+func (p *ddlParser) skipUntilSeparator() {
+	depth := 0
+	for {
+		tok := p.peek()
+		if tok.kind == tokEOF {
+			return
+		}
+		if tok.kind == tokSymbol {
+			switch tok.text {
+			case "(":
+				depth++
+			case ")":
+				if depth == 0 {
+					return
+				}
+				depth--
+			case ",":
+				if depth == 0 {
+					return
+				}
+			}
+		}
+		p.pos++
+	}
+}
+
+// skipToken 跳过一个词法单元，遇到 '(' 时跳过整个括号
+func (p *ddlParser) skipToken() {
+	if p.isSymbol("(") {
+		if closeIdx := matchingParen(p.tokens, p.pos); closeIdx != -1 {
+			p.pos = closeIdx + 1
+			return
+		}
+	}
+	p.next()
+}
+
+// expectEnd 检查语句已结束（允许结尾的分号）
+func (p *ddlParser) expectEnd() error {
+	p.acceptSymbol(";")
+	if p.peek().kind != tokEOF {
+		return p.errorf("unexpected trailing input")
+	}
+	return nil
+}
+
+// errorf 生成带位置的语法错误
+func (p *ddlParser) errorf(format string, args ...interface{}) error {
+	tok := p.peek()
+	near := tok.text
+	if tok.kind == tokEOF {
+		near = "end of input"
+	}
+	return fmt.Errorf("syntax error at position %d near '%s': %s", tok.pos, near, fmt.Sprintf(format, args...))
+}
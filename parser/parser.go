@@ -2,11 +2,19 @@ package parser
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/xwb1989/sqlparser"
 )
 
 // Parse 解析 SQL 语句
 func Parse(sql string) (sqlparser.Statement, error) {
+	sql, err := rewriteCasts(sql)
+	if err != nil {
+		return nil, err
+	}
+	sql = quoteCollations(sql)
+
 	stmt, err := sqlparser.Parse(sql)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse SQL: %w", err)
@@ -14,6 +22,45 @@ func Parse(sql string) (sqlparser.Statement, error) {
 	return stmt, nil
 }
 
+// ParseExpr 解析单个表达式（如列的 DEFAULT 表达式）
+func ParseExpr(text string) (sqlparser.Expr, error) {
+	stmt, err := Parse("SELECT " + text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", text, err)
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || len(sel.SelectExprs) != 1 {
+		return nil, fmt.Errorf("invalid expression %q", text)
+	}
+	aliased, ok := sel.SelectExprs[0].(*sqlparser.AliasedExpr)
+	if !ok {
+		return nil, fmt.Errorf("invalid expression %q", text)
+	}
+	return aliased.Expr, nil
+}
+
+// quoteCollations 给 COLLATE 后面的排序规则名加上引号
+// sqlparser 把 BINARY 当作关键字，COLLATE binary 无法解析
+func quoteCollations(sql string) string {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return sql
+	}
+
+	// 从后往前替换，避免偏移变化
+	for i := len(tokens) - 2; i >= 0; i-- {
+		tok, name := tokens[i], tokens[i+1]
+		if tok.kind != tokIdent || tok.quoted || !strings.EqualFold(tok.text, "COLLATE") {
+			continue
+		}
+		if name.kind != tokIdent || name.quoted {
+			continue
+		}
+		sql = sql[:name.pos] + "`" + name.text + "`" + sql[name.end:]
+	}
+	return sql
+}
+
 // StatementType 返回语句类型
 func StatementType(stmt sqlparser.Statement) string {
 	switch stmt.(type) {
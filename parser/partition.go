@@ -0,0 +1,183 @@
+package parser
+
+import "strings"
+
+// PartitionDef 分区的定义
+type PartitionDef struct {
+	Name     string   // 分区名
+	Range    bool     // 是否是 RANGE 分区的定义（VALUES LESS THAN），否则为 LIST 分区的定义（VALUES IN）
+	LessThan string   // RANGE 分区的上界（不含）原文，MaxValue 为 true 时为空
+	MaxValue bool     // RANGE 分区是否声明了 VALUES LESS THAN (MAXVALUE)
+	Values   []string // LIST 分区的值原文
+	HasNull  bool     // LIST 分区的值中是否有 NULL
+}
+
+// PartitionSpec PARTITION BY 子句
+type PartitionSpec struct {
+	Method     string          // 分区方式：RANGE、LIST 或 HASH
+	Column     string          // 分区键
+	Count      int             // HASH 分区的分区数
+	Partitions []*PartitionDef // RANGE 和 LIST 分区的定义（按声明顺序）
+}
+
+// parsePartitionBy 解析 PARTITION BY 子句（PARTITION BY 已消费）:
+//
+//	RANGE (col) (PARTITION name VALUES LESS THAN (value | MAXVALUE), ...)
+//	LIST (col) (PARTITION name VALUES IN (value, ...), ...)
+//	HASH (col) PARTITIONS n
+func (p *ddlParser) parsePartitionBy() (*PartitionSpec, error) {
+	spec := &PartitionSpec{}
+	switch {
+	case p.acceptKeywords("RANGE"):
+		spec.Method = "RANGE"
+	case p.acceptKeywords("LIST"):
+		spec.Method = "LIST"
+	case p.acceptKeywords("HASH"):
+		spec.Method = "HASH"
+	default:
+		return nil, p.errorf("expected RANGE, LIST or HASH")
+	}
+
+	columns, err := p.parseColumnList()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) != 1 {
+		return nil, p.errorf("partition key must be a single column")
+	}
+	spec.Column = columns[0]
+
+	if spec.Method == "HASH" {
+		if err := p.expectKeywords("PARTITIONS"); err != nil {
+			return nil, err
+		}
+		if spec.Count, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+		if spec.Count <= 0 {
+			return nil, p.errorf("number of partitions must be positive")
+		}
+		return spec, nil
+	}
+
+	spec.Partitions, err = p.parsePartitionDefs()
+	if err != nil {
+		return nil, err
+	}
+	for _, partition := range spec.Partitions {
+		if partition.Range != (spec.Method == "RANGE") {
+			return nil, p.errorf("partition %s does not match partitioning method %s", partition.Name, spec.Method)
+		}
+	}
+	return spec, nil
+}
+
+// parsePartitionDefs 解析括号中的分区定义列表: (PARTITION name VALUES ..., ...)
+func (p *ddlParser) parsePartitionDefs() ([]*PartitionDef, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	partitions := make([]*PartitionDef, 0)
+	for {
+		partition, err := p.parsePartitionDef()
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, partition)
+
+		if p.acceptSymbol(",") {
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return partitions, nil
+	}
+}
+
+// parsePartitionDef 解析一个分区定义:
+//
+//	PARTITION name VALUES LESS THAN (value | MAXVALUE)
+//	PARTITION name VALUES IN (value, ...)
+func (p *ddlParser) parsePartitionDef() (*PartitionDef, error) {
+	if err := p.expectKeywords("PARTITION"); err != nil {
+		return nil, err
+	}
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	partition := &PartitionDef{Name: name}
+	if err := p.expectKeywords("VALUES"); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeywords("LESS", "THAN") {
+		partition.Range = true
+		if p.acceptKeywords("MAXVALUE") {
+			partition.MaxValue = true
+			return partition, nil
+		}
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		if p.acceptKeywords("MAXVALUE") {
+			partition.MaxValue = true
+		} else if partition.LessThan, err = p.parsePartitionValue(); err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return partition, nil
+	}
+
+	if err := p.expectKeywords("IN"); err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	for {
+		if p.acceptKeywords("NULL") {
+			partition.HasNull = true
+		} else {
+			value, err := p.parsePartitionValue()
+			if err != nil {
+				return nil, err
+			}
+			partition.Values = append(partition.Values, value)
+		}
+
+		if p.acceptSymbol(",") {
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return partition, nil
+	}
+}
+
+// parsePartitionValue 解析分区边界的值：字符串、数字（可以带负号）、TRUE 或 FALSE，返回值的文本
+func (p *ddlParser) parsePartitionValue() (string, error) {
+	sign := ""
+	if p.acceptSymbol("-") {
+		sign = "-"
+	}
+	tok := p.peek()
+	switch {
+	case tok.kind == tokNumber:
+		p.pos++
+		return sign + tok.text, nil
+	case sign != "":
+		return "", p.errorf("expected number")
+	case tok.kind == tokString:
+		p.pos++
+		return tok.text, nil
+	case tok.kind == tokIdent && !tok.quoted && (strings.EqualFold(tok.text, "TRUE") || strings.EqualFold(tok.text, "FALSE")):
+		p.pos++
+		return strings.ToLower(tok.text), nil
+	}
+	return "", p.errorf("expected partition bound value")
+}
@@ -0,0 +1,139 @@
+package parser
+
+import "strconv"
+
+// CreateSequenceStmt CREATE SEQUENCE 语句，未声明的选项为 nil
+type CreateSequenceStmt struct {
+	Name        string // 序列名
+	IfNotExists bool   // 是否声明了 IF NOT EXISTS
+	Increment   *int64 // INCREMENT BY
+	MinValue    *int64 // MINVALUE
+	MaxValue    *int64 // MAXVALUE
+	Start       *int64 // START WITH
+	Cycle       bool   // 是否声明了 CYCLE
+}
+
+// DropSequenceStmt DROP SEQUENCE 语句
+type DropSequenceStmt struct {
+	Name     string // 序列名
+	IfExists bool   // 是否声明了 IF EXISTS
+}
+
+// ParseCreateSequence 解析 CREATE SEQUENCE 语句:
+//
+//	CREATE SEQUENCE [IF NOT EXISTS] name
+//	  [INCREMENT [BY] n] [MINVALUE n | NO MINVALUE] [MAXVALUE n | NO MAXVALUE]
+//	  [START [WITH] n] [CACHE n] [[NO] CYCLE]
+func ParseCreateSequence(sql string) (*CreateSequenceStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("CREATE", "SEQUENCE"); err != nil {
+		return nil, err
+	}
+
+	stmt := &CreateSequenceStmt{}
+	if p.acceptKeywords("IF", "NOT", "EXISTS") {
+		stmt.IfNotExists = true
+	}
+
+	stmt.Name, err = p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var target **int64
+		switch {
+		case p.acceptKeywords("INCREMENT"):
+			p.acceptKeywords("BY")
+			target = &stmt.Increment
+		case p.acceptKeywords("MINVALUE"):
+			target = &stmt.MinValue
+		case p.acceptKeywords("MAXVALUE"):
+			target = &stmt.MaxValue
+		case p.acceptKeywords("START"):
+			p.acceptKeywords("WITH")
+			target = &stmt.Start
+		case p.acceptKeywords("CACHE"):
+			// 每次分配都会按固定的批量持久化，CACHE 只检查语法
+			if _, err := p.parseInt64(); err != nil {
+				return nil, err
+			}
+			continue
+		case p.acceptKeywords("NO", "MINVALUE"):
+			stmt.MinValue = nil
+			continue
+		case p.acceptKeywords("NO", "MAXVALUE"):
+			stmt.MaxValue = nil
+			continue
+		case p.acceptKeywords("NO", "CYCLE"):
+			stmt.Cycle = false
+			continue
+		case p.acceptKeywords("CYCLE"):
+			stmt.Cycle = true
+			continue
+		default:
+			if err := p.expectEnd(); err != nil {
+				return nil, err
+			}
+			return stmt, nil
+		}
+
+		value, err := p.parseInt64()
+		if err != nil {
+			return nil, err
+		}
+		*target = &value
+	}
+}
+
+// ParseDropSequence 解析 DROP SEQUENCE 语句: DROP SEQUENCE [IF EXISTS] name
+func ParseDropSequence(sql string) (*DropSequenceStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("DROP", "SEQUENCE"); err != nil {
+		return nil, err
+	}
+
+	stmt := &DropSequenceStmt{}
+	if p.acceptKeywords("IF", "EXISTS") {
+		stmt.IfExists = true
+	}
+
+	stmt.Name, err = p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseInt64 解析可以带符号的 64 位整数
+func (p *ddlParser) parseInt64() (int64, error) {
+	sign := ""
+	if p.acceptSymbol("-") {
+		sign = "-"
+	} else {
+		p.acceptSymbol("+")
+	}
+
+	tok := p.peek()
+	if tok.kind != tokNumber {
+		return 0, p.errorf("expected number")
+	}
+	n, err := strconv.ParseInt(sign+tok.text, 10, 64)
+	if err != nil {
+		return 0, p.errorf("invalid number")
+	}
+	p.pos++
+	return n, nil
+}
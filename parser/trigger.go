@@ -0,0 +1,166 @@
+package parser
+
+import "strings"
+
+// CreateTriggerStmt CREATE TRIGGER 语句
+type CreateTriggerStmt struct {
+	Name   string   // 触发器名
+	Timing string   // BEFORE 或 AFTER
+	Event  string   // INSERT、UPDATE 或 DELETE
+	Table  string   // 表名
+	Body   []string // 触发器体中的语句原文
+}
+
+// DropTriggerStmt DROP TRIGGER 语句
+type DropTriggerStmt struct {
+	Name     string // 触发器名
+	IfExists bool   // 是否声明了 IF EXISTS
+}
+
+// ParseCreateTrigger 解析 CREATE TRIGGER 语句:
+//
+//	CREATE TRIGGER name { BEFORE | AFTER } { INSERT | UPDATE | DELETE } ON table FOR EACH ROW
+//	    { statement | BEGIN statement; [statement; ...] END }
+func ParseCreateTrigger(sql string) (*CreateTriggerStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("CREATE", "TRIGGER"); err != nil {
+		return nil, err
+	}
+	stmt := &CreateTriggerStmt{}
+	if stmt.Name, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.acceptKeywords("BEFORE"):
+		stmt.Timing = "BEFORE"
+	case p.acceptKeywords("AFTER"):
+		stmt.Timing = "AFTER"
+	default:
+		return nil, p.errorf("expected BEFORE or AFTER")
+	}
+	switch {
+	case p.acceptKeywords("INSERT"):
+		stmt.Event = "INSERT"
+	case p.acceptKeywords("UPDATE"):
+		stmt.Event = "UPDATE"
+	case p.acceptKeywords("DELETE"):
+		stmt.Event = "DELETE"
+	default:
+		return nil, p.errorf("expected INSERT, UPDATE or DELETE")
+	}
+
+	if err := p.expectKeywords("ON"); err != nil {
+		return nil, err
+	}
+	if stmt.Table, err = p.parseQualifiedName(); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeywords("FOR", "EACH", "ROW"); err != nil {
+		return nil, err
+	}
+
+	if stmt.Body, err = p.parseTriggerBody(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseTriggerBody 解析触发器体，按分号切分为语句原文
+// BEGIN ... END 中的语句可能包含 CASE ... END，只有最后一个词法单元是结束触发器体的 END
+func (p *ddlParser) parseTriggerBody() ([]string, error) {
+	end := len(p.tokens) - 1 // EOF
+	if end > p.pos && p.tokens[end-1].kind == tokSymbol && p.tokens[end-1].text == ";" {
+		end--
+	}
+
+	if p.acceptKeywords("BEGIN") {
+		last := p.tokens[end-1]
+		if end <= p.pos || last.kind != tokIdent || last.quoted || !strings.EqualFold(last.text, "END") {
+			return nil, p.errorf("expected END at the end of the trigger body")
+		}
+		end--
+	}
+
+	body := make([]string, 0)
+	start := p.pos
+	for i := p.pos; i <= end; i++ {
+		if i < end && (p.tokens[i].kind != tokSymbol || p.tokens[i].text != ";") {
+			continue
+		}
+		if i > start {
+			body = append(body, strings.TrimSpace(p.sql[p.tokens[start].pos:p.tokens[i-1].end]))
+		}
+		start = i + 1
+	}
+	if len(body) == 0 {
+		return nil, p.errorf("trigger body is empty")
+	}
+	p.pos = len(p.tokens) - 1
+	return body, nil
+}
+
+// ParseDropTrigger 解析 DROP TRIGGER 语句: DROP TRIGGER [IF EXISTS] name
+func ParseDropTrigger(sql string) (*DropTriggerStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("DROP", "TRIGGER"); err != nil {
+		return nil, err
+	}
+	stmt := &DropTriggerStmt{}
+	if p.acceptKeywords("IF", "EXISTS") {
+		stmt.IfExists = true
+	}
+	if stmt.Name, err = p.parseQualifiedName(); err != nil {
+		return nil, err
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// ReplaceRowReferences 把语句中的 NEW.column 和 OLD.column 替换为 replace 返回的文本（执行触发器体之前代入行的值）
+// 字符串字面量和引号标识符中的内容不会被替换
+func ReplaceRowReferences(sql string, replace func(row, column string) (string, error)) (string, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	last := 0
+	for i := 0; i+2 < len(tokens); i++ {
+		row, dot, column := tokens[i], tokens[i+1], tokens[i+2]
+		if row.kind != tokIdent || row.quoted || dot.kind != tokSymbol || dot.text != "." || column.kind != tokIdent {
+			continue
+		}
+		name := strings.ToUpper(row.text)
+		if name != "NEW" && name != "OLD" {
+			continue
+		}
+		// 前面是 . 时是 schema.table.column 中的表名，不是行引用
+		if i > 0 && tokens[i-1].kind == tokSymbol && tokens[i-1].text == "." {
+			continue
+		}
+
+		text, err := replace(name, column.text)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(sql[last:row.pos])
+		sb.WriteString(text)
+		last = column.end
+		i += 2
+	}
+	sb.WriteString(sql[last:])
+	return sb.String(), nil
+}
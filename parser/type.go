@@ -0,0 +1,137 @@
+package parser
+
+// CreateTypeStmt CREATE TYPE ... AS ENUM 语句
+type CreateTypeStmt struct {
+	Name   string   // 类型名
+	Labels []string // 枚举标签
+}
+
+// AlterTypeStmt ALTER TYPE ... ADD VALUE 语句
+type AlterTypeStmt struct {
+	Name        string // 类型名
+	Label       string // 新增的标签
+	IfNotExists bool   // 是否声明了 IF NOT EXISTS
+}
+
+// DropTypeStmt DROP TYPE 语句
+type DropTypeStmt struct {
+	Name     string // 类型名
+	IfExists bool   // 是否声明了 IF EXISTS
+}
+
+// ParseCreateType 解析 CREATE TYPE name AS ENUM ('label', ...)
+func ParseCreateType(sql string) (*CreateTypeStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("CREATE", "TYPE"); err != nil {
+		return nil, err
+	}
+
+	stmt := &CreateTypeStmt{}
+	stmt.Name, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("AS", "ENUM"); err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	stmt.Labels = make([]string, 0)
+	if !p.isSymbol(")") {
+		for {
+			label, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Labels = append(stmt.Labels, label)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// ParseAlterType 解析 ALTER TYPE name ADD VALUE [IF NOT EXISTS] 'label'
+func ParseAlterType(sql string) (*AlterTypeStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("ALTER", "TYPE"); err != nil {
+		return nil, err
+	}
+
+	stmt := &AlterTypeStmt{}
+	stmt.Name, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("ADD", "VALUE"); err != nil {
+		return nil, err
+	}
+	if p.acceptKeywords("IF", "NOT", "EXISTS") {
+		stmt.IfNotExists = true
+	}
+
+	stmt.Label, err = p.parseString()
+	if err != nil {
+		return nil, err
+	}
+
+	// 存储的是标签序号，插入到中间会改变已有数据的含义
+	if p.isKeyword("BEFORE") || p.isKeyword("AFTER") {
+		return nil, p.errorf("BEFORE/AFTER is not supported, new labels are always appended")
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// ParseDropType 解析 DROP TYPE [IF EXISTS] name
+func ParseDropType(sql string) (*DropTypeStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("DROP", "TYPE"); err != nil {
+		return nil, err
+	}
+
+	stmt := &DropTypeStmt{}
+	if p.acceptKeywords("IF", "EXISTS") {
+		stmt.IfExists = true
+	}
+
+	stmt.Name, err = p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
@@ -0,0 +1,132 @@
+package parser
+
+import "strings"
+
+// CreateViewStmt CREATE VIEW 语句
+type CreateViewStmt struct {
+	Name         string   // 视图名
+	OrReplace    bool     // 是否声明了 OR REPLACE
+	Materialized bool     // 是否为物化视图
+	IfNotExists  bool     // 是否声明了 IF NOT EXISTS（只用于物化视图）
+	Columns      []string // 声明的列名，空表示使用查询结果的列名
+	Query        string   // AS 之后的 SELECT 语句原文
+}
+
+// DropViewStmt DROP [MATERIALIZED] VIEW 语句
+type DropViewStmt struct {
+	Name         string // 视图名
+	Materialized bool   // 是否为物化视图
+	IfExists     bool   // 是否声明了 IF EXISTS
+}
+
+// RefreshMaterializedViewStmt REFRESH MATERIALIZED VIEW 语句
+type RefreshMaterializedViewStmt struct {
+	Name string // 物化视图名
+}
+
+// ParseCreateView 解析 CREATE VIEW 语句:
+//
+//	CREATE [OR REPLACE] VIEW name [(column, ...)] AS SELECT ...
+//	CREATE MATERIALIZED VIEW [IF NOT EXISTS] name [(column, ...)] AS SELECT ...
+func ParseCreateView(sql string) (*CreateViewStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("CREATE"); err != nil {
+		return nil, err
+	}
+	stmt := &CreateViewStmt{}
+	if p.acceptKeywords("OR", "REPLACE") {
+		stmt.OrReplace = true
+	} else if p.acceptKeywords("MATERIALIZED") {
+		stmt.Materialized = true
+	}
+	if err := p.expectKeywords("VIEW"); err != nil {
+		return nil, err
+	}
+	if stmt.Materialized && p.acceptKeywords("IF", "NOT", "EXISTS") {
+		stmt.IfNotExists = true
+	}
+
+	stmt.Name, err = p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isSymbol("(") {
+		stmt.Columns, err = p.parseColumnList()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectKeywords("AS"); err != nil {
+		return nil, err
+	}
+	if !p.isKeyword("SELECT") {
+		return nil, p.errorf("expected SELECT")
+	}
+
+	// 查询部分交给 sqlparser 解析，这里只保存原文
+	query := strings.TrimSpace(p.sql[p.peek().pos:])
+	stmt.Query = strings.TrimSpace(strings.TrimSuffix(query, ";"))
+	return stmt, nil
+}
+
+// ParseDropView 解析 DROP VIEW 语句: DROP [MATERIALIZED] VIEW [IF EXISTS] name
+func ParseDropView(sql string) (*DropViewStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("DROP"); err != nil {
+		return nil, err
+	}
+	stmt := &DropViewStmt{}
+	if p.acceptKeywords("MATERIALIZED") {
+		stmt.Materialized = true
+	}
+	if err := p.expectKeywords("VIEW"); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeywords("IF", "EXISTS") {
+		stmt.IfExists = true
+	}
+
+	stmt.Name, err = p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// ParseRefreshMaterializedView 解析 REFRESH MATERIALIZED VIEW 语句: REFRESH MATERIALIZED VIEW name
+func ParseRefreshMaterializedView(sql string) (*RefreshMaterializedViewStmt, error) {
+	p, err := newDDLParser(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeywords("REFRESH", "MATERIALIZED", "VIEW"); err != nil {
+		return nil, err
+	}
+
+	stmt := &RefreshMaterializedViewStmt{}
+	stmt.Name, err = p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
@@ -17,6 +17,8 @@ type PageType uint8
 const (
 	PageTypeTable PageType = iota // 表数据页
 	PageTypeMeta                   // 元数据页
+	PageTypeIndexInternal          // B+ 树索引的内部节点页
+	PageTypeIndexLeaf              // B+ 树索引的叶子节点页
 )
 
 // Page 数据页结构
@@ -11,6 +11,7 @@ type Pager struct {
 	file      *os.File
 	numPages  uint32
 	pageCache map[uint32]*Page // 简单的页缓存
+	freePages []uint32         // 已释放可以重新分配的页（只保存在内存中，重启后不再复用）
 	mu        sync.RWMutex
 }
 
@@ -37,6 +38,11 @@ func NewPager(filename string) (*Pager, error) {
 	}, nil
 }
 
+// FileName 返回数据库文件名
+func (p *Pager) FileName() string {
+	return p.file.Name()
+}
+
 // Close 关闭页管理器
 func (p *Pager) Close() error {
 	p.mu.Lock()
@@ -94,6 +100,17 @@ func (p *Pager) AllocatePage(pageType PageType) (*Page, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	// 优先复用已释放的页
+	if n := len(p.freePages); n > 0 {
+		page := NewPage(p.freePages[n-1], pageType)
+		if err := p.writePageToDisk(page); err != nil {
+			return nil, err
+		}
+		p.freePages = p.freePages[:n-1]
+		p.pageCache[page.ID] = page
+		return page, nil
+	}
+
 	pageID := p.numPages
 	page := NewPage(pageID, pageType)
 
@@ -109,6 +126,19 @@ func (p *Pager) AllocatePage(pageType PageType) (*Page, error) {
 	return page, nil
 }
 
+// FreePages 释放不再被任何表引用的页（撤销事务中创建的表时使用），之后分配页时复用
+func (p *Pager) FreePages(pageIDs []uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, pageID := range pageIDs {
+		// NextPage 为 0 表示没有下一页，页 0 不能作为后续页复用
+		if pageID != 0 {
+			p.freePages = append(p.freePages, pageID)
+		}
+	}
+}
+
 // FlushPage 刷新页到磁盘
 func (p *Pager) FlushPage(pageID uint32) error {
 	p.mu.Lock()
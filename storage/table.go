@@ -109,6 +109,11 @@ type TableStorage struct {
 	pager       *Pager
 	firstPageID uint32 // 第一个数据页的 ID
 	numColumns  int    // 列数
+
+	// 分区表每个分区有自己的页链，插入的行由 route 选择分区
+	partitions []uint32                    // 各分区第一个数据页的 ID，nil 表示不分区
+	scanned    []int                       // 读取的分区（分区裁剪后的结果），nil 表示全部分区
+	route      func(row *Row) (int, error) // 返回行所属的分区
 }
 
 // NewTableStorage 创建表存储
@@ -135,7 +140,40 @@ func LoadTableStorage(pager *Pager, firstPageID uint32, numColumns int) *TableSt
 	}
 }
 
-// InsertRow 插入行
+// LoadPartitionedTableStorage 加载分区表的存储，firstPageIDs 为各分区第一个数据页的 ID
+func LoadPartitionedTableStorage(pager *Pager, firstPageIDs []uint32, numColumns int, route func(row *Row) (int, error)) *TableStorage {
+	return &TableStorage{
+		pager:       pager,
+		firstPageID: firstPageIDs[0],
+		numColumns:  numColumns,
+		partitions:  firstPageIDs,
+		route:       route,
+	}
+}
+
+// Prune 返回只读取指定分区的表存储（插入的行仍按分区规则写入对应的分区）
+func (t *TableStorage) Prune(partitions []int) *TableStorage {
+	pruned := *t
+	pruned.scanned = partitions
+	return &pruned
+}
+
+// chains 返回要读取的页链的第一页 ID
+func (t *TableStorage) chains() []uint32 {
+	if t.partitions == nil {
+		return []uint32{t.firstPageID}
+	}
+	if t.scanned == nil {
+		return t.partitions
+	}
+	chains := make([]uint32, len(t.scanned))
+	for i, partition := range t.scanned {
+		chains[i] = t.partitions[partition]
+	}
+	return chains
+}
+
+// InsertRow 插入行（分区表插入到行所属分区的页链中）
 func (t *TableStorage) InsertRow(row *Row) error {
 	if len(row.Values) != t.numColumns {
 		return fmt.Errorf("column count mismatch: expected %d, got %d", t.numColumns, len(row.Values))
@@ -149,6 +187,13 @@ func (t *TableStorage) InsertRow(row *Row) error {
 
 	// 找到可以插入的页
 	currentPageID := t.firstPageID
+	if t.partitions != nil {
+		partition, err := t.route(row)
+		if err != nil {
+			return err
+		}
+		currentPageID = t.partitions[partition]
+	}
 	for {
 		page, err := t.pager.GetPage(currentPageID)
 		if err != nil {
@@ -194,7 +239,21 @@ func (t *TableStorage) GetAllRows() ([]*Row, error) {
 func (t *TableStorage) GetAllRowsWithDeleted(includeDeleted bool) ([]*Row, error) {
 	rows := make([]*Row, 0)
 
-	currentPageID := t.firstPageID
+	for _, firstPageID := range t.chains() {
+		chainRows, err := t.chainRows(firstPageID, includeDeleted)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, chainRows...)
+	}
+	return rows, nil
+}
+
+// chainRows 读取一个页链中的行
+func (t *TableStorage) chainRows(firstPageID uint32, includeDeleted bool) ([]*Row, error) {
+	rows := make([]*Row, 0)
+
+	currentPageID := firstPageID
 	for {
 		page, err := t.pager.GetPage(currentPageID)
 		if err != nil {
@@ -235,6 +294,38 @@ func (t *TableStorage) GetAllRowsWithDeleted(includeDeleted bool) ([]*Row, error
 	return rows, nil
 }
 
+// GetPageIDs 返回表的所有数据页 ID（按链表顺序，分区表依次为各分区的页）
+func (t *TableStorage) GetPageIDs() ([]uint32, error) {
+	pageIDs := make([]uint32, 0)
+	for _, firstPageID := range t.chains() {
+		chainPageIDs, err := t.chainPageIDs(firstPageID)
+		if err != nil {
+			return nil, err
+		}
+		pageIDs = append(pageIDs, chainPageIDs...)
+	}
+	return pageIDs, nil
+}
+
+// chainPageIDs 返回一个页链的所有页 ID
+func (t *TableStorage) chainPageIDs(firstPageID uint32) ([]uint32, error) {
+	pageIDs := make([]uint32, 0)
+	currentPageID := firstPageID
+	for {
+		page, err := t.pager.GetPage(currentPageID)
+		if err != nil {
+			return nil, err
+		}
+		pageIDs = append(pageIDs, currentPageID)
+
+		if page.NextPage == 0 {
+			break
+		}
+		currentPageID = page.NextPage
+	}
+	return pageIDs, nil
+}
+
 // GetFirstPageID 获取第一页 ID
 func (t *TableStorage) GetFirstPageID() uint32 {
 	return t.firstPageID
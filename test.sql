@@ -1,4 +1,4 @@
-CREATE TABLE users (id INT, name TEXT, age INT, active TINYINT)
+CREATE TABLE users (id INT, name TEXT, age INT, active BOOLEAN)
 INSERT INTO users VALUES (1, 'Alice', 25, 'true')
 INSERT INTO users VALUES (2, 'Bob', 30, 'true')
 INSERT INTO users VALUES (3, 'Charlie', 35, 'false')
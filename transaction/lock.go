@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -155,3 +156,46 @@ func (lm *LockManager) ReleaseTableLock(table string, txID TransactionID) {
 		lock.writer = 0
 	}
 }
+
+// String 返回锁类型名
+func (t LockType) String() string {
+	if t == WriteLock {
+		return "WRITE"
+	}
+	return "READ"
+}
+
+// LockInfo 事务持有的一个表锁
+type LockInfo struct {
+	Table string        // 表名
+	TxID  TransactionID // 持有锁的事务
+	Type  LockType      // 锁类型（持有写锁时不再单独列出读锁）
+}
+
+// ListLocks 列出当前持有的所有锁，按表名和事务 ID 排序
+func (lm *LockManager) ListLocks() []LockInfo {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	result := make([]LockInfo, 0)
+	for table, lock := range lm.tableLocks {
+		lock.mu.Lock()
+		if lock.writer != 0 {
+			result = append(result, LockInfo{Table: table, TxID: lock.writer, Type: WriteLock})
+		}
+		for txID := range lock.readers {
+			if txID != lock.writer || lock.writer == 0 {
+				result = append(result, LockInfo{Table: table, TxID: txID, Type: ReadLock})
+			}
+		}
+		lock.mu.Unlock()
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Table != result[j].Table {
+			return result[i].Table < result[j].Table
+		}
+		return result[i].TxID < result[j].TxID
+	})
+	return result
+}
@@ -16,6 +16,7 @@ type TransactionManager struct {
 	activeTxs   map[TransactionID]*Transaction
 	lockManager *LockManager
 	pager       *storage.Pager
+	tempPager   *storage.Pager // 临时表使用的页管理器，nil 表示没有临时表
 	catalog     *catalog.Catalog
 }
 
@@ -30,6 +31,25 @@ func NewTransactionManager(pager *storage.Pager, catalog *catalog.Catalog) *Tran
 	}
 }
 
+// SetTempPager 设置临时表使用的页管理器（回滚临时表的修改时使用）
+func (tm *TransactionManager) SetTempPager(pager *storage.Pager) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.tempPager = pager
+}
+
+// pagerFor 返回表的行所在的页管理器
+func (tm *TransactionManager) pagerFor(schema *catalog.TableSchema) *storage.Pager {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if schema.Temporary && tm.tempPager != nil {
+		return tm.tempPager
+	}
+	return tm.pager
+}
+
 // Begin 开始新事务
 func (tm *TransactionManager) Begin() (*Transaction, error) {
 	tm.mu.Lock()
@@ -84,8 +104,9 @@ func (tm *TransactionManager) Commit(txID TransactionID) error {
 	return nil
 }
 
-// Rollback 回滚事务
-func (tm *TransactionManager) Rollback(txID TransactionID) error {
+// Rollback 回滚事务，undoIndexes 在每个操作回滚之后调用，撤销调用者维护的索引条目
+// （DDL 和数据操作按逆序交替回滚，索引也要在同样的顺序下撤销）
+func (tm *TransactionManager) Rollback(txID TransactionID, undoIndexes func(op *Operation)) error {
 	tm.mu.Lock()
 	tx, exists := tm.activeTxs[txID]
 	if !exists {
@@ -99,14 +120,7 @@ func (tm *TransactionManager) Rollback(txID TransactionID) error {
 	tm.mu.Unlock()
 
 	// 回滚所有操作（逆序执行）
-	operations := tx.GetOperations()
-	for i := len(operations) - 1; i >= 0; i-- {
-		op := operations[i]
-		if err := tm.rollbackOperation(op); err != nil {
-			// 记录错误但继续回滚其他操作
-			fmt.Printf("Warning: failed to rollback operation: %v\n", err)
-		}
-	}
+	tm.undoOperations(tx.GetOperations(), undoIndexes)
 
 	// 释放所有锁
 	tm.lockManager.ReleaseLocks(txID)
@@ -119,14 +133,59 @@ func (tm *TransactionManager) Rollback(txID TransactionID) error {
 	return nil
 }
 
+// RollbackTo 撤销事务中前 mark 个操作之后的操作，事务保持活跃、锁不释放
+// 用于事务中的一条语句失败时撤销它已经做的修改（例如触发器中的语句失败）
+func (tm *TransactionManager) RollbackTo(txID TransactionID, mark int, undoIndexes func(op *Operation)) error {
+	tm.mu.RLock()
+	tx, exists := tm.activeTxs[txID]
+	tm.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("transaction %d not found", txID)
+	}
+
+	operations := tx.GetOperations()
+	if mark >= len(operations) {
+		return nil
+	}
+	tm.undoOperations(operations[mark:], undoIndexes)
+	tx.TruncateOperations(mark)
+
+	if err := tm.pager.FlushAll(); err != nil {
+		return fmt.Errorf("failed to flush pages after rollback: %w", err)
+	}
+	return nil
+}
+
+// undoOperations 逆序撤销操作
+func (tm *TransactionManager) undoOperations(operations []*Operation, undoIndexes func(op *Operation)) {
+	for i := len(operations) - 1; i >= 0; i-- {
+		op := operations[i]
+		if err := tm.rollbackOperation(op); err != nil {
+			// 记录错误但继续回滚其他操作
+			fmt.Printf("Warning: failed to rollback operation: %v\n", err)
+		}
+		if undoIndexes != nil {
+			undoIndexes(op)
+		}
+	}
+}
+
 // rollbackOperation 回滚单个操作
 func (tm *TransactionManager) rollbackOperation(op *Operation) error {
+	switch op.Type {
+	case OpCreateTable, OpDropTable, OpCreateIndex, OpDropIndex:
+		if op.Undo == nil {
+			return fmt.Errorf("no undo for DDL operation on table %s", op.TableName)
+		}
+		return op.Undo()
+	}
+
 	schema, err := tm.catalog.GetTable(op.TableName)
 	if err != nil {
 		return err
 	}
 
-	tableStorage, err := catalog.CreateTableStorage(tm.pager, schema)
+	tableStorage, err := catalog.CreateTableStorage(tm.pagerFor(schema), schema)
 	if err != nil {
 		return err
 	}
@@ -159,7 +218,7 @@ func (tm *TransactionManager) rollbackOperation(op *Operation) error {
 
 // unmarkRowDeleted 取消行的删除标记
 func (tm *TransactionManager) unmarkRowDeleted(tableStorage *storage.TableStorage, rowID storage.RowID) error {
-	page, err := tm.pager.GetPage(rowID.PageID)
+	page, err := tableStorage.GetPager().GetPage(rowID.PageID)
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,20 @@ const (
 	TxAborted                          // 已中止
 )
 
+// String 返回事务状态名
+func (s TransactionStatus) String() string {
+	switch s {
+	case TxActive:
+		return "ACTIVE"
+	case TxCommitted:
+		return "COMMITTED"
+	case TxAborted:
+		return "ABORTED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
 // OperationType 操作类型
 type OperationType int
 
@@ -25,6 +39,10 @@ const (
 	OpInsert OperationType = iota
 	OpUpdate
 	OpDelete
+	OpCreateTable
+	OpDropTable
+	OpCreateIndex
+	OpDropIndex
 )
 
 // Operation 事务操作记录（用于回滚）
@@ -34,6 +52,7 @@ type Operation struct {
 	RowID     storage.RowID
 	OldData   *storage.Row // 用于回滚 UPDATE/DELETE
 	NewData   *storage.Row // INSERT/UPDATE 的新数据
+	Undo      func() error // 撤销 DDL 操作（元数据、内存中的索引和页由执行器恢复）
 }
 
 // Transaction 事务
@@ -52,6 +71,13 @@ func (tx *Transaction) AddOperation(op *Operation) {
 	tx.Operations = append(tx.Operations, op)
 }
 
+// TruncateOperations 删除第 n 个之后的操作记录（这些操作已经撤销）
+func (tx *Transaction) TruncateOperations(n int) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.Operations = tx.Operations[:n]
+}
+
 // GetOperations 获取所有操作（用于回滚）
 func (tx *Transaction) GetOperations() []*Operation {
 	tx.mu.Lock()
@@ -0,0 +1,204 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// implicitCasts 隐式转换表：源类型 -> 可以隐式转换到的目标类型
+// 比较和赋值时只允许这些转换，其余转换必须显式使用 CAST
+var implicitCasts = map[DataType][]DataType{
+	TypeInt:  {TypeFloat},
+	TypeText: {TypeDate, TypeBoolean, TypeUUID}, // 字符串字面量
+}
+
+// dateFormats 支持的日期字面量格式
+var dateFormats = []string{"2006-01-02", "2006-01-02 15:04:05"}
+
+// CanImplicitCast 判断是否允许从 from 隐式转换到 to
+func CanImplicitCast(from, to DataType) bool {
+	if from == to {
+		return true
+	}
+	for _, target := range implicitCasts[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ImplicitCast 隐式转换（用于赋值），不在隐式转换表中的转换返回错误
+// NULL 可以赋值给任意类型
+func ImplicitCast(v Value, to DataType) (Value, error) {
+	if v.Type == to || v.IsNull() {
+		return v, nil
+	}
+	if !CanImplicitCast(v.Type, to) {
+		return Value{}, fmt.Errorf("type mismatch: expected %s, got %s", to, v.Type)
+	}
+	return Cast(v, to)
+}
+
+// CommonType 返回比较两个类型的值时统一转换到的类型
+func CommonType(a, b DataType) (DataType, error) {
+	if a == b {
+		return a, nil
+	}
+	if CanImplicitCast(a, b) {
+		return b, nil
+	}
+	if CanImplicitCast(b, a) {
+		return a, nil
+	}
+	return 0, fmt.Errorf("type mismatch in comparison: %s and %s", a, b)
+}
+
+// CoerceForComparison 把两个值转换到共同类型以便比较
+// 任意一侧是 NULL 时原样返回
+func CoerceForComparison(left, right Value) (Value, Value, error) {
+	if left.IsNull() || right.IsNull() {
+		return left, right, nil
+	}
+
+	common, err := CommonType(left.Type, right.Type)
+	if err != nil {
+		return Value{}, Value{}, err
+	}
+
+	left, err = Cast(left, common)
+	if err != nil {
+		return Value{}, Value{}, err
+	}
+	right, err = Cast(right, common)
+	if err != nil {
+		return Value{}, Value{}, err
+	}
+	return left, right, nil
+}
+
+// Cast 显式类型转换（CAST），NULL 转换后仍是 NULL
+func Cast(v Value, to DataType) (Value, error) {
+	if v.Type == to || v.IsNull() {
+		return v, nil
+	}
+
+	switch to {
+	case TypeText:
+		return castToText(v)
+	case TypeInt:
+		return castToInt(v)
+	case TypeFloat:
+		return castToFloat(v)
+	case TypeBoolean:
+		return castToBoolean(v)
+	case TypeDate:
+		if v.Type == TypeText {
+			date, err := ParseDate(v.Data.(string))
+			if err != nil {
+				return Value{}, err
+			}
+			return NewDateValue(date), nil
+		}
+	case TypeUUID:
+		if v.Type == TypeText {
+			uuidVal, err := ParseUUID(v.Data.(string))
+			if err != nil {
+				return Value{}, err
+			}
+			return NewUUIDValue(uuidVal), nil
+		}
+	}
+
+	return Value{}, fmt.Errorf("cannot cast %s to %s", v.Type, to)
+}
+
+func castToText(v Value) (Value, error) {
+	switch v.Type {
+	case TypeFloat:
+		return NewTextValue(strconv.FormatFloat(v.Data.(float64), 'f', -1, 64)), nil
+	case TypeInt, TypeBoolean, TypeDate, TypeUUID:
+		return NewTextValue(v.String()), nil
+	default:
+		return Value{}, fmt.Errorf("cannot cast %s to %s", v.Type, TypeText)
+	}
+}
+
+func castToInt(v Value) (Value, error) {
+	switch v.Type {
+	case TypeFloat:
+		f := math.Round(v.Data.(float64))
+		if f < math.MinInt64 || f >= math.MaxInt64 || math.IsNaN(f) {
+			return Value{}, fmt.Errorf("value out of range for %s: %v", TypeInt, v.Data)
+		}
+		return NewIntValue(int64(f)), nil
+	case TypeText:
+		intVal, err := strconv.ParseInt(strings.TrimSpace(v.Data.(string)), 10, 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("invalid input for %s: '%s'", TypeInt, v.Data)
+		}
+		return NewIntValue(intVal), nil
+	case TypeBoolean:
+		if v.Data.(bool) {
+			return NewIntValue(1), nil
+		}
+		return NewIntValue(0), nil
+	default:
+		return Value{}, fmt.Errorf("cannot cast %s to %s", v.Type, TypeInt)
+	}
+}
+
+func castToFloat(v Value) (Value, error) {
+	switch v.Type {
+	case TypeInt:
+		return NewFloatValue(float64(v.Data.(int64))), nil
+	case TypeText:
+		floatVal, err := strconv.ParseFloat(strings.TrimSpace(v.Data.(string)), 64)
+		if err != nil {
+			return Value{}, fmt.Errorf("invalid input for %s: '%s'", TypeFloat, v.Data)
+		}
+		return NewFloatValue(floatVal), nil
+	default:
+		return Value{}, fmt.Errorf("cannot cast %s to %s", v.Type, TypeFloat)
+	}
+}
+
+func castToBoolean(v Value) (Value, error) {
+	switch v.Type {
+	case TypeInt:
+		return NewBooleanValue(v.Data.(int64) != 0), nil
+	case TypeText:
+		boolVal, err := ParseBoolean(v.Data.(string))
+		if err != nil {
+			return Value{}, err
+		}
+		return NewBooleanValue(boolVal), nil
+	default:
+		return Value{}, fmt.Errorf("cannot cast %s to %s", v.Type, TypeBoolean)
+	}
+}
+
+// ParseBoolean 解析布尔字面量（true/false/t/f/yes/no/on/off/1/0，不区分大小写）
+func ParseBoolean(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t", "yes", "y", "on", "1":
+		return true, nil
+	case "false", "f", "no", "n", "off", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean value: %s", s)
+	}
+}
+
+// ParseDate 解析日期字面量（YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS）
+func ParseDate(s string) (time.Time, error) {
+	for _, layout := range dateFormats {
+		if date, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date format: %s", s)
+}
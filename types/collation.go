@@ -0,0 +1,184 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Collation 文本排序规则，决定 TEXT 值的比较和排序方式
+type Collation string
+
+const (
+	CollationBinary  Collation = "binary"  // 按字节比较（即按 Unicode 码点），区分大小写
+	CollationNoCase  Collation = "nocase"  // 忽略大小写，其余按码点比较
+	CollationUnicode Collation = "unicode" // Unicode 排序算法（中文按拼音），忽略大小写
+)
+
+// collationAliases 排序规则别名（兼容 MySQL 的写法）
+var collationAliases = map[string]Collation{
+	"binary":             CollationBinary,
+	"bin":                CollationBinary,
+	"utf8mb4_bin":        CollationBinary,
+	"nocase":             CollationNoCase,
+	"ci":                 CollationNoCase,
+	"utf8mb4_general_ci": CollationNoCase,
+	"unicode":            CollationUnicode,
+	"utf8mb4_unicode_ci": CollationUnicode,
+	"utf8mb4_0900_ai_ci": CollationUnicode,
+	"zh":                 CollationUnicode,
+}
+
+// unicodeCollator collate.Collator 不是并发安全的，使用时需要加锁
+var (
+	unicodeCollator = collate.New(language.Chinese, collate.IgnoreCase)
+	unicodeMu       sync.Mutex
+)
+
+// ParseCollation 解析排序规则名（不区分大小写）
+func ParseCollation(name string) (Collation, error) {
+	if c, ok := collationAliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("unsupported collation: %s", name)
+}
+
+// Compare 按排序规则比较两个字符串
+// 返回：-1 (a < b), 0 (a == b), 1 (a > b)
+func (c Collation) Compare(a, b string) int {
+	switch c {
+	case CollationNoCase:
+		return compareFold(a, b)
+	case CollationUnicode:
+		unicodeMu.Lock()
+		defer unicodeMu.Unlock()
+		return unicodeCollator.CompareString(a, b)
+	default:
+		// 旧版本元数据中未记录排序规则的列按 binary 处理
+		return strings.Compare(a, b)
+	}
+}
+
+// Key 返回字符串在排序规则下的键：按排序规则相等的字符串键相同（用于哈希）
+func (c Collation) Key(s string) []byte {
+	switch c {
+	case CollationNoCase:
+		return []byte(strings.Map(foldRune, s))
+	case CollationUnicode:
+		unicodeMu.Lock()
+		defer unicodeMu.Unlock()
+		var buf collate.Buffer
+		return append([]byte(nil), unicodeCollator.KeyFromString(&buf, s)...)
+	default:
+		return []byte(s)
+	}
+}
+
+// String 返回排序规则名，未设置时为 binary
+func (c Collation) String() string {
+	if c == "" {
+		return string(CollationBinary)
+	}
+	return string(c)
+}
+
+// compareFold 逐字符按 Unicode 简单大小写折叠比较
+func compareFold(a, b string) int {
+	for a != "" && b != "" {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		ra, rb = foldRune(ra), foldRune(rb)
+		if ra != rb {
+			if ra < rb {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+// foldRune 返回字符在大小写折叠等价类中的代表（取小写形式）
+func foldRune(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// Compare 比较两个相同类型的值，TEXT 按排序规则比较
+// 返回：-1 (v1 < v2), 0 (v1 == v2), 1 (v1 > v2)；NULL 排在最前面，其余类型不同时返回 0
+func Compare(v1, v2 Value, coll Collation) int {
+	if v1.IsNull() || v2.IsNull() {
+		switch {
+		case v1.IsNull() && v2.IsNull():
+			return 0
+		case v1.IsNull():
+			return -1
+		default:
+			return 1
+		}
+	}
+	if v1.Type != v2.Type {
+		return 0
+	}
+
+	switch v1.Type {
+	case TypeInt:
+		return compareOrdered(v1.Data.(int64), v2.Data.(int64))
+
+	case TypeText:
+		return coll.Compare(v1.Data.(string), v2.Data.(string))
+
+	case TypeFloat:
+		return compareOrdered(v1.Data.(float64), v2.Data.(float64))
+
+	case TypeDate:
+		left, _ := v1.AsDate()
+		right, _ := v2.AsDate()
+		return left.Compare(right)
+
+	case TypeBoolean:
+		left, _ := v1.AsBoolean()
+		right, _ := v2.AsBoolean()
+		if !left && right {
+			return -1
+		} else if left && !right {
+			return 1
+		}
+		return 0
+
+	case TypeUUID:
+		left, _ := v1.AsUUID()
+		right, _ := v2.AsUUID()
+		return left.Compare(right)
+
+	case TypeEnum:
+		// 枚举按声明顺序（即序号）比较
+		left, _ := v1.AsEnum()
+		right, _ := v2.AsEnum()
+		return compareOrdered(left, right)
+	}
+
+	return 0
+}
+
+func compareOrdered[T int64 | float64 | uint16](a, b T) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
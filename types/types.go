@@ -16,6 +16,9 @@ const (
 	TypeBoolean
 	TypeFloat
 	TypeDate
+	TypeUUID
+	TypeEnum
+	TypeNull // SQL NULL，可以出现在任意类型的列中
 )
 
 func (t DataType) String() string {
@@ -30,6 +33,12 @@ func (t DataType) String() string {
 		return "FLOAT"
 	case TypeDate:
 		return "DATE"
+	case TypeUUID:
+		return "UUID"
+	case TypeEnum:
+		return "ENUM"
+	case TypeNull:
+		return "NULL"
 	default:
 		return "UNKNOWN"
 	}
@@ -38,7 +47,7 @@ func (t DataType) String() string {
 // Value 存储任意类型的值
 type Value struct {
 	Type DataType
-	Data interface{} // int64, string, bool, float64, time.Time
+	Data interface{} // int64, string, bool, float64, time.Time, UUID, uint16（枚举序号）
 }
 
 // NewIntValue 创建整数值
@@ -66,6 +75,26 @@ func NewDateValue(v time.Time) Value {
 	return Value{Type: TypeDate, Data: v}
 }
 
+// NewUUIDValue 创建 UUID 值
+func NewUUIDValue(v UUID) Value {
+	return Value{Type: TypeUUID, Data: v}
+}
+
+// NewEnumValue 创建枚举值（存储的是标签在类型中的序号）
+func NewEnumValue(ordinal uint16) Value {
+	return Value{Type: TypeEnum, Data: ordinal}
+}
+
+// NewNullValue 创建 NULL 值
+func NewNullValue() Value {
+	return Value{Type: TypeNull}
+}
+
+// IsNull 判断是否是 NULL
+func (v Value) IsNull() bool {
+	return v.Type == TypeNull
+}
+
 // AsInt 获取整数值
 func (v Value) AsInt() (int64, error) {
 	if v.Type != TypeInt {
@@ -106,6 +135,22 @@ func (v Value) AsDate() (time.Time, error) {
 	return v.Data.(time.Time), nil
 }
 
+// AsUUID 获取 UUID 值
+func (v Value) AsUUID() (UUID, error) {
+	if v.Type != TypeUUID {
+		return UUID{}, fmt.Errorf("value is not uuid, got %s", v.Type)
+	}
+	return v.Data.(UUID), nil
+}
+
+// AsEnum 获取枚举序号
+func (v Value) AsEnum() (uint16, error) {
+	if v.Type != TypeEnum {
+		return 0, fmt.Errorf("value is not enum, got %s", v.Type)
+	}
+	return v.Data.(uint16), nil
+}
+
 // Serialize 序列化为字节数组（用于存储）
 func (v Value) Serialize() ([]byte, error) {
 	buf := make([]byte, 1) // 第一个字节存储类型
@@ -147,6 +192,18 @@ func (v Value) Serialize() ([]byte, error) {
 		binary.LittleEndian.PutUint64(dateBuf, uint64(timestamp))
 		buf = append(buf, dateBuf...)
 
+	case TypeUUID:
+		uuidVal := v.Data.(UUID)
+		buf = append(buf, uuidVal[:]...)
+
+	case TypeEnum:
+		enumBuf := make([]byte, 2)
+		binary.LittleEndian.PutUint16(enumBuf, v.Data.(uint16))
+		buf = append(buf, enumBuf...)
+
+	case TypeNull:
+		// NULL 只存储类型字节
+
 	default:
 		return nil, fmt.Errorf("unsupported type: %s", v.Type)
 	}
@@ -154,6 +211,14 @@ func (v Value) Serialize() ([]byte, error) {
 	return buf, nil
 }
 
+// Key 返回值在排序规则下的键：按排序规则相等的值键相同（TEXT 按排序规则的键，其他类型按序列化的字节）
+func (v Value) Key(collation Collation) ([]byte, error) {
+	if v.Type == TypeText {
+		return collation.Key(v.Data.(string)), nil
+	}
+	return v.Serialize()
+}
+
 // Deserialize 从字节数组反序列化
 func Deserialize(data []byte) (Value, int, error) {
 	if len(data) < 1 {
@@ -206,6 +271,24 @@ func Deserialize(data []byte) (Value, int, error) {
 		dateVal := time.Unix(timestamp, 0)
 		return NewDateValue(dateVal), offset + 8, nil
 
+	case TypeUUID:
+		if len(data) < offset+16 {
+			return Value{}, 0, fmt.Errorf("data too short for uuid")
+		}
+		var uuidVal UUID
+		copy(uuidVal[:], data[offset:offset+16])
+		return NewUUIDValue(uuidVal), offset + 16, nil
+
+	case TypeEnum:
+		if len(data) < offset+2 {
+			return Value{}, 0, fmt.Errorf("data too short for enum")
+		}
+		ordinal := binary.LittleEndian.Uint16(data[offset : offset+2])
+		return NewEnumValue(ordinal), offset + 2, nil
+
+	case TypeNull:
+		return NewNullValue(), offset, nil
+
 	default:
 		return Value{}, 0, fmt.Errorf("unsupported type: %d", dataType)
 	}
@@ -224,6 +307,13 @@ func (v Value) String() string {
 		return fmt.Sprintf("%f", v.Data.(float64))
 	case TypeDate:
 		return v.Data.(time.Time).Format("2006-01-02")
+	case TypeUUID:
+		return v.Data.(UUID).String()
+	case TypeEnum:
+		// 标签需要结合列的枚举类型解析，这里只能输出序号
+		return fmt.Sprintf("%d", v.Data.(uint16))
+	case TypeNull:
+		return "NULL"
 	default:
 		return "UNKNOWN"
 	}
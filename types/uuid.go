@@ -0,0 +1,66 @@
+package types
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// UUID 16 字节的通用唯一标识符
+type UUID [16]byte
+
+// ParseUUID 解析 UUID 字面量
+// 支持标准格式 xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx（大小写均可）以及不带连字符的 32 位十六进制
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	hexStr := s
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("invalid uuid: %s", s)
+		}
+		hexStr = strings.ReplaceAll(s, "-", "")
+	}
+	if len(hexStr) != 32 {
+		return u, fmt.Errorf("invalid uuid: %s", s)
+	}
+
+	if _, err := hex.Decode(u[:], []byte(hexStr)); err != nil {
+		return u, fmt.Errorf("invalid uuid: %s", s)
+	}
+	return u, nil
+}
+
+// NewRandomUUID 生成随机 UUID（版本 4）
+func NewRandomUUID() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return u, fmt.Errorf("failed to generate uuid: %w", err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // 版本 4
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 变体
+	return u, nil
+}
+
+// String 返回标准格式（小写，带连字符）
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// Compare 按字节序比较两个 UUID
+// 返回：-1 (u < other), 0 (u == other), 1 (u > other)
+func (u UUID) Compare(other UUID) int {
+	return bytes.Compare(u[:], other[:])
+}